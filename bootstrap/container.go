@@ -1,49 +1,128 @@
 package bootstrap
 
 import (
+	"go-my-blog/config"
+	"go-my-blog/config/priority_config"
 	"go-my-blog/internal/handler"
 	"go-my-blog/internal/repo"
 	"go-my-blog/internal/service"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/storage"
+	"go-my-blog/pkg/theme"
 
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type Container struct {
 	DB *gorm.DB
 
+	// 媒体文件存储
+	MediaStorage storage.Storage
+
 	// 仓库层
-	UserRepo    *repo.UserRepository
-	PostRepo    *repo.PostRepository
-	CommentRepo *repo.CommentRepository
+	UserRepo     *repo.UserRepository
+	PostRepo     *repo.PostRepository
+	CommentRepo  *repo.CommentRepository
+	TagRepo      *repo.TagRepository
+	SpamRepo     *repo.SpamRepository
+	MediaRepo    *repo.MediaRepository
+	RedirectRepo *repo.RedirectRepository
+	BackupRepo   *repo.BackupRepository
+	ExportRepo   *repo.DataExportRepository
+	AuditRepo    *repo.AuditRepository
+	TrashRepo    *repo.TrashRepository
+	// 跨仓库的事务
+	UnitOfWork *repo.UnitOfWork
 
 	// 服务层
-	UserService    *service.UserSevice
-	PostService    *service.PostService
-	CommentService *service.CommentService
+	UserService     *service.UserSevice
+	PostService     *service.PostService
+	CommentService  *service.CommentService
+	SpamService     *service.SpamService
+	MediaService    *service.MediaService
+	FeedService     *service.FeedService
+	SitemapService  *service.SitemapService
+	FrontendService *service.FrontendService
+	ImportService   *service.ImportService
+	RedirectService *service.RedirectService
+	BackupService   *service.BackupService
+	AccountService  *service.AccountService
+	TrashService    *service.TrashService
 
 	// 处理器层
-	UserHandler    *handler.UserHandler
-	PostHandler    *handler.PostHandler
-	CommentHandler *handler.CommentHandler
+	UserHandler     *handler.UserHandler
+	PostHandler     *handler.PostHandler
+	CommentHandler  *handler.CommentHandler
+	MediaHandler    *handler.MediaHandler
+	FeedHandler     *handler.FeedHandler
+	SitemapHandler  *handler.SitemapHandler
+	RedirectHandler *handler.RedirectHandler
+	BackupHandler   *handler.BackupHandler
+	AccountHandler  *handler.AccountHandler
+	TrashHandler    *handler.TrashHandler
+	// HTML 前台未开启时为 nil
+	FrontendHandler *handler.FrontendHandler
 }
 
 func NewContainer(db *gorm.DB) *Container {
 	c := &Container{DB: db}
 
+	mediaStorage, err := storage.New(config.Conf.Media)
+	if err != nil {
+		logger.Fatal("媒体存储初始化失败", zap.Error(err))
+	}
+	c.MediaStorage = mediaStorage
+
 	// 初始化仓库层
 	c.UserRepo = repo.NewUserRepository(db)
 	c.PostRepo = repo.NewPostRepository(db)
 	c.CommentRepo = repo.NewCommentRepository(db)
+	c.TagRepo = repo.NewTagRepository(db)
+	c.SpamRepo = repo.NewSpamRepository(db)
+	c.MediaRepo = repo.NewMediaRepository(db)
+	c.RedirectRepo = repo.NewRedirectRepository(db)
+	c.BackupRepo = repo.NewBackupRepository(db)
+	c.ExportRepo = repo.NewDataExportRepository(db)
+	c.AuditRepo = repo.NewAuditRepository(db)
+	c.TrashRepo = repo.NewTrashRepository(db)
+	c.UnitOfWork = repo.NewUnitOfWork(db)
 
 	// 初始化服务层
 	c.UserService = service.NewUserService(c.UserRepo)
-	c.PostService = service.NewPostService(c.PostRepo, c.UserRepo, c.CommentRepo)
-	c.CommentService = service.NewCommentService(c.CommentRepo, c.UserRepo, c.PostRepo)
+	c.PostService = service.NewPostService(c.PostRepo, c.UserRepo, c.CommentRepo, c.TagRepo, c.MediaRepo, c.MediaStorage, c.UnitOfWork)
+	c.SpamService = service.NewSpamService(c.SpamRepo, c.CommentRepo)
+	c.CommentService = service.NewCommentService(c.CommentRepo, c.UserRepo, c.PostRepo, c.SpamService, c.UnitOfWork)
+	c.MediaService = service.NewMediaService(c.MediaRepo, c.UserRepo, c.MediaStorage)
+	c.FeedService = service.NewFeedService(c.PostRepo, c.UserRepo, c.MediaService)
+	c.SitemapService = service.NewSitemapService(c.PostRepo)
+	c.PostService.OnPublish(c.SitemapService.PingSearchEngines)
+	c.FrontendService = service.NewFrontendService(c.PostService, c.PostRepo, c.UserRepo)
+	c.ImportService = service.NewImportService(c.PostService, c.UserRepo, c.UnitOfWork)
+	c.RedirectService = service.NewRedirectService(c.RedirectRepo)
+	c.BackupService = service.NewBackupService(c.BackupRepo, c.UserRepo, c.MediaStorage, c.UnitOfWork)
+	c.AccountService = service.NewAccountService(c.UserRepo, c.PostRepo, c.CommentRepo, c.ExportRepo, c.MediaStorage, c.UnitOfWork)
+	c.TrashService = service.NewTrashService(c.TrashRepo, c.UserRepo)
 
 	// 初始化处理器层
 	c.UserHandler = handler.NewUserHandler(c.UserService)
 	c.PostHandler = handler.NewPostHandler(c.PostService)
 	c.CommentHandler = handler.NewCommentHandler(c.CommentService)
+	c.MediaHandler = handler.NewMediaHandler(c.MediaService)
+	c.FeedHandler = handler.NewFeedHandler(c.FeedService)
+	c.SitemapHandler = handler.NewSitemapHandler(c.SitemapService)
+	c.RedirectHandler = handler.NewRedirectHandler(c.RedirectService)
+	c.BackupHandler = handler.NewBackupHandler(c.BackupService)
+	c.AccountHandler = handler.NewAccountHandler(c.AccountService)
+	c.TrashHandler = handler.NewTrashHandler(c.TrashService)
+	if config.Conf.Frontend.Enabled {
+		// 调试模式下每次请求重新加载模板，便于开发主题
+		siteTheme, err := theme.New(config.Conf.Frontend.ThemeDir, priority_config.PriorityConf.Gin.Debug)
+		if err != nil {
+			logger.Fatal("主题加载失败", zap.String("theme_dir", config.Conf.Frontend.ThemeDir), zap.Error(err))
+		}
+		c.FrontendHandler = handler.NewFrontendHandler(c.FrontendService, siteTheme)
+	}
 
 	return c
 }
@@ -0,0 +1,37 @@
+package bootstrap
+
+import (
+	"context"
+	"go-my-blog/internal/model"
+	"go-my-blog/internal/repo"
+	"go-my-blog/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AutoMigrate 根据模型自动迁移表结构（只新增表/字段/索引，不会删除已有列）
+// 参数:
+//   - db: 数据库连接对象
+func AutoMigrate(db *gorm.DB) {
+	err := db.AutoMigrate(
+		&model.User{},
+		&model.Post{},
+		&model.Tag{},
+		&model.Comment{},
+		&model.CommentRevision{},
+		&model.SpamToken{},
+		&model.Media{},
+		&model.Redirect{},
+		&model.DataExport{},
+		&model.AuditLog{},
+	)
+	if err != nil {
+		logger.Fatal("数据库表结构迁移失败", zap.Error(err))
+	}
+	// 历史根评论回填物化路径，保证楼中楼查询可用
+	if err = repo.NewCommentRepository(db).BackfillRootPaths(context.Background()); err != nil {
+		logger.Fatal("评论物化路径回填失败", zap.Error(err))
+	}
+	logger.Info("数据库表结构迁移完成")
+}
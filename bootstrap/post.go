@@ -11,7 +11,7 @@ import (
 func InitPostModule(db *gorm.DB) *handler.PostHandler {
 	postRepository := repo.NewPostRepository(db)
 
-	postService := service.NewPostService(postRepository, nil, nil)
+	postService := service.NewPostService(postRepository, nil, nil, repo.NewTagRepository(db), repo.NewMediaRepository(db), nil, repo.NewUnitOfWork(db))
 
 	return handler.NewPostHandler(postService)
 }
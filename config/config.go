@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"go-my-blog/pkg/logger"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -18,7 +20,20 @@ type AppConfig struct {
 	//Log    LogConfig    `mapstructure:"log"`
 	// 不在这里读取GinConfig
 	//Gin GinConfig `mapstructure:"gin"`
-	JWT JWTConfig `mapstructure:"jwt"`
+	JWT      JWTConfig      `mapstructure:"jwt"`
+	Comment  CommentConfig  `mapstructure:"comment"`
+	Spam     SpamConfig     `mapstructure:"spam"`
+	Guest    GuestConfig    `mapstructure:"guest"`
+	Post     PostConfig     `mapstructure:"post"`
+	Media    MediaConfig    `mapstructure:"media"`
+	Site     SiteConfig     `mapstructure:"site"`
+	Feed     FeedConfig     `mapstructure:"feed"`
+	Sitemap  SitemapConfig  `mapstructure:"sitemap"`
+	Robots   RobotsConfig   `mapstructure:"robots"`
+	Frontend FrontendConfig `mapstructure:"frontend"`
+	Import   ImportConfig   `mapstructure:"import"`
+	Account  AccountConfig  `mapstructure:"account"`
+	Trash    TrashConfig    `mapstructure:"trash"`
 }
 
 type MysqlConfig struct {
@@ -30,7 +45,16 @@ type MysqlConfig struct {
 }
 
 type ServerConfig struct {
-	Port int `mapstructure:"port"`
+	Port            int                  `mapstructure:"port"`
+	RequestTimeoutS int                  `mapstructure:"request_timeout_s"` // 请求超时（秒），超时后取消正在执行的查询；负数表示不限制
+	RouteTimeouts   []RouteTimeoutConfig `mapstructure:"route_timeouts"`    // 按路由覆盖请求超时，如上传、下载需要更长时间
+	ProblemDetails  bool                 `mapstructure:"problem_details"`   // 错误响应始终使用 RFC 7807 格式（application/problem+json）；否则仅在请求 Accept 该类型时使用
+}
+
+type RouteTimeoutConfig struct {
+	Method   string `mapstructure:"method"`
+	Path     string `mapstructure:"path"`      // 路由模板，如 /api/v2/me/exports/:id/download
+	TimeoutS int    `mapstructure:"timeout_s"` // 负数表示不限制，0 表示使用全局超时
 }
 
 //type LogConfig struct {
@@ -48,6 +72,130 @@ type JWTConfig struct {
 	ExpireHour int    `mapstructure:"expire_hour"` // Token 有效期（小时）
 }
 
+// CommentConfig 评论配置结构体
+type CommentConfig struct {
+	MaxDepth          int    `mapstructure:"max_depth"`           // 回复最大嵌套层数（根评论算第 1 层）
+	EditWindowMinutes int    `mapstructure:"edit_window_minutes"` // 作者可编辑评论的时间窗口（分钟）
+	Policy            string `mapstructure:"policy"`              // 全局评论策略（open/moderated/closed），文章可单独覆盖
+	AutoCloseDays     int    `mapstructure:"auto_close_days"`     // 文章发布 N 天后自动关闭评论（0 表示不自动关闭）
+}
+
+// SpamConfig 垃圾评论过滤配置结构体
+type SpamConfig struct {
+	Enabled                bool     `mapstructure:"enabled"`                  // 是否开启垃圾评论过滤
+	MaxLinks               int      `mapstructure:"max_links"`                // 单条评论允许的最大链接数，超过视为垃圾特征
+	BlockedWords           []string `mapstructure:"blocked_words"`            // 屏蔽词（不区分大小写）
+	BlockedIPs             []string `mapstructure:"blocked_ips"`              // 屏蔽IP，支持 CIDR 如 10.0.0.0/8
+	BlockedEmails          []string `mapstructure:"blocked_emails"`           // 屏蔽邮箱，@example.com 表示整个域名
+	DuplicateWindowMinutes int      `mapstructure:"duplicate_window_minutes"` // 重复评论检测的时间窗口（分钟）
+	ModerateThreshold      float64  `mapstructure:"moderate_threshold"`       // 得分达到该值进入审核队列
+	RejectThreshold        float64  `mapstructure:"reject_threshold"`         // 得分达到该值直接判为垃圾评论
+	BayesMinDocs           int64    `mapstructure:"bayes_min_docs"`           // 垃圾/正常样本都达到该数量后才启用贝叶斯打分
+}
+
+// GuestConfig 游客评论配置结构体
+type GuestConfig struct {
+	Enabled             bool   `mapstructure:"enabled"`               // 是否允许游客评论
+	Secret              string `mapstructure:"secret"`                // 工作量证明题目签名密钥，为空时使用 JWT 密钥
+	Difficulty          int    `mapstructure:"difficulty"`            // 工作量证明难度（前导零比特数）
+	ChallengeTTLMinutes int    `mapstructure:"challenge_ttl_minutes"` // 题目有效期（分钟）
+}
+
+// PostConfig 文章配置结构体
+type PostConfig struct {
+	ExcerptLength     int `mapstructure:"excerpt_length"`       // 自动摘要长度（字符数），文章含 <!--more--> 时以标记为准
+	CJKCharsPerMinute int `mapstructure:"cjk_chars_per_minute"` // 中日韩文字阅读速度（字/分钟）
+	WordsPerMinute    int `mapstructure:"words_per_minute"`     // 英文等拉丁文字阅读速度（词/分钟）
+}
+
+// MediaConfig 媒体库配置结构体
+type MediaConfig struct {
+	Storage         string           `mapstructure:"storage"`          // 存储类型（local/s3）
+	MaxSizeMB       int              `mapstructure:"max_size_mb"`      // 单个文件大小上限（MB）
+	MaxPixels       int              `mapstructure:"max_pixels"`       // 单张图片像素数上限（宽×高），防止解压炸弹
+	AllowedTypes    []string         `mapstructure:"allowed_types"`    // 允许上传的 MIME 类型（按文件内容识别）
+	ThumbnailWidths []int            `mapstructure:"thumbnail_widths"` // 缩略图宽度列表（像素）
+	JPEGQuality     int              `mapstructure:"jpeg_quality"`     // JPEG 重新编码质量（1~100）
+	Local           LocalMediaConfig `mapstructure:"local"`
+	S3              S3MediaConfig    `mapstructure:"s3"`
+}
+
+// LocalMediaConfig 本地存储配置
+type LocalMediaConfig struct {
+	Root      string `mapstructure:"root"`       // 文件存放目录
+	URLPrefix string `mapstructure:"url_prefix"` // 静态文件路由前缀
+	BaseURL   string `mapstructure:"base_url"`   // 对外访问前缀，为空时使用 url_prefix
+}
+
+// S3MediaConfig S3 兼容存储配置
+type S3MediaConfig struct {
+	Endpoint      string `mapstructure:"endpoint"`
+	Region        string `mapstructure:"region"`
+	Bucket        string `mapstructure:"bucket"`
+	AccessKey     string `mapstructure:"access_key"`
+	SecretKey     string `mapstructure:"secret_key"`
+	PathStyle     bool   `mapstructure:"path_style"`
+	PublicBaseURL string `mapstructure:"public_base_url"`
+}
+
+// SiteConfig 站点信息配置结构体（用于分享卡片、订阅源、站点地图等需要绝对地址的场景）
+type SiteConfig struct {
+	Name         string `mapstructure:"name"`          // 站点名称
+	Description  string `mapstructure:"description"`   // 站点简介
+	BaseURL      string `mapstructure:"base_url"`      // 站点对外访问地址，如 https://blog.example.com
+	Language     string `mapstructure:"language"`      // 站点语言，如 zh-CN
+	TwitterSite  string `mapstructure:"twitter_site"`  // 站点 Twitter 账号，如 @myblog
+	DefaultImage string `mapstructure:"default_image"` // 文章没有封面时使用的分享图地址
+}
+
+// FeedConfig 订阅源配置结构体
+type FeedConfig struct {
+	Limit int    `mapstructure:"limit"` // 订阅源包含的最新文章数
+	Mode  string `mapstructure:"mode"`  // 默认输出模式（full 全文 / excerpt 摘要），可用 ?mode= 覆盖
+}
+
+// SitemapConfig 站点地图配置结构体
+type SitemapConfig struct {
+	MaxURLs      int      `mapstructure:"max_urls"`       // 单个 sitemap 文件的 URL 上限（协议上限 50000），超出后拆分并输出 sitemap 索引
+	PingURLs     []string `mapstructure:"ping_urls"`      // 文章发布后通知的地址，{sitemap} 会被替换为转义后的站点地图地址
+	PingTimeoutS int      `mapstructure:"ping_timeout_s"` // 通知请求超时（秒）
+}
+
+// RobotsConfig robots.txt 配置结构体
+type RobotsConfig struct {
+	DisallowAll bool     `mapstructure:"disallow_all"` // 禁止所有爬虫（测试/预发布环境使用）
+	Allow       []string `mapstructure:"allow"`
+	Disallow    []string `mapstructure:"disallow"`
+	Extra       []string `mapstructure:"extra"` // 原样追加到文件末尾的行
+}
+
+// FrontendConfig 服务端渲染 HTML 前台配置结构体
+type FrontendConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`   // 是否开启 HTML 前台
+	ThemeDir string `mapstructure:"theme_dir"` // 主题目录，为空使用内置默认主题；缺少的模板回退到默认主题
+	PageSize int    `mapstructure:"page_size"` // 列表页每页文章数
+}
+
+// ImportConfig 内容导入配置结构体
+type ImportConfig struct {
+	Author string `mapstructure:"author"` // 导入文章的默认作者用户名，可用 -author 覆盖
+}
+
+// AccountConfig 个人数据导出与账号注销配置结构体
+type AccountConfig struct {
+	ExportDir         string `mapstructure:"export_dir"`          // 个人数据归档的保存目录（不对外公开，只能通过接口下载）
+	ExportTTLHours    int    `mapstructure:"export_ttl_hours"`    // 归档生成后的保留时长（小时），过期后删除
+	DeletionGraceDays int    `mapstructure:"deletion_grace_days"` // 申请注销后的冷静期（天），期间可撤销
+	AnonymousName     string `mapstructure:"anonymous_name"`      // 匿名化后评论显示的昵称
+	PurgeIntervalM    int    `mapstructure:"purge_interval_m"`    // 后台清理到期账号和过期归档的间隔（分钟）
+}
+
+// TrashConfig 回收站配置结构体
+type TrashConfig struct {
+	RetentionDays  int `mapstructure:"retention_days"`   // 软删除的文章和评论在回收站中保留的天数，过期后永久删除
+	PurgeIntervalM int `mapstructure:"purge_interval_m"` // 后台清理过期回收站内容的间隔（分钟）
+}
+
 func Init() {
 	// 读取配置文件
 	viper.SetConfigFile("config/app.dev.yml")
@@ -62,6 +210,18 @@ func Init() {
 
 	// 验证配置
 	validateMysqlConfig()
+	validateServerConfig()
+	validateCommentConfig()
+	validateSpamConfig()
+	validateGuestConfig()
+	validatePostConfig()
+	validateMediaConfig()
+	validateSiteConfig()
+	validateFeedConfig()
+	validateSitemapConfig()
+	validateFrontendConfig()
+	validateAccountConfig()
+	validateTrashConfig()
 	logger.Info("配置初始化完成", zap.Any("mysql_config", Conf.Mysql))
 }
 
@@ -75,6 +235,234 @@ func validateMysqlConfig() {
 	}
 }
 
+func validateServerConfig() {
+	if Conf.Server.RequestTimeoutS == 0 {
+		Conf.Server.RequestTimeoutS = 30
+	}
+}
+
+func validateCommentConfig() {
+	if Conf.Comment.MaxDepth <= 0 {
+		Conf.Comment.MaxDepth = 5
+		logger.Warn("评论最大嵌套层数未配置或小于等于0，已设置为默认值5")
+	}
+	// 物化路径字段为 varchar(255)，每层占 11 个字符，超过 23 层会被截断
+	if Conf.Comment.MaxDepth > 20 {
+		Conf.Comment.MaxDepth = 20
+		logger.Warn("评论最大嵌套层数不能超过20，已设置为20")
+	}
+	if Conf.Comment.EditWindowMinutes <= 0 {
+		Conf.Comment.EditWindowMinutes = 15
+		logger.Warn("评论编辑时间窗口未配置或小于等于0，已设置为默认值15分钟")
+	}
+	switch Conf.Comment.Policy {
+	case "open", "moderated", "closed":
+	default:
+		logger.Warn("评论策略配置无效，已设置为默认值open", zap.String("invalid_policy", Conf.Comment.Policy))
+		Conf.Comment.Policy = "open"
+	}
+	if Conf.Comment.AutoCloseDays < 0 {
+		Conf.Comment.AutoCloseDays = 0
+		logger.Warn("评论自动关闭天数不能小于0，已设置为0（不自动关闭）")
+	}
+}
+
+func validateSpamConfig() {
+	if Conf.Spam.MaxLinks < 0 {
+		Conf.Spam.MaxLinks = 2
+		logger.Warn("最大链接数不能小于0，已设置为默认值2")
+	}
+	if Conf.Spam.DuplicateWindowMinutes <= 0 {
+		Conf.Spam.DuplicateWindowMinutes = 60
+	}
+	if Conf.Spam.ModerateThreshold <= 0 || Conf.Spam.ModerateThreshold > 1 {
+		Conf.Spam.ModerateThreshold = 0.5
+		logger.Warn("垃圾评论审核阈值无效，已设置为默认值0.5")
+	}
+	if Conf.Spam.RejectThreshold < Conf.Spam.ModerateThreshold || Conf.Spam.RejectThreshold > 1 {
+		Conf.Spam.RejectThreshold = 0.9
+		logger.Warn("垃圾评论拒绝阈值无效，已设置为默认值0.9")
+	}
+	if Conf.Spam.BayesMinDocs <= 0 {
+		Conf.Spam.BayesMinDocs = 20
+	}
+}
+
+func validateGuestConfig() {
+	if Conf.Guest.Secret == "" {
+		Conf.Guest.Secret = Conf.JWT.Secret
+	}
+	if Conf.Guest.Difficulty <= 0 || Conf.Guest.Difficulty > 32 {
+		Conf.Guest.Difficulty = 18
+		logger.Warn("工作量证明难度配置无效，已设置为默认值18")
+	}
+	if Conf.Guest.ChallengeTTLMinutes <= 0 {
+		Conf.Guest.ChallengeTTLMinutes = 10
+	}
+}
+
+func validatePostConfig() {
+	if Conf.Post.ExcerptLength <= 0 {
+		Conf.Post.ExcerptLength = 200
+		logger.Warn("文章摘要长度未配置或小于等于0，已设置为默认值200")
+	}
+	if Conf.Post.CJKCharsPerMinute <= 0 {
+		Conf.Post.CJKCharsPerMinute = 400
+	}
+	if Conf.Post.WordsPerMinute <= 0 {
+		Conf.Post.WordsPerMinute = 200
+	}
+}
+
+func validateMediaConfig() {
+	if Conf.Media.Storage == "" {
+		Conf.Media.Storage = "local"
+	}
+	if Conf.Media.MaxSizeMB <= 0 {
+		Conf.Media.MaxSizeMB = 10
+		logger.Warn("媒体文件大小上限未配置或小于等于0，已设置为默认值10MB")
+	}
+	if Conf.Media.MaxPixels <= 0 {
+		Conf.Media.MaxPixels = 50000000
+	}
+	if len(Conf.Media.AllowedTypes) == 0 {
+		Conf.Media.AllowedTypes = []string{"image/jpeg", "image/png", "image/gif"}
+	}
+	if Conf.Media.JPEGQuality <= 0 || Conf.Media.JPEGQuality > 100 {
+		Conf.Media.JPEGQuality = 90
+	}
+	if Conf.Media.Local.Root == "" {
+		Conf.Media.Local.Root = "./uploads"
+	}
+	if Conf.Media.Local.URLPrefix == "" {
+		Conf.Media.Local.URLPrefix = "/uploads"
+	}
+	if Conf.Media.Local.BaseURL == "" {
+		Conf.Media.Local.BaseURL = Conf.Media.Local.URLPrefix
+	}
+}
+
+func validateSiteConfig() {
+	if Conf.Site.Name == "" {
+		Conf.Site.Name = "My Blog"
+	}
+	if Conf.Site.BaseURL == "" {
+		Conf.Site.BaseURL = fmt.Sprintf("http://localhost:%d", Conf.Server.Port)
+		logger.Warn("站点地址未配置，已使用默认值", zap.String("base_url", Conf.Site.BaseURL))
+	}
+	Conf.Site.BaseURL = strings.TrimSuffix(Conf.Site.BaseURL, "/")
+	if Conf.Site.Language == "" {
+		Conf.Site.Language = "zh-CN"
+	}
+}
+
+func validateFeedConfig() {
+	if Conf.Feed.Limit <= 0 || Conf.Feed.Limit > 100 {
+		Conf.Feed.Limit = 20
+	}
+	if Conf.Feed.Mode != "full" && Conf.Feed.Mode != "excerpt" {
+		Conf.Feed.Mode = "full"
+	}
+}
+
+func validateSitemapConfig() {
+	if Conf.Sitemap.MaxURLs <= 0 || Conf.Sitemap.MaxURLs > 50000 {
+		Conf.Sitemap.MaxURLs = 50000
+	}
+	if Conf.Sitemap.PingTimeoutS <= 0 {
+		Conf.Sitemap.PingTimeoutS = 10
+	}
+}
+
+func validateFrontendConfig() {
+	if Conf.Frontend.PageSize <= 0 || Conf.Frontend.PageSize > 100 {
+		Conf.Frontend.PageSize = 10
+	}
+}
+
+func validateAccountConfig() {
+	if Conf.Account.ExportDir == "" {
+		Conf.Account.ExportDir = "data/exports"
+	}
+	if Conf.Account.ExportTTLHours <= 0 {
+		Conf.Account.ExportTTLHours = 72
+	}
+	if Conf.Account.DeletionGraceDays < 0 {
+		Conf.Account.DeletionGraceDays = 0
+	}
+	if Conf.Account.AnonymousName == "" {
+		Conf.Account.AnonymousName = "已注销用户"
+	}
+	if Conf.Account.PurgeIntervalM <= 0 {
+		Conf.Account.PurgeIntervalM = 60
+	}
+}
+
+func validateTrashConfig() {
+	if Conf.Trash.RetentionDays <= 0 {
+		Conf.Trash.RetentionDays = 30
+	}
+	if Conf.Trash.PurgeIntervalM <= 0 {
+		Conf.Trash.PurgeIntervalM = 60
+	}
+}
+
+// GetRequestTimeout 辅助方法：路由的请求超时，path 为路由模板；返回 0 表示不限制
+func (s *ServerConfig) GetRequestTimeout(method string, path string) time.Duration {
+	timeout := s.RequestTimeoutS
+	for _, route := range s.RouteTimeouts {
+		if route.TimeoutS != 0 && route.Path == path && strings.EqualFold(route.Method, method) {
+			timeout = route.TimeoutS
+			break
+		}
+	}
+	if timeout < 0 {
+		return 0
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+// GetExportTTL 辅助方法：个人数据归档的保留时长
+func (a *AccountConfig) GetExportTTL() time.Duration {
+	return time.Duration(a.ExportTTLHours) * time.Hour
+}
+
+// GetDeletionGrace 辅助方法：注销冷静期
+func (a *AccountConfig) GetDeletionGrace() time.Duration {
+	return time.Duration(a.DeletionGraceDays) * 24 * time.Hour
+}
+
+// GetRetention 辅助方法：回收站保留时长
+func (t *TrashConfig) GetRetention() time.Duration {
+	return time.Duration(t.RetentionDays) * 24 * time.Hour
+}
+
+// AbsoluteURL 辅助方法：将站内路径转为绝对地址，已是绝对地址的原样返回
+func (s *SiteConfig) AbsoluteURL(path string) string {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return s.BaseURL + path
+}
+
+// PostURL 辅助方法：文章页的绝对地址
+func (s *SiteConfig) PostURL(id uint) string {
+	return s.AbsoluteURL(fmt.Sprintf("/posts/%d", id))
+}
+
+// GetMaxSize 辅助方法：将 MB 转为字节数
+func (m *MediaConfig) GetMaxSize() int64 {
+	return int64(m.MaxSizeMB) << 20
+}
+
+// GetEditWindow 辅助方法：将分钟转为 time.Duration
+func (c *CommentConfig) GetEditWindow() time.Duration {
+	return time.Duration(c.EditWindowMinutes) * time.Minute
+}
+
 // GetConnMaxLifetime 辅助方法：将小时转为 time.Duration（供数据库连接池使用）
 func (m *MysqlConfig) GetConnMaxLifetime() time.Duration {
 	return time.Duration(m.ConnMaxLifetimeHour) * time.Hour
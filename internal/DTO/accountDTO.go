@@ -0,0 +1,69 @@
+package DTO
+
+import "time"
+
+// PersonalDataFormat 个人数据归档的格式标识，清单结构与整站归档相同
+const PersonalDataFormat = "go-my-blog-personal-data"
+
+// DataExportDTO 个人数据导出任务
+type DataExportDTO struct {
+	ID        uint
+	Status    string
+	Size      int64
+	Error     string
+	ExpiresAt string
+	CreatedAt string
+}
+
+// DeleteAccountDTO 申请注销账号
+type DeleteAccountDTO struct {
+	UserID      uint
+	Password    string // 需再次输入密码确认
+	CommentMode string // anonymize 匿名化保留评论 / delete 删除评论
+	IP          string
+}
+
+// AccountDeletionDTO 注销申请状态
+type AccountDeletionDTO struct {
+	ScheduledAt string
+	CommentMode string
+}
+
+// 以下为个人数据归档中的记录（JSON Lines）
+
+// AccountProfileDTO 个人资料（profile.json）
+type AccountProfileDTO struct {
+	ID        uint      `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Locale    string    `json:"locale"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AccountPostDTO 本人的文章（posts.jsonl，含草稿）
+type AccountPostDTO struct {
+	ID            uint      `json:"id"`
+	Title         string    `json:"title"`
+	Content       string    `json:"content"`
+	ContentFormat string    `json:"content_format"`
+	Status        string    `json:"status"`
+	Tags          []string  `json:"tags"`
+	URL           string    `json:"url"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// AccountCommentDTO 本人发表的评论（comments.jsonl）
+type AccountCommentDTO struct {
+	ID            uint       `json:"id"`
+	PostID        uint       `json:"post_id"`
+	ParentID      *uint      `json:"parent_id"`
+	Content       string     `json:"content"`
+	ContentFormat string     `json:"content_format"`
+	Status        string     `json:"status"`
+	IP            string     `json:"ip"`
+	EditedAt      *time.Time `json:"edited_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
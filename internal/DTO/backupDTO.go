@@ -0,0 +1,146 @@
+package DTO
+
+import "time"
+
+// 归档格式标识和版本：格式不兼容时递增版本号
+const (
+	BackupFormat  = "go-my-blog-archive"
+	BackupVersion = 1
+)
+
+// BackupManifestDTO 归档清单（manifest.json），位于归档第一个文件
+type BackupManifestDTO struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BackupUserDTO 用户（含密码哈希，恢复后可直接登录）
+type BackupUserDTO struct {
+	ID                    uint       `json:"id"`
+	Username              string     `json:"username"`
+	Password              string     `json:"password"`
+	Email                 string     `json:"email"`
+	Role                  string     `json:"role"`
+	Locale                string     `json:"locale,omitempty"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	ImportSource          string     `json:"import_source,omitempty"`
+	DeletionScheduledAt   *time.Time `json:"deletion_scheduled_at,omitempty"` // 冷静期中的注销申请，恢复后到期照常删除
+	DeletionCommentMode   string     `json:"deletion_comment_mode,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+	DeletedAt             *time.Time `json:"deleted_at,omitempty"`
+}
+
+// BackupMediaDTO 媒体元数据，文件本体以 media/<key> 保存在归档中
+type BackupMediaDTO struct {
+	ID         uint      `json:"id"`
+	UID        string    `json:"uid"`
+	UserID     uint      `json:"user_id"`
+	FileName   string    `json:"file_name"`
+	MimeType   string    `json:"mime_type"`
+	Size       int64     `json:"size"`
+	Width      int       `json:"width"`
+	Height     int       `json:"height"`
+	StorageKey string    `json:"storage_key"`
+	Thumbnails string    `json:"thumbnails"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// BackupTagDTO 标签
+type BackupTagDTO struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BackupPostDTO 文章；HTML 缓存、摘要、字数等派生字段恢复时重新生成
+type BackupPostDTO struct {
+	ID                   uint       `json:"id"`
+	Title                string     `json:"title"`
+	Content              string     `json:"content"`
+	ContentFormat        string     `json:"content_format"`
+	UserID               uint       `json:"user_id"`
+	Status               string     `json:"status"`
+	ViewCount            int64      `json:"view_count"`
+	CommentPolicy        string     `json:"comment_policy"`
+	CommentAutoCloseDays int        `json:"comment_auto_close_days"`
+	CoverMediaID         *uint      `json:"cover_media_id"`
+	SEOTitle             string     `json:"seo_title"`
+	SEODescription       string     `json:"seo_description"`
+	CanonicalURL         string     `json:"canonical_url"`
+	NoIndex              bool       `json:"no_index"`
+	Slug                 string     `json:"slug"`
+	SourcePath           string     `json:"source_path"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+	DeletedAt            *time.Time `json:"deleted_at,omitempty"`
+}
+
+// BackupPostTagDTO 文章与标签的关联
+type BackupPostTagDTO struct {
+	PostID uint `json:"post_id"`
+	TagID  uint `json:"tag_id"`
+}
+
+// BackupPostMediaDTO 文章与媒体的引用关系
+type BackupPostMediaDTO struct {
+	PostID  uint `json:"post_id"`
+	MediaID uint `json:"media_id"`
+}
+
+// BackupCommentDTO 评论；按 ID 升序导出，父评论总在子评论之前，物化路径恢复时重新计算
+type BackupCommentDTO struct {
+	ID            uint       `json:"id"`
+	PostID        uint       `json:"post_id"`
+	UserID        *uint      `json:"user_id"`
+	ParentID      *uint      `json:"parent_id"`
+	Content       string     `json:"content"`
+	ContentFormat string     `json:"content_format"`
+	ReplyCount    int        `json:"reply_count"`
+	IsDeleted     bool       `json:"is_deleted"`
+	Status        string     `json:"status"`
+	SpamScore     float64    `json:"spam_score"`
+	SpamLabel     string     `json:"spam_label,omitempty"` // 训练分类器时使用的标签，与 spam_tokens.jsonl 中的词频一致
+	IP            string     `json:"ip"`
+	GuestName     string     `json:"guest_name"`
+	GuestEmail    string     `json:"guest_email"`
+	GuestWebsite  string     `json:"guest_website"`
+	SourceID      string     `json:"source_id"`
+	EditedAt      *time.Time `json:"edited_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+}
+
+// BackupCommentRevisionDTO 评论历史版本
+type BackupCommentRevisionDTO struct {
+	ID            uint      `json:"id"`
+	CommentID     uint      `json:"comment_id"`
+	Content       string    `json:"content"`
+	ContentFormat string    `json:"content_format"`
+	EditorID      uint      `json:"editor_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// BackupRedirectDTO 旧地址重定向
+type BackupRedirectDTO struct {
+	FromPath  string    `json:"from_path"`
+	PostID    uint      `json:"post_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BackupSpamTokenDTO 垃圾评论分类器的词频统计（含记录训练样本数的特殊词）
+type BackupSpamTokenDTO struct {
+	Token     string    `json:"token"`
+	SpamCount int64     `json:"spam_count"`
+	HamCount  int64     `json:"ham_count"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BackupReportDTO 导出或恢复的统计：文件名（如 users.jsonl、media）-> 记录数
+type BackupReportDTO struct {
+	Counts map[string]int `json:"counts"`
+}
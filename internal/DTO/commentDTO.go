@@ -1,23 +1,95 @@
 package DTO
 
 type CommentDetailDTO struct {
-	ID      uint   `json:"id"`
-	Content string `json:"content"`
-	UserID  uint   `json:"user_id"`
+	ID            uint   `json:"id"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format"`
+	ContentHTML   string `json:"content_html"` // 服务端渲染并清洗后的 HTML
+	UserID        uint   `json:"user_id"`      // 游客评论为 0
 	//Username  string    `json:"username"`
-	CreatedAt string `json:"createdAt"`
-	UpdatedAt string `json:"updatedAt"`
-}
+	GuestName    string `json:"guest_name"`
+	GuestWebsite string `json:"guest_website"`
+	ParentID     uint   `json:"parent_id"`
+	Depth        int    `json:"depth"`
+	ReplyCount   int    `json:"reply_count"`
+	Deleted      bool   `json:"deleted"`
+	EditedAt     string `json:"edited_at"` // 未编辑过为空串
+	CreatedAt    string `json:"createdAt"`
+	UpdatedAt    string `json:"updatedAt"`
 
-type ListCommentDTO struct {
-	PageNum  int    `form:"page_num"`
-	PageSize int    `form:"page_size"`
-	Keyword  string `form:"keyword"`
-	UserID   uint   `json:"user_id"`
-	PostId   uint   `json:"post_id"`
+	Replies []CommentDetailDTO `json:"replies"`
 }
 
 type CreateCommentDTO struct {
-	Content string `json:"content"`
-	PostID  uint   `json:"post_id"`
+	ID            uint   `json:"id"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format"`
+	ContentHTML   string `json:"content_html"`
+	PostID        uint   `json:"post_id"`
+	ParentID      uint   `json:"parent_id"` // 0 表示根评论
+	Depth         int    `json:"depth"`
+	Status        string `json:"status"` // 审核状态，pending 表示需等待审核后公开
+	IP            string `json:"-"`      // 评论者IP，用于垃圾过滤
+}
+
+type UpdateCommentDTO struct {
+	ID            uint   `json:"id"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format"`
+	UserID        uint   `json:"user_id"`
+	IP            string `json:"ip"`
+}
+
+type ModerationQueueDTO struct {
+	Status   string `json:"status"`
+	PageNum  int    `json:"page_num"`
+	PageSize int    `json:"page_size"`
+}
+
+type ModerationCommentDTO struct {
+	ID        uint   `json:"id"`
+	PostID    uint   `json:"post_id"`
+	UserID    uint   `json:"user_id"`
+	GuestName string `json:"guest_name"`
+	ParentID  uint   `json:"parent_id"`
+	Content   string `json:"content"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+}
+
+type ModerationCommentListDTO struct {
+	Comments []ModerationCommentDTO `json:"comments"`
+	Total    int64                  `json:"total"`
+	PageNum  int                    `json:"page_num"`
+	PageSize int                    `json:"page_size"`
+}
+
+type ModerateCommentsDTO struct {
+	IDs         []uint `json:"ids"`
+	Action      string `json:"action"`
+	ModeratorID uint   `json:"moderator_id"`
+}
+
+type ModerateResultDTO struct {
+	Updated []uint `json:"updated"` // 状态发生变化的评论
+	Skipped []uint `json:"skipped"` // 不存在或状态未变化的评论
+}
+
+type CreateGuestCommentDTO struct {
+	PostID        uint   `json:"post_id"`
+	ParentID      uint   `json:"parent_id"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	Website       string `json:"website"`
+	Challenge     string `json:"challenge"`
+	Solution      string `json:"solution"`
+	IP            string `json:"-"`
+}
+
+type GuestChallengeDTO struct {
+	Token      string `json:"token"`
+	Difficulty int    `json:"difficulty"`
+	ExpiresAt  int64  `json:"expires_at"`
 }
@@ -0,0 +1,15 @@
+package DTO
+
+// 订阅源输出模式
+const (
+	FeedModeFull    = "full"    // 全文
+	FeedModeExcerpt = "excerpt" // 仅摘要
+)
+
+// FeedQueryDTO 订阅源查询条件：Author 与 Tag 同时为空时为全站订阅源
+type FeedQueryDTO struct {
+	Author string `json:"author"` // 作者用户名
+	Tag    string `json:"tag"`
+	Mode   string `json:"mode"`
+	Path   string `json:"path"` // 订阅源自身的请求路径，用于生成自引用链接
+}
@@ -0,0 +1,16 @@
+package DTO
+
+import "time"
+
+// ArchivePostDTO 归档页中的一篇文章
+type ArchivePostDTO struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArchiveMonthDTO 按月分组的归档，Month 形如 2024-05
+type ArchiveMonthDTO struct {
+	Month string           `json:"month"`
+	Posts []ArchivePostDTO `json:"posts"`
+}
@@ -0,0 +1,76 @@
+package DTO
+
+import "time"
+
+// 导入结果动作
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+	ImportActionFailed    = "failed"
+)
+
+// ImportPostDTO 从外部来源解析出的一篇文章
+type ImportPostDTO struct {
+	SourcePath    string     // 来源标识（文件相对路径等），重复导入时优先据此匹配
+	Slug          string     // 原站点的文章别名，来源变化时据此匹配
+	Title         string     // 标题
+	Content       string     // 正文
+	ContentFormat string     // 正文格式（render.FormatMarkdown 等）
+	Draft         bool       // 是否为草稿
+	Tags          []string   // 标签
+	Description   string     // 描述，写入 SEO 描述
+	Date          *time.Time // 发布时间，为空时使用导入时间
+	Modified      *time.Time // 最后修改时间，为空时与发布时间相同
+	Author        string     // 作者用户名，为空时使用本次导入的默认作者
+	OriginalURLs  []string   // 原站点地址，导入后永久重定向到本站文章页
+	Comments      []ImportCommentDTO
+}
+
+// ImportCommentDTO 随文章导入的评论
+type ImportCommentDTO struct {
+	SourceID       string    // 来源中的评论标识，重复导入时据此去重
+	ParentSourceID string    // 父评论的来源标识，根评论为空
+	Author         string    // 作者用户名，对应已导入的用户；为空时按游客评论导入
+	GuestName      string    // 游客昵称
+	GuestEmail     string    // 游客邮箱
+	GuestWebsite   string    // 游客个人网站
+	IP             string    // 评论者 IP
+	Content        string    // 评论内容
+	ContentFormat  string    // 内容格式
+	Status         string    // 审核状态（model.CommentStatus*）
+	CreatedAt      time.Time // 评论时间
+}
+
+// ImportUserDTO 导入的用户：创建时不设置可用密码，需重置密码后登录
+type ImportUserDTO struct {
+	Source   string // 导入来源（如 wordpress），必填；只复用同一来源导入时创建的用户
+	Username string
+	Email    string
+}
+
+// ImportResultDTO 单篇文章的导入结果
+type ImportResultDTO struct {
+	SourcePath string   `json:"source_path"`
+	Title      string   `json:"title"`
+	Action     string   `json:"action"`
+	PostID     uint     `json:"post_id"`
+	Changes    []string `json:"changes"` // 更新时变化的字段
+	Error      string   `json:"error"`
+}
+
+// ImportReportDTO 导入报告
+type ImportReportDTO struct {
+	DryRun    bool `json:"dry_run"`
+	Created   int  `json:"created"`
+	Updated   int  `json:"updated"`
+	Unchanged int  `json:"unchanged"`
+	Failed    int  `json:"failed"`
+	// 用户和评论
+	UsersCreated    int               `json:"users_created"`
+	UsersExisting   int               `json:"users_existing"`
+	UsersRenamed    []string          `json:"users_renamed"` // 与已有用户重名而改名导入的用户，如 "admin → admin_wordpress"
+	UserErrors      []string          `json:"user_errors"`
+	CommentsCreated int               `json:"comments_created"`
+	Results         []ImportResultDTO `json:"results"`
+}
@@ -0,0 +1,55 @@
+package DTO
+
+// UploadMediaDTO 上传媒体：Data 为文件原始内容
+type UploadMediaDTO struct {
+	UserID   uint   `json:"user_id"`
+	FileName string `json:"file_name"`
+	Data     []byte `json:"-"`
+}
+
+type MediaThumbnailDTO struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	URL    string `json:"url"`
+}
+
+// MediaPostDTO 引用媒体的文章
+type MediaPostDTO struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+type MediaDTO struct {
+	ID         uint                `json:"id"`
+	UID        string              `json:"uid"`
+	UserID     uint                `json:"user_id"`
+	FileName   string              `json:"file_name"`
+	MimeType   string              `json:"mime_type"`
+	Size       int64               `json:"size"`
+	Width      int                 `json:"width"`
+	Height     int                 `json:"height"`
+	URL        string              `json:"url"`
+	Thumbnails []MediaThumbnailDTO `json:"thumbnails"`
+	References int64               `json:"references"` // 引用该媒体的文章数
+	Posts      []MediaPostDTO      `json:"posts"`      // 仅详情返回
+	CreatedAt  string              `json:"created_at"`
+}
+
+type ListMediaDTO struct {
+	UserID   uint `json:"user_id"`
+	PageNum  int  `json:"page_num"`
+	PageSize int  `json:"page_size"`
+}
+
+type MediaListDTO struct {
+	Media    []MediaDTO `json:"media"`
+	Total    int64      `json:"total"`
+	PageNum  int        `json:"page_num"`
+	PageSize int        `json:"page_size"`
+}
+
+type DeleteMediaDTO struct {
+	ID     uint `json:"id"`
+	UserID uint `json:"user_id"`
+	Force  bool `json:"force"` // 仍被文章引用时是否强制删除
+}
@@ -1,15 +1,46 @@
 package DTO
 
+import "time"
+
+// 文章列表排序方式（白名单）
+const (
+	PostSortNewest        = "newest"         // 按创建时间倒序（默认）
+	PostSortOldest        = "oldest"         // 按创建时间正序
+	PostSortMostCommented = "most_commented" // 按评论数倒序
+	PostSortMostViewed    = "most_viewed"    // 按浏览数倒序
+	PostSortTitle         = "title"          // 按标题字母序
+)
+
 type CreatePostDTO struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title                string   `json:"title"`
+	Content              string   `json:"content"`
+	ContentFormat        string   `json:"content_format"`
+	Status               string   `json:"status"`
+	TagNames             []string `json:"tags"`
+	CommentPolicy        string   `json:"comment_policy"`
+	CommentAutoCloseDays int      `json:"comment_auto_close_days"`
+	CoverMediaID         *uint    `json:"cover_media_id"`
+	SEOTitle             string   `json:"seo_title"`
+	SEODescription       string   `json:"seo_description"`
+	CanonicalURL         string   `json:"canonical_url"`
+	NoIndex              bool     `json:"no_index"`
 }
 
 type UpdatePostDTO struct {
-	ID      uint   `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	UserID  uint   `json:"user_id"`
+	ID                   uint     `json:"id"`
+	Title                *string  `json:"title"`   // 不传保持不变
+	Content              *string  `json:"content"` // 不传保持不变
+	ContentFormat        string   `json:"content_format"`
+	Status               string   `json:"status"`
+	TagNames             []string `json:"tags"`
+	CommentPolicy        string   `json:"comment_policy"`
+	CommentAutoCloseDays *int     `json:"comment_auto_close_days"`
+	CoverMediaID         *uint    `json:"cover_media_id"`
+	SEOTitle             *string  `json:"seo_title"`
+	SEODescription       *string  `json:"seo_description"`
+	CanonicalURL         *string  `json:"canonical_url"`
+	NoIndex              *bool    `json:"no_index"`
+	UserID               uint     `json:"user_id"`
 }
 
 type ListPostDTO struct {
@@ -17,29 +48,110 @@ type ListPostDTO struct {
 	PageSize int    `form:"page_size"`
 	Keyword  string `form:"keyword"`
 	UserID   uint   `json:"user_id"`
+
+	// 筛选条件：零值表示不筛选
+	AuthorID  uint       `json:"author_id"`
+	StartTime *time.Time `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+	Status    string     `json:"status"`
+	Tag       string     `json:"tag"`
+	Sort      string     `json:"sort"`
 }
 
+// PostDTO 列表项：只返回摘要，不返回正文
 type PostDTO struct {
-	ID      uint   `json:"id"`
-	Title   string `json:"title"`
+	ID             uint      `json:"id"`
+	Title          string    `json:"title"`
+	Excerpt        string    `json:"excerpt"`
+	WordCount      int       `json:"word_count"`
+	ReadingMinutes int       `json:"reading_minutes"`
+	UserID         uint      `json:"user_id"`
+	Status         string    `json:"status"`
+	ViewCount      int64     `json:"view_count"`
+	TagNames       []string  `json:"tags"`
+	CoverURL       string    `json:"cover_url"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PostCoverDTO 封面图片
+type PostCoverDTO struct {
+	MediaID    uint                `json:"media_id"`
+	URL        string              `json:"url"`
+	Width      int                 `json:"width"`
+	Height     int                 `json:"height"`
+	Thumbnails []MediaThumbnailDTO `json:"thumbnails"`
+}
+
+// MetaTagDTO 页面 <meta> 标签：Attr 为 property（Open Graph）或 name（Twitter）
+type MetaTagDTO struct {
+	Attr    string `json:"attr"`
+	Key     string `json:"key"`
 	Content string `json:"content"`
 }
 
+// PostMetaDTO 文章页元数据，前端可直接输出到 <head>
+type PostMetaDTO struct {
+	Title        string                 `json:"title"`
+	Description  string                 `json:"description"`
+	CanonicalURL string                 `json:"canonical_url"`
+	Robots       string                 `json:"robots"`
+	Tags         []MetaTagDTO           `json:"tags"`
+	JSONLD       map[string]interface{} `json:"json_ld"`
+}
+
+// TOCItemDTO 目录条目，与 render.TOCItem 的 JSON 结构一致
+type TOCItemDTO struct {
+	Level    int          `json:"level"`
+	Text     string       `json:"text"`
+	Anchor   string       `json:"anchor"`
+	Children []TOCItemDTO `json:"children,omitempty"`
+}
+
+// PostListFilterDTO 文章列表实际生效的筛选条件，原样回显给调用方
+type PostListFilterDTO struct {
+	Keyword   string `json:"keyword"`
+	AuthorID  uint   `json:"author_id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Status    string `json:"status"`
+	Tag       string `json:"tag"`
+	Sort      string `json:"sort"`
+}
+
 type PostListDTO struct {
-	Posts    []PostDTO `json:"posts"`
-	Total    int64     `json:"total"`
-	PageNum  int       `form:"page_num"`
-	PageSize int       `form:"page_size"`
+	Posts    []PostDTO         `json:"posts"`
+	Total    int64             `json:"total"`
+	PageNum  int               `form:"page_num"`
+	PageSize int               `form:"page_size"`
+	Filters  PostListFilterDTO `json:"filters"`
 }
 
 type PostDetailDTO struct {
-	ID        uint   `json:"id"`
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	UserID    uint   `json:"userId"`
-	CreatedAt string `json:"createdAt"`
-	UpdatedAt string `json:"updatedAt"`
-	Username  string `json:"username"`
+	ID             uint         `json:"id"`
+	Title          string       `json:"title"`
+	Content        string       `json:"content"`
+	ContentFormat  string       `json:"contentFormat"`
+	ContentHTML    string       `json:"contentHtml"` // 服务端渲染并清洗后的 HTML
+	WordCount      int          `json:"wordCount"`
+	ReadingMinutes int          `json:"readingMinutes"`
+	TOC            []TOCItemDTO `json:"toc"`
+	UserID         uint         `json:"userId"`
+	Status         string       `json:"status"`
+	ViewCount      int64        `json:"viewCount"`
+	TagNames       []string     `json:"tags"`
+	CreatedAt      string       `json:"createdAt"`
+	UpdatedAt      string       `json:"updatedAt"`
+	Username       string       `json:"username"`
+	// 当前实际生效的评论策略（已计算继承和自动关闭）
+	CommentPolicy string `json:"commentPolicy"`
+	// 封面及 SEO 设置原值（供编辑页回显）
+	Cover          *PostCoverDTO `json:"cover"`
+	SEOTitle       string        `json:"seoTitle"`
+	SEODescription string        `json:"seoDescription"`
+	CanonicalURL   string        `json:"canonicalUrl"`
+	NoIndex        bool          `json:"noIndex"`
+	// 计算后的页面元数据（Open Graph、Twitter 卡片、JSON-LD）
+	Meta PostMetaDTO `json:"meta"`
 
 	Comments []CommentDetailDTO `json:"comments"`
 }
@@ -0,0 +1,9 @@
+package DTO
+
+import "time"
+
+// LastModDTO 名称及其下已发布文章的最近更新时间（标签页、作者页的 lastmod）
+type LastModDTO struct {
+	Name    string    `json:"name"`
+	LastMod time.Time `json:"last_mod"`
+}
@@ -0,0 +1,14 @@
+package DTO
+
+type SpamCheckDTO struct {
+	UserID  uint   `json:"user_id"`
+	Email   string `json:"email"`
+	IP      string `json:"ip"`
+	Content string `json:"content"`
+}
+
+type SpamVerdictDTO struct {
+	Score   float64  `json:"score"`   // 0~1，越高越可能是垃圾评论
+	Status  string   `json:"status"`  // 建议的评论状态：spam/pending，空串表示不干预
+	Reasons []string `json:"reasons"` // 命中的规则，便于审核时参考
+}
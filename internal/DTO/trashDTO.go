@@ -0,0 +1,51 @@
+package DTO
+
+// ListTrashDTO 回收站列表查询
+type ListTrashDTO struct {
+	UserID   uint `json:"user_id"`
+	PageNum  int  `json:"page_num"`
+	PageSize int  `json:"page_size"`
+}
+
+// TrashPostDTO 回收站中的文章
+type TrashPostDTO struct {
+	ID        uint   `json:"id"`
+	Title     string `json:"title"`
+	UserID    uint   `json:"user_id"`
+	Status    string `json:"status"`
+	Comments  int64  `json:"comments"` // 随文章一起删除、恢复时一并恢复的评论数
+	DeletedAt string `json:"deleted_at"`
+	PurgeAt   string `json:"purge_at"` // 预计永久删除时间
+}
+
+type TrashPostListDTO struct {
+	Posts    []TrashPostDTO `json:"posts"`
+	Total    int64          `json:"total"`
+	PageNum  int            `json:"page_num"`
+	PageSize int            `json:"page_size"`
+}
+
+// TrashCommentDTO 回收站中的评论
+type TrashCommentDTO struct {
+	ID        uint   `json:"id"`
+	PostID    uint   `json:"post_id"`
+	PostTitle string `json:"post_title"`
+	UserID    uint   `json:"user_id"`
+	Content   string `json:"content"`
+	Status    string `json:"status"`
+	DeletedAt string `json:"deleted_at"`
+	PurgeAt   string `json:"purge_at"`
+}
+
+type TrashCommentListDTO struct {
+	Comments []TrashCommentDTO `json:"comments"`
+	Total    int64             `json:"total"`
+	PageNum  int               `json:"page_num"`
+	PageSize int               `json:"page_size"`
+}
+
+// RestorePostResultDTO 恢复文章的结果
+type RestorePostResultDTO struct {
+	ID       uint  `json:"id"`
+	Comments int64 `json:"comments"` // 一并恢复的评论数
+}
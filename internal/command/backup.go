@@ -0,0 +1,86 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go-my-blog/bootstrap"
+	"go-my-blog/internal/DTO"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+func init() {
+	register(Command{
+		Name:  "export",
+		Usage: "导出整站数据（用户、文章、评论、媒体等）为归档：export [-out go-my-blog-时间.tar.gz]",
+		Run:   runExport,
+	})
+	register(Command{
+		Name:  "restore",
+		Usage: "从 export 生成的归档恢复数据到空数据库：restore <file>",
+		Run:   runRestore,
+	})
+}
+
+func runExport(ctx context.Context, container *bootstrap.Container, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := flags.String("out", "go-my-blog-"+time.Now().Format("20060102-150405")+".tar.gz", "输出文件")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// 先写临时文件，导出成功后再改名，避免中途失败留下不完整的归档
+	tmp, err := os.CreateTemp(filepath.Dir(*out), ".export-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	report, err := container.BackupService.Export(ctx, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), *out); err != nil {
+		return err
+	}
+
+	fmt.Printf("已导出到 %s\n", *out)
+	printBackupReport(report)
+	return nil
+}
+
+func runRestore(ctx context.Context, container *bootstrap.Container, args []string) error {
+	if len(args) != 1 {
+		return errors.New("用法：restore <file>")
+	}
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	report, err := container.BackupService.Restore(ctx, file)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("已从 %s 恢复\n", args[0])
+	printBackupReport(report)
+	return nil
+}
+
+func printBackupReport(report *DTO.BackupReportDTO) {
+	names := make([]string, 0, len(report.Counts))
+	for name := range report.Counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  %-24s %d\n", name, report.Counts[name])
+	}
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-my-blog/bootstrap"
+	"sort"
+	"strings"
+)
+
+// Command 命令行子命令，在配置、数据库和模块初始化完成后执行
+type Command struct {
+	Name  string
+	Usage string
+	Run   func(ctx context.Context, container *bootstrap.Container, args []string) error
+}
+
+var commands = make(map[string]Command)
+
+// register 注册子命令，各命令在自己文件的 init 中调用
+func register(cmd Command) {
+	commands[cmd.Name] = cmd
+}
+
+// Run 执行 args[0] 对应的子命令，其余参数交给子命令解析；ctx 取消（如收到中断信号）时命令尽快结束
+func Run(ctx context.Context, container *bootstrap.Container, args []string) error {
+	if len(args) == 0 {
+		return errors.New(Usage())
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("未知命令 %q\n%s", args[0], Usage())
+	}
+	return cmd.Run(ctx, container, args[1:])
+}
+
+// Usage 全部子命令的用法说明
+func Usage() string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("用法：go-my-blog [命令] [参数]，不带命令时启动 HTTP 服务\n可用命令：\n")
+	for _, name := range names {
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", name, commands[name].Usage))
+	}
+	return b.String()
+}
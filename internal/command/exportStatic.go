@@ -0,0 +1,490 @@
+package command
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go-my-blog/bootstrap"
+	"go-my-blog/config"
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/storage"
+	"go-my-blog/router"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// manifestName 导出目录中的清单文件
+const manifestName = "manifest.json"
+
+func init() {
+	register(Command{
+		Name:  "export-static",
+		Usage: "将已发布内容导出为静态站点：export-static [-out dist] [-full]",
+		Run:   runExportStatic,
+	})
+}
+
+// staticManifest 导出清单：记录每个文件的哈希和每篇文章的更新时间及评论指纹，用于增量构建和 CDN 缓存刷新
+type staticManifest struct {
+	GeneratedAt time.Time            `json:"generated_at"`
+	Posts       map[string]time.Time `json:"posts"`      // 文章ID -> 导出时的 UpdatedAt
+	Comments    map[string]string    `json:"comments"`   // 文章ID -> 导出时的评论指纹，没有评论的文章不记录
+	Files       map[string]string    `json:"files"`      // 相对路径 -> sha256
+	Changed     []string             `json:"changed"`    // 本次新增或内容变化的文件
+	Removed     []string             `json:"removed"`    // 本次删除的文件（如已删除或取消发布的文章）
+	Invalidate  []string             `json:"invalidate"` // 需要刷新 CDN 缓存的 URL 路径
+}
+
+// staticExporter 通过 Gin 引擎在进程内请求页面，保证导出结果与线上渲染一致
+type staticExporter struct {
+	container *bootstrap.Container
+	engine    *gin.Engine
+	outDir    string
+	full      bool
+	previous  *staticManifest
+	current   *staticManifest
+}
+
+func runExportStatic(ctx context.Context, container *bootstrap.Container, args []string) error {
+	flags := flag.NewFlagSet("export-static", flag.ContinueOnError)
+	outDir := flags.String("out", "dist", "输出目录")
+	full := flags.Bool("full", false, "忽略上次的清单，全量重新生成")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if container.FrontendHandler == nil {
+		return errors.New("静态导出依赖 HTML 前台，请先在配置中开启 frontend.enabled")
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	router.InitRouter(engine, container)
+
+	exporter := &staticExporter{
+		container: container,
+		engine:    engine,
+		outDir:    *outDir,
+		full:      *full,
+		previous:  loadManifest(filepath.Join(*outDir, manifestName)),
+		current: &staticManifest{
+			GeneratedAt: time.Now(),
+			Posts:       make(map[string]time.Time),
+			Comments:    make(map[string]string),
+			Files:       make(map[string]string),
+			Changed:     []string{},
+			Removed:     []string{},
+			Invalidate:  []string{},
+		},
+	}
+	if *full {
+		exporter.previous = &staticManifest{Posts: map[string]time.Time{}, Comments: map[string]string{}, Files: map[string]string{}}
+	}
+	return exporter.export(ctx)
+}
+
+func (e *staticExporter) export(ctx context.Context) error {
+	if err := os.MkdirAll(e.outDir, 0o755); err != nil {
+		return err
+	}
+
+	steps := []func(ctx context.Context) error{e.exportPosts, e.exportListings, e.exportFeeds, e.exportSitemaps, e.exportThemeAssets, e.exportMedia}
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			return err
+		}
+	}
+
+	// 上次有、本次没有的文件说明内容已不存在，一并删除
+	for rel := range e.previous.Files {
+		if _, ok := e.current.Files[rel]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(e.outDir, filepath.FromSlash(rel))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("删除过期文件失败", zap.String("file", rel), zap.Error(err))
+			continue
+		}
+		e.current.Removed = append(e.current.Removed, rel)
+		e.current.Invalidate = append(e.current.Invalidate, urlPathOf(rel))
+	}
+	sort.Strings(e.current.Changed)
+	sort.Strings(e.current.Removed)
+	sort.Strings(e.current.Invalidate)
+
+	data, err := json.MarshalIndent(e.current, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(e.outDir, manifestName), data); err != nil {
+		return err
+	}
+	logger.Info("静态站点导出完成",
+		zap.String("out", e.outDir),
+		zap.Int("files", len(e.current.Files)),
+		zap.Int("changed", len(e.current.Changed)),
+		zap.Int("removed", len(e.current.Removed)))
+	return nil
+}
+
+// exportPosts 导出文章页；增量模式下 UpdatedAt 和评论指纹都未变化且文件仍在的文章直接沿用
+func (e *staticExporter) exportPosts(ctx context.Context) error {
+	posts, err := e.container.PostRepo.ListArchive(ctx)
+	if err != nil {
+		return err
+	}
+	comments, err := e.commentFingerprints(ctx)
+	if err != nil {
+		return err
+	}
+	for _, post := range posts {
+		id := strconv.FormatUint(uint64(post.ID), 10)
+		urlPath := "/posts/" + id
+		rel := filePathOf(urlPath)
+		e.current.Posts[id] = post.UpdatedAt
+		if fingerprint, ok := comments[post.ID]; ok {
+			e.current.Comments[id] = fingerprint
+		}
+
+		previousHash, exported := e.previous.Files[rel]
+		updatedAt, ok := e.previous.Posts[id]
+		if ok && exported && updatedAt.Equal(post.UpdatedAt) && e.previous.Comments[id] == comments[post.ID] && e.exists(rel) {
+			e.current.Files[rel] = previousHash
+			continue
+		}
+		if _, err := e.exportURL(urlPath, http.StatusOK); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commentFingerprints 按文章汇总页面上展示的评论，生成评论指纹
+// 评论的审核、编辑、删除和回复数变化都会改变指纹；审核状态只用 UpdateColumn 更新，不会刷新 updated_at，
+// 所以不能只比较最近更新时间，而是对评论集合逐条取值后计算哈希
+func (e *staticExporter) commentFingerprints(ctx context.Context) (map[uint]string, error) {
+	comments, err := e.container.CommentRepo.ListApprovedStamps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fingerprints := make(map[uint]string)
+	for start := 0; start < len(comments); {
+		end := start
+		h := sha256.New()
+		for ; end < len(comments) && comments[end].PostID == comments[start].PostID; end++ {
+			c := comments[end]
+			var editedAt int64
+			if c.EditedAt != nil {
+				editedAt = c.EditedAt.UnixNano()
+			}
+			fmt.Fprintf(h, "%d:%d:%t:%d:%d\n", c.ID, c.ReplyCount, c.IsDeleted, editedAt, c.UpdatedAt.UnixNano())
+		}
+		fingerprints[comments[start].PostID] = hex.EncodeToString(h.Sum(nil))[:16]
+		start = end
+	}
+	return fingerprints, nil
+}
+
+// exportListings 导出首页、标签页、作者页（含分页）、归档页和 404 页
+func (e *staticExporter) exportListings(ctx context.Context) error {
+	if err := e.exportPaged("/"); err != nil {
+		return err
+	}
+	tags, err := e.container.PostRepo.ListTagLastMods(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if !safeSegment(tag.Name) {
+			logger.Warn("标签名无法作为目录名，跳过导出", zap.String("tag", tag.Name))
+			continue
+		}
+		if err := e.exportPaged("/tags/" + url.PathEscape(tag.Name)); err != nil {
+			return err
+		}
+	}
+	authors, err := e.container.PostRepo.ListAuthorLastMods(ctx)
+	if err != nil {
+		return err
+	}
+	for _, author := range authors {
+		if !safeSegment(author.Name) {
+			logger.Warn("用户名无法作为目录名，跳过导出", zap.String("author", author.Name))
+			continue
+		}
+		if err := e.exportPaged("/authors/" + url.PathEscape(author.Name)); err != nil {
+			return err
+		}
+	}
+	if _, err := e.exportURL("/archive", http.StatusOK); err != nil {
+		return err
+	}
+	// 404 页面保存为 404.html，大多数 CDN 和静态托管会自动使用
+	_, err = e.exportURLAs("/404.html", "404.html", http.StatusNotFound)
+	return err
+}
+
+// exportPaged 导出列表页及其后续分页，直到页码超出范围
+func (e *staticExporter) exportPaged(basePath string) error {
+	if _, err := e.exportURL(basePath, http.StatusOK); err != nil {
+		return err
+	}
+	for page := 2; ; page++ {
+		ok, err := e.exportURL(strings.TrimSuffix(basePath, "/")+"/page/"+strconv.Itoa(page), http.StatusOK)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+}
+
+// exportFeeds 导出全站、各标签和各作者的订阅源
+func (e *staticExporter) exportFeeds(ctx context.Context) error {
+	prefixes := []string{""}
+	tags, err := e.container.PostRepo.ListTagLastMods(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if safeSegment(tag.Name) {
+			prefixes = append(prefixes, "/tags/"+url.PathEscape(tag.Name))
+		}
+	}
+	authors, err := e.container.PostRepo.ListAuthorLastMods(ctx)
+	if err != nil {
+		return err
+	}
+	for _, author := range authors {
+		if safeSegment(author.Name) {
+			prefixes = append(prefixes, "/authors/"+url.PathEscape(author.Name))
+		}
+	}
+	for _, prefix := range prefixes {
+		for _, name := range []string{"/feed.xml", "/atom.xml", "/feed.json"} {
+			if _, err := e.exportURL(prefix+name, http.StatusOK); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// exportSitemaps 导出站点地图（含拆分后的分页）和 robots.txt
+func (e *staticExporter) exportSitemaps(ctx context.Context) error {
+	for _, urlPath := range []string{"/sitemap.xml", "/robots.txt"} {
+		if _, err := e.exportURL(urlPath, http.StatusOK); err != nil {
+			return err
+		}
+	}
+	for page := 1; ; page++ {
+		ok, err := e.exportURL("/sitemaps/"+strconv.Itoa(page)+".xml", http.StatusOK)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+}
+
+// exportThemeAssets 复制主题静态资源到 /theme/
+func (e *staticExporter) exportThemeAssets(ctx context.Context) error {
+	static := e.container.FrontendHandler.Static()
+	return fs.WalkDir(static, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(static, name)
+		if err != nil {
+			return err
+		}
+		return e.write(path.Join("theme", name), data)
+	})
+}
+
+// exportMedia 复制本地存储的媒体文件（原图和缩略图）；媒体 key 不可变，已导出过的直接跳过。
+// S3 存储的文件本身已由对象存储/CDN 提供，无需复制
+func (e *staticExporter) exportMedia(ctx context.Context) error {
+	mediaConf := config.Conf.Media
+	if mediaConf.Storage != storage.DriverLocal {
+		logger.Info("媒体使用对象存储，跳过复制", zap.String("storage", mediaConf.Storage))
+		return nil
+	}
+	media, err := e.container.MediaRepo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	prefix := strings.Trim(mediaConf.Local.URLPrefix, "/")
+	for _, item := range media {
+		keys := []string{item.StorageKey}
+		var thumbnails []struct {
+			Key string `json:"key"`
+		}
+		if item.Thumbnails != "" && json.Unmarshal([]byte(item.Thumbnails), &thumbnails) == nil {
+			for _, thumbnail := range thumbnails {
+				keys = append(keys, thumbnail.Key)
+			}
+		}
+		for _, key := range keys {
+			rel := path.Join(prefix, key)
+			if hash, ok := e.previous.Files[rel]; ok && e.exists(rel) {
+				e.current.Files[rel] = hash
+				continue
+			}
+			if err := e.copyMedia(key, rel); err != nil {
+				logger.Warn("媒体文件复制失败", zap.String("key", key), zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+func (e *staticExporter) copyMedia(key string, rel string) error {
+	reader, err := e.container.MediaStorage.Open(key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	return e.write(rel, data)
+}
+
+// exportURL 请求页面并按美化地址保存；返回 false 表示页面不存在（用于分页探测）
+func (e *staticExporter) exportURL(urlPath string, expectStatus int) (bool, error) {
+	return e.exportURLAs(urlPath, filePathOf(urlPath), expectStatus)
+}
+
+func (e *staticExporter) exportURLAs(urlPath string, rel string, expectStatus int) (bool, error) {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, urlPath, nil)
+	// 导出不是真实访问：不计浏览数，页面中也不输出会过时的浏览数
+	e.engine.ServeHTTP(recorder, req.WithContext(service.WithStaticRender(req.Context())))
+	if recorder.Code == http.StatusNotFound && expectStatus != http.StatusNotFound {
+		return false, nil
+	}
+	if recorder.Code != expectStatus {
+		return false, fmt.Errorf("导出 %s 失败：状态码 %d", urlPath, recorder.Code)
+	}
+	return true, e.write(rel, recorder.Body.Bytes())
+}
+
+// write 写入文件并记录哈希；内容未变化时不重写，也不计入变更
+func (e *staticExporter) write(rel string, data []byte) error {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	e.current.Files[rel] = hash
+	if e.previous.Files[rel] == hash && e.exists(rel) {
+		return nil
+	}
+	if err := writeFileAtomic(filepath.Join(e.outDir, filepath.FromSlash(rel)), data); err != nil {
+		logger.Error("写入导出文件失败", zap.String("file", rel), zap.Error(err))
+		return err
+	}
+	e.current.Changed = append(e.current.Changed, rel)
+	e.current.Invalidate = append(e.current.Invalidate, urlPathOf(rel))
+	return nil
+}
+
+func (e *staticExporter) exists(rel string) bool {
+	_, err := os.Stat(filepath.Join(e.outDir, filepath.FromSlash(rel)))
+	return err == nil
+}
+
+// loadManifest 读取上次导出的清单，不存在或损坏时按首次导出处理
+func loadManifest(file string) *staticManifest {
+	manifest := &staticManifest{Posts: map[string]time.Time{}, Comments: map[string]string{}, Files: map[string]string{}}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return manifest
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		logger.Warn("导出清单解析失败，将全量导出", zap.Error(err))
+		return &staticManifest{Posts: map[string]time.Time{}, Comments: map[string]string{}, Files: map[string]string{}}
+	}
+	if manifest.Posts == nil {
+		manifest.Posts = map[string]time.Time{}
+	}
+	if manifest.Comments == nil {
+		manifest.Comments = map[string]string{}
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]string{}
+	}
+	return manifest
+}
+
+// filePathOf 美化地址对应的文件：带扩展名的原样保存，其余保存为目录下的 index.html
+func filePathOf(urlPath string) string {
+	unescaped, err := url.PathUnescape(urlPath)
+	if err != nil {
+		unescaped = urlPath
+	}
+	rel := strings.Trim(path.Clean("/"+unescaped), "/")
+	if rel == "" {
+		return "index.html"
+	}
+	if path.Ext(rel) != "" {
+		return rel
+	}
+	return rel + "/index.html"
+}
+
+// urlPathOf 文件对应的访问地址（已转义），index.html 对应目录地址
+func urlPathOf(rel string) string {
+	urlPath := "/" + rel
+	if rel == "index.html" || strings.HasSuffix(rel, "/index.html") {
+		urlPath = strings.TrimSuffix(urlPath, "index.html")
+	}
+	return (&url.URL{Path: urlPath}).EscapedPath()
+}
+
+// safeSegment 名称能否安全地作为一级目录名（不含路径分隔符，也不是 . 或 ..）
+func safeSegment(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免 CDN 同步时读到写了一半的文件
+func writeFileAtomic(file string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".export-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go-my-blog/bootstrap"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/service"
+	"sort"
+	"strings"
+)
+
+// importSource 一种导入来源：边解析边向导入会话提交用户和文章，不需要一次性加载全部内容
+type importSource struct {
+	usage       string
+	needsAuthor bool // 来源中没有作者信息，必须指定默认作者
+	run         func(ctx context.Context, input string, session *service.ImportSession) error
+}
+
+var importSources = make(map[string]importSource)
+
+func init() {
+	register(Command{
+		Name:  "import",
+		Usage: "导入文章：import <来源> <路径> [-author 用户名] [-dry-run]",
+		Run:   runImport,
+	})
+}
+
+func runImport(ctx context.Context, container *bootstrap.Container, args []string) error {
+	if len(args) == 0 {
+		return errors.New(importUsage())
+	}
+	source, ok := importSources[args[0]]
+	if !ok {
+		return fmt.Errorf("未知的导入来源 %q\n%s", args[0], importUsage())
+	}
+
+	flags := flag.NewFlagSet("import "+args[0], flag.ContinueOnError)
+	author := flags.String("author", config.Conf.Import.Author, "导入文章的作者用户名")
+	dryRun := flags.Bool("dry-run", false, "只输出差异报告，不写入数据库")
+	// 允许参数写在路径前后
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("缺少导入路径\n" + importUsage())
+	}
+	input := flags.Arg(0)
+	if err := flags.Parse(flags.Args()[1:]); err != nil {
+		return err
+	}
+	if *author == "" && source.needsAuthor {
+		return errors.New("未指定作者：请使用 -author 或在配置中设置 import.author")
+	}
+
+	session, err := container.ImportService.Begin(ctx, *author, *dryRun)
+	if err != nil {
+		return err
+	}
+	if err := source.run(ctx, input, session); err != nil {
+		return err
+	}
+	report := session.Report()
+	printImportReport(report)
+	if report.Failed > 0 {
+		return fmt.Errorf("%d 篇文章导入失败", report.Failed)
+	}
+	return nil
+}
+
+// printImportReport 输出差异报告：每篇文章的动作及变化的字段
+func printImportReport(report *DTO.ImportReportDTO) {
+	for _, result := range report.Results {
+		if result.Action == DTO.ImportActionUnchanged {
+			continue
+		}
+		fmt.Printf("[%s] %s（%s）\n", result.Action, result.SourcePath, result.Title)
+		for _, change := range result.Changes {
+			fmt.Printf("    %s\n", change)
+		}
+		if result.Error != "" {
+			fmt.Printf("    错误：%s\n", result.Error)
+		}
+	}
+	if report.UsersCreated > 0 || report.UsersExisting > 0 || len(report.UserErrors) > 0 {
+		fmt.Printf("用户：新建 %d，已存在 %d，失败 %d\n", report.UsersCreated, report.UsersExisting, len(report.UserErrors))
+		for _, renamed := range report.UsersRenamed {
+			fmt.Printf("    用户名已被占用，改名导入：%s\n", renamed)
+		}
+		for _, userErr := range report.UserErrors {
+			fmt.Printf("    %s\n", userErr)
+		}
+	}
+	mode := ""
+	if report.DryRun {
+		mode = "（预览，未写入）"
+	}
+	fmt.Printf("文章：新建 %d，更新 %d，未变化 %d，失败 %d；新增评论 %d 条%s\n",
+		report.Created, report.Updated, report.Unchanged, report.Failed, report.CommentsCreated, mode)
+}
+
+func importUsage() string {
+	names := make([]string, 0, len(importSources))
+	for name := range importSources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("可用的导入来源：\n")
+	for _, name := range names {
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", name, importSources[name].usage))
+	}
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/frontmatter"
+	"go-my-blog/pkg/render"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// datedFileName Jekyll 风格的文件名：2024-05-01-hello-world.md
+var datedFileName = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+
+func init() {
+	importSources["markdown"] = importSource{
+		usage:       "带 YAML/TOML 前置元数据的 Markdown 目录（Hugo/Jekyll）",
+		needsAuthor: true,
+		run:         importMarkdownDir,
+	}
+}
+
+// importMarkdownDir 递归读取目录下的 .md/.markdown 文件；以 _ 或 . 开头的文件和目录（如 Hugo 的 _index.md）跳过
+func importMarkdownDir(ctx context.Context, dir string, session *service.ImportSession) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s 不是目录", dir)
+	}
+
+	return filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if file != dir && (strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".")) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(name))
+		if entry.IsDir() || (ext != ".md" && ext != ".markdown") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		post, err := markdownPost(filepath.ToSlash(rel), data)
+		if err != nil {
+			return fmt.Errorf("%s：%w", rel, err)
+		}
+		session.ImportPost(ctx, post)
+		return nil
+	})
+}
+
+// markdownPost 将前置元数据映射为文章：title、date、lastmod、tags（及 categories）、slug、draft、description。
+// 缺少的字段从文件名推断：Jekyll 文件名中的日期和 slug，Hugo 页面包（index.md）使用目录名作为 slug
+func markdownPost(rel string, data []byte) (*DTO.ImportPostDTO, error) {
+	doc, err := frontmatter.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+	if base == "index" && path.Dir(rel) != "." {
+		base = path.Base(path.Dir(rel))
+	}
+	var fileDate string
+	if match := datedFileName.FindStringSubmatch(base); match != nil {
+		fileDate, base = match[1], match[2]
+	}
+
+	post := &DTO.ImportPostDTO{
+		SourcePath:    rel,
+		Slug:          doc.String("slug"),
+		Title:         doc.String("title"),
+		Content:       doc.Body,
+		ContentFormat: render.FormatMarkdown,
+		Draft:         doc.Bool("draft") || (doc.Meta["published"] != nil && !doc.Bool("published")),
+		Description:   firstNonEmpty(doc.String("description"), doc.String("summary")),
+	}
+	if post.Slug == "" {
+		post.Slug = base
+	}
+	if post.Title == "" {
+		post.Title = base
+	}
+	// 博客没有分类，分类按标签导入
+	post.Tags = append(doc.Strings("tags"), doc.Strings("categories")...)
+
+	if post.Date, err = doc.Time("date"); err != nil {
+		return nil, err
+	}
+	if post.Date == nil && fileDate != "" {
+		if post.Date, err = frontmatter.ParseTime(fileDate); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range []string{"lastmod", "updated", "last_modified_at"} {
+		if post.Modified, err = doc.Time(key); err != nil {
+			return nil, err
+		}
+		if post.Modified != nil {
+			break
+		}
+	}
+	return post, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
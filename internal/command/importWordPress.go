@@ -0,0 +1,159 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/render"
+	"go-my-blog/pkg/wxr"
+	"html"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	importSources["wordpress"] = importSource{
+		usage: "WordPress 导出文件（WXR），包含作者、文章、分类/标签和评论",
+		run:   importWordPress,
+	}
+}
+
+// importWordPress 流式读取 WXR：先导入 <wp:author>，再逐篇导入文章（post_type=post）及其评论。
+// 作者以 WordPress 登录名导入；导出中没有作者列表时，文章使用 -author 指定的默认作者
+func importWordPress(ctx context.Context, file string, session *service.ImportSession) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	logins := make(map[int]string) // WordPress 用户ID -> 登录名，用于匹配评论作者
+	onAuthor := func(author *wxr.Author) error {
+		session.ImportUser(ctx, &DTO.ImportUserDTO{Source: "wordpress", Username: author.Login, Email: author.Email})
+		logins[author.ID] = author.Login
+		return nil
+	}
+	onItem := func(item *wxr.Item) error {
+		if item.PostType != "post" {
+			return nil
+		}
+		post, ok := wordPressPost(item, logins)
+		if ok {
+			session.ImportPost(ctx, post)
+		}
+		return nil
+	}
+	return wxr.Parse(bufio.NewReaderSize(f, 1<<20), onAuthor, onItem)
+}
+
+// wordPressPost 将 WXR 文章映射为导入文章；回收站和自动草稿不导入
+func wordPressPost(item *wxr.Item, logins map[int]string) (*DTO.ImportPostDTO, bool) {
+	var draft bool
+	switch item.Status {
+	case "publish":
+	case "draft", "pending", "private", "future":
+		draft = true
+	default:
+		return nil, false
+	}
+
+	post := &DTO.ImportPostDTO{
+		SourcePath:    "wordpress:" + strconv.Itoa(item.PostID),
+		Slug:          decodeSlug(item.PostName),
+		Title:         html.UnescapeString(strings.TrimSpace(item.Title)),
+		Content:       wxr.AutoP(item.Content),
+		ContentFormat: render.FormatHTML,
+		Draft:         draft,
+		Date:          wxr.ParseDate(item.PostDateGMT, item.PostDate),
+		Modified:      wxr.ParseDate(item.PostModifiedGMT, item.PostModified),
+		Author:        strings.TrimSpace(item.Creator),
+	}
+	if post.Title == "" {
+		post.Title = post.Slug
+	}
+	if len(logins) == 0 {
+		// 导出中没有作者列表时无法创建作者，改用默认作者
+		post.Author = ""
+	}
+	// 博客没有分类，WordPress 的分类和标签都作为标签导入（未分类除外）
+	for _, category := range item.Categories {
+		if category.Domain == "category" && category.Nicename == "uncategorized" {
+			continue
+		}
+		if category.Domain == "category" || category.Domain == "post_tag" {
+			post.Tags = append(post.Tags, html.UnescapeString(strings.TrimSpace(category.Name)))
+		}
+	}
+	// 保留原地址用于重定向：固定链接和 ?p= 形式的 guid
+	if item.Link != "" {
+		post.OriginalURLs = append(post.OriginalURLs, item.Link)
+	}
+	if item.GUID != "" && item.GUID != item.Link {
+		post.OriginalURLs = append(post.OriginalURLs, item.GUID)
+	}
+	post.OriginalURLs = append(post.OriginalURLs, "/?p="+strconv.Itoa(item.PostID))
+
+	for _, comment := range item.Comments {
+		if importComment, ok := wordPressComment(&comment, logins); ok {
+			post.Comments = append(post.Comments, importComment)
+		}
+	}
+	return post, true
+}
+
+// wordPressComment 映射评论：引用通告和回收站中的评论不导入；注册用户的评论关联到导入的用户
+func wordPressComment(comment *wxr.Comment, logins map[int]string) (DTO.ImportCommentDTO, bool) {
+	if comment.Type != "" && comment.Type != "comment" {
+		return DTO.ImportCommentDTO{}, false
+	}
+	var status string
+	switch comment.Approved {
+	case "1":
+		status = model.CommentStatusApproved
+	case "0":
+		status = model.CommentStatusPending
+	case "spam":
+		status = model.CommentStatusSpam
+	default:
+		return DTO.ImportCommentDTO{}, false
+	}
+
+	createdAt := time.Now()
+	if date := wxr.ParseDate(comment.DateGMT, comment.Date); date != nil {
+		createdAt = *date
+	}
+	importComment := DTO.ImportCommentDTO{
+		SourceID:      "wordpress:" + strconv.Itoa(comment.ID),
+		GuestName:     html.UnescapeString(strings.TrimSpace(comment.Author)),
+		GuestEmail:    strings.TrimSpace(comment.AuthorEmail),
+		GuestWebsite:  strings.TrimSpace(comment.AuthorURL),
+		IP:            strings.TrimSpace(comment.AuthorIP),
+		Content:       wxr.AutoP(comment.Content),
+		ContentFormat: render.FormatHTML,
+		Status:        status,
+		CreatedAt:     createdAt,
+	}
+	if comment.Parent != 0 {
+		importComment.ParentSourceID = "wordpress:" + strconv.Itoa(comment.Parent)
+	}
+	if comment.UserID != 0 {
+		importComment.Author = logins[comment.UserID]
+	}
+	if importComment.GuestName == "" {
+		importComment.GuestName = "匿名"
+	}
+	return importComment, true
+}
+
+// decodeSlug WordPress 的中文 slug 以百分号编码保存，还原为可读形式
+func decodeSlug(slug string) string {
+	if decoded, err := url.PathUnescape(slug); err == nil {
+		return decoded
+	}
+	return slug
+}
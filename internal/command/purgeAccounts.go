@@ -0,0 +1,23 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"go-my-blog/bootstrap"
+)
+
+func init() {
+	register(Command{
+		Name:  "purge-accounts",
+		Usage: "立即删除冷静期已过的账号和过期的个人数据归档（服务运行时也会定期执行）",
+		Run:   runPurgeAccounts,
+	})
+}
+
+func runPurgeAccounts(ctx context.Context, container *bootstrap.Container, args []string) error {
+	if err := container.AccountService.PurgeDue(ctx); err != nil {
+		return err
+	}
+	fmt.Println("清理完成")
+	return nil
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/middleware"
+	"go-my-blog/internal/request"
+	"go-my-blog/internal/response"
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/logger"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+	"go.uber.org/zap"
+)
+
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// RequestExport 申请导出个人数据（后台生成，通过任务ID查询进度和下载）
+func (ah AccountHandler) RequestExport(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	exportDTO, err := ah.accountService.RequestExport(context.Request.Context(), userID.(uint), context.ClientIP())
+	if err != nil {
+		logger.Error("申请导出个人数据失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var exportResp response.DataExportResponse
+	if err := copier.Copy(&exportResp, exportDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusAccepted, gin.H{"msg": message(context, "export_requested"), "data": exportResp})
+}
+
+// ExportStatus 查询导出任务进度
+func (ah AccountHandler) ExportStatus(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	exportID, err := strconv.ParseUint(context.Param("id"), 10, 0)
+	if err != nil {
+		logger.Error("导出任务ID格式错误", zap.Error(err))
+		context.Error(invalidParam(errInvalidExportID, err))
+		return
+	}
+
+	exportDTO, err := ah.accountService.ExportStatus(context.Request.Context(), uint(exportID), userID.(uint))
+	if err != nil {
+		logger.Error("查询导出任务失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var exportResp response.DataExportResponse
+	if err := copier.Copy(&exportResp, exportDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "export_status_fetched"), "data": exportResp})
+}
+
+// DownloadExport 下载已生成的个人数据归档
+func (ah AccountHandler) DownloadExport(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	exportID, err := strconv.ParseUint(context.Param("id"), 10, 0)
+	if err != nil {
+		logger.Error("导出任务ID格式错误", zap.Error(err))
+		context.Error(invalidParam(errInvalidExportID, err))
+		return
+	}
+
+	path, fileName, err := ah.accountService.ExportFile(context.Request.Context(), uint(exportID), userID.(uint))
+	if err != nil {
+		logger.Error("下载个人数据失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.FileAttachment(path, fileName)
+}
+
+// DeleteAccount 申请注销账号（冷静期后删除，可选择匿名化或删除评论）
+func (ah AccountHandler) DeleteAccount(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	var req request.DeleteAccountRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		logger.Error("注销账号参数绑定失败", zap.Error(err))
+		context.Error(invalidParam(errInvalidParams, err))
+		return
+	}
+
+	deleteDTO := DTO.DeleteAccountDTO{UserID: userID.(uint), Password: req.Password, CommentMode: req.Comments, IP: context.ClientIP()}
+	deletionDTO, err := ah.accountService.RequestDeletion(context.Request.Context(), &deleteDTO)
+	if err != nil {
+		logger.Error("申请注销账号失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var deletionResp response.AccountDeletionResponse
+	if err := copier.Copy(&deletionResp, deletionDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusAccepted, gin.H{"msg": message(context, "deletion_requested"), "data": deletionResp})
+}
+
+// CancelDeletion 撤销注销申请
+func (ah AccountHandler) CancelDeletion(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	if err := ah.accountService.CancelDeletion(context.Request.Context(), userID.(uint), context.ClientIP()); err != nil {
+		logger.Error("撤销注销申请失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "deletion_cancelled")})
+}
+
+// UpdateLocale 设置接口提示信息的语言偏好
+func (ah AccountHandler) UpdateLocale(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	var req request.UpdateLocaleRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		logger.Error("语言偏好参数绑定失败", zap.Error(err))
+		context.Error(invalidParam(errInvalidParams, err))
+		return
+	}
+
+	locale, err := ah.accountService.UpdateLocale(context.Request.Context(), userID.(uint), req.Locale)
+	if err != nil {
+		logger.Error("设置语言偏好失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	// 提示信息直接使用新设置的语言
+	if locale != "" {
+		middleware.SetLocale(context, locale)
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "locale_updated"), "data": response.LocaleResponse{Locale: locale}})
+}
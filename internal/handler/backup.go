@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type BackupHandler struct {
+	backupService *service.BackupService
+}
+
+func NewBackupHandler(backupService *service.BackupService) *BackupHandler {
+	return &BackupHandler{
+		backupService: backupService,
+	}
+}
+
+// Export 下载整站数据归档（tar.gz，仅管理员）
+func (bh BackupHandler) Export(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	if err := bh.backupService.CheckAdmin(context.Request.Context(), userID.(uint)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			context.Error(errUnauthorized.Wrap(err))
+			return
+		}
+		context.Error(err)
+		return
+	}
+
+	// 归档边生成边输出，开始写出后无法再返回错误响应，失败时只能中断连接并记录日志
+	fileName := "go-my-blog-" + time.Now().Format("20060102-150405") + ".tar.gz"
+	context.Header("Content-Type", "application/gzip")
+	context.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	context.Status(http.StatusOK)
+	if _, err := bh.backupService.Export(context.Request.Context(), context.Writer); err != nil {
+		logger.Error("导出数据失败", zap.Error(err))
+		context.Abort()
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"github.com/jinzhu/copier"
 	"go.uber.org/zap"
 )
@@ -34,30 +33,72 @@ func (ch CommentHandler) DeleteComment(context *gin.Context) {
 	commentIdStr := context.Param("id")
 	if commentIdStr == "" {
 		logger.Error("评论id不能为空")
-		context.JSON(400, gin.H{"error": "commentId is required"})
+		context.Error(errCommentIDRequired)
+		return
 	}
 
 	userID, exists := context.Get("userID")
 	if !exists {
 		logger.Warn("用户授权失败")
 		// 如果用户ID不存在，返回未授权错误
-		context.JSON(http.StatusUnauthorized, gin.H{"msg": "Unauthorized: no user ID found in context"})
+		context.Error(errUnauthorized)
 		return
 	}
 
 	commentId, err := strconv.ParseUint(commentIdStr, 10, 0)
 	if err != nil {
 		logger.Error("评论ID格式错误", zap.Error(err))
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "评论ID格式错误：" + err.Error()})
+		context.Error(invalidParam(errInvalidCommentID, err))
 		return
 	}
 
-	err = ch.commentService.DeleteComment(uint(commentId), userID.(uint))
+	err = ch.commentService.DeleteComment(context.Request.Context(), uint(commentId), userID.(uint))
 	if err != nil {
 		logger.Error("删除评论失败", zap.Error(err))
-		context.JSON(http.StatusInternalServerError, gin.H{"msg": "删除评论失败：" + err.Error()})
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "comment_deleted")})
+}
+
+// UpdateComment 编辑评论
+func (ch CommentHandler) UpdateComment(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	commentId, err := strconv.ParseUint(context.Param("id"), 10, 0)
+	if err != nil {
+		logger.Error("评论ID格式错误", zap.Error(err))
+		context.Error(invalidParam(errInvalidCommentID, err))
+		return
+	}
+
+	var req request.UpdateCommentRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		logger.Error("编辑评论参数绑定失败", zap.Error(err))
+		context.Error(invalidParam(errInvalidParams, err))
+		return
+	}
+
+	updateCommentDTO := DTO.UpdateCommentDTO{ID: uint(commentId), Content: req.Content, ContentFormat: req.ContentFormat, UserID: userID.(uint), IP: context.ClientIP()}
+	commentDTO, err := ch.commentService.UpdateComment(context.Request.Context(), &updateCommentDTO)
+	if err != nil {
+		logger.Error("编辑评论失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var commentResp response.UpdateCommentResponse
+	if err = copier.Copy(&commentResp, commentDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
 	}
-	context.JSON(http.StatusOK, gin.H{"msg": "删除评论成功"})
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "comment_updated"), "data": commentResp})
 }
 
 func (ch CommentHandler) CreateComment(context *gin.Context) {
@@ -65,19 +106,19 @@ func (ch CommentHandler) CreateComment(context *gin.Context) {
 	userID, exists := context.Get("userID")
 	if !exists {
 		logger.Warn("用户授权失败")
-		context.JSON(http.StatusUnauthorized, gin.H{"msg": "Unauthorized: no user ID found in context"})
+		context.Error(errUnauthorized)
 		return
 	}
 	postIDStr := context.Param("postID")
 	if postIDStr == "" {
 		logger.Error("文章ID不能为空")
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "文章ID不能为空"})
+		context.Error(errPostIDRequired)
 		return
 	}
 	postID, err := strconv.ParseUint(postIDStr, 10, 0)
 	if err != nil {
 		logger.Error("文章ID格式错误", zap.Error(err))
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "文章ID格式错误：" + err.Error()})
+		context.Error(invalidParam(errInvalidPostID, err))
 		return
 	}
 
@@ -85,51 +126,215 @@ func (ch CommentHandler) CreateComment(context *gin.Context) {
 	req.PostID = uint(postID)
 	if err := context.ShouldBindJSON(&req); err != nil {
 		logger.Error("创建参数绑定失败", zap.Error(err))
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "参数错误：" + err.Error()})
+		context.Error(invalidParam(errInvalidParams, err))
 		return
 	}
 
-	if err := validator.New().Struct(req); err != nil {
-		logger.Error("创建参数校验失败", zap.Error(err))
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "参数错误：" + err.Error()})
-	}
-
-	createCommentDTO := DTO.CreateCommentDTO{Content: req.Content, PostID: req.PostID}
-	commentRespDTO, err := ch.commentService.CreateComment(userID.(uint), &createCommentDTO)
+	createCommentDTO := DTO.CreateCommentDTO{Content: req.Content, ContentFormat: req.ContentFormat, PostID: req.PostID, ParentID: req.ParentID, IP: context.ClientIP()}
+	commentRespDTO, err := ch.commentService.CreateComment(context.Request.Context(), userID.(uint), &createCommentDTO)
 	if err != nil {
 		logger.Error("创建评论失败", zap.Error(err))
 		// 如果创建失败，返回服务器内部错误信息
-		context.JSON(http.StatusInternalServerError, gin.H{"msg": "创建评论失败：" + err.Error()})
+		context.Error(err)
 		return
 	}
 
 	var commentResp response.CreateCommentResponse
 	if err = copier.Copy(&commentResp, &commentRespDTO); err != nil {
 		logger.Error("拷贝失败", zap.Error(err))
-		context.JSON(http.StatusInternalServerError, gin.H{"msg": "拷贝失败：" + err.Error()})
+		context.Error(err)
+		return
 	}
 
-	context.JSON(http.StatusOK, gin.H{"msg": "评论创建成功", "data": commentResp})
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "comment_created"), "data": commentResp})
 }
 
 func (ch CommentHandler) CommentList(context *gin.Context) {
 	postIDStr := context.Param("postID")
 	if postIDStr == "" {
 		logger.Error("文章ID不能为空")
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "文章ID不能为空"})
+		context.Error(errPostIDRequired)
 		return
 	}
 	postID, err := strconv.ParseUint(postIDStr, 10, 0)
 	if err != nil {
 		logger.Error("文章ID格式错误", zap.Error(err))
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "文章ID格式错误：" + err.Error()})
+		context.Error(invalidParam(errInvalidPostID, err))
+		return
+	}
+
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
 		return
 	}
 
-	comments, err := ch.commentService.CommentList(uint(postID))
+	comments, err := ch.commentService.CommentList(context.Request.Context(), uint(postID), userID.(uint))
 	if err != nil {
 		logger.Error("获取评论列表失败", zap.Error(err))
-		context.JSON(http.StatusInternalServerError, gin.H{"msg": "获取评论列表失败：" + err.Error()})
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "comment_list_fetched"), "data": comments})
+}
+
+// CommentThread 获取评论所在的整棵回复树
+func (ch CommentHandler) CommentThread(context *gin.Context) {
+	commentIdStr := context.Param("id")
+	commentId, err := strconv.ParseUint(commentIdStr, 10, 0)
+	if err != nil {
+		logger.Error("评论ID格式错误", zap.Error(err))
+		context.Error(invalidParam(errInvalidCommentID, err))
+		return
+	}
+
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	thread, err := ch.commentService.CommentThread(context.Request.Context(), uint(commentId), userID.(uint))
+	if err != nil {
+		logger.Error("获取评论回复树失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var threadResp response.CommentDetailResponse
+	if err := copier.Copy(&threadResp, thread); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "comment_thread_fetched"), "data": threadResp})
+}
+
+// ModerationQueue 审核队列（仅版主）
+func (ch CommentHandler) ModerationQueue(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	var req request.ModerationQueueRequest
+	if err := context.ShouldBindQuery(&req); err != nil {
+		logger.Error("审核队列参数绑定失败", zap.Error(err))
+		context.Error(invalidParam(errInvalidParams, err))
+		return
+	}
+	req.SetDefault()
+
+	queueDTO := DTO.ModerationQueueDTO{Status: req.Status, PageNum: req.PageNum, PageSize: req.PageSize}
+	listDTO, err := ch.commentService.ModerationQueue(context.Request.Context(), userID.(uint), &queueDTO)
+	if err != nil {
+		logger.Error("获取审核队列失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var listResp response.ModerationCommentListResponse
+	if err := copier.Copy(&listResp, listDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "moderation_queue_fetched"), "data": listResp})
+}
+
+// ModerateComments 批量通过/拒绝/标记垃圾评论（仅版主）
+func (ch CommentHandler) ModerateComments(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	var req request.ModerateCommentsRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		logger.Error("批量审核参数绑定失败", zap.Error(err))
+		context.Error(invalidParam(errInvalidParams, err))
+		return
+	}
+
+	moderateDTO := DTO.ModerateCommentsDTO{IDs: req.IDs, Action: req.Action, ModeratorID: userID.(uint)}
+	resultDTO, err := ch.commentService.ModerateComments(context.Request.Context(), &moderateDTO)
+	if err != nil {
+		logger.Error("批量审核评论失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var resultResp response.ModerateResultResponse
+	if err := copier.Copy(&resultResp, resultDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "comments_moderated"), "data": resultResp})
+}
+
+// GuestChallenge 获取游客评论的工作量证明题目
+func (ch CommentHandler) GuestChallenge(context *gin.Context) {
+	challengeDTO, err := ch.commentService.IssueGuestChallenge()
+	if err != nil {
+		logger.Error("获取游客评论题目失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var challengeResp response.GuestChallengeResponse
+	if err := copier.Copy(&challengeResp, challengeDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	challengeResp.Algorithm = "find solution such that sha256(token + \":\" + solution) has `difficulty` leading zero bits"
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "guest_challenge_issued"), "data": challengeResp})
+}
+
+// CreateGuestComment 游客发表评论（无需登录，需提交工作量证明答案）
+func (ch CommentHandler) CreateGuestComment(context *gin.Context) {
+	postID, err := strconv.ParseUint(context.Param("postID"), 10, 0)
+	if err != nil {
+		logger.Error("文章ID格式错误", zap.Error(err))
+		context.Error(invalidParam(errInvalidPostID, err))
+		return
+	}
+
+	var req request.CreateGuestCommentRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		logger.Error("游客评论参数绑定失败", zap.Error(err))
+		context.Error(invalidParam(errInvalidParams, err))
+		return
+	}
+
+	var guestCommentDTO DTO.CreateGuestCommentDTO
+	if err := copier.Copy(&guestCommentDTO, &req); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	guestCommentDTO.PostID = uint(postID)
+	guestCommentDTO.IP = context.ClientIP()
+
+	commentRespDTO, err := ch.commentService.CreateGuestComment(context.Request.Context(), &guestCommentDTO)
+	if err != nil {
+		logger.Error("游客评论失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var commentResp response.CreateCommentResponse
+	if err = copier.Copy(&commentResp, commentRespDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
 	}
-	context.JSON(http.StatusOK, gin.H{"msg": "获取评论列表成功", "data": comments})
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "comment_pending"), "data": commentResp})
 }
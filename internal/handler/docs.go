@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"go-my-blog/pkg/openapi"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocsHandler 提供根据路由表生成的 OpenAPI 文档及交互式文档页面
+type DocsHandler struct {
+	doc *openapi.Document
+}
+
+func NewDocsHandler(doc *openapi.Document) *DocsHandler {
+	return &DocsHandler{
+		doc: doc,
+	}
+}
+
+// Spec 返回 OpenAPI 3.1 文档（JSON）
+func (dh DocsHandler) Spec(context *gin.Context) {
+	context.JSON(http.StatusOK, dh.doc)
+}
+
+// Page 返回交互式文档页面，页面从 /openapi.json 加载接口列表
+func (dh DocsHandler) Page(context *gin.Context) {
+	context.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsHTML)
+}
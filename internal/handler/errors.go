@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"go-my-blog/pkg/apperr"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	// errUnauthorized 上下文中没有登录用户（未经过 JWTAuth 中间件）
+	errUnauthorized    = apperr.Unauthorized("unauthorized", "未登录或登录已失效")
+	errRouteNotFound   = apperr.NotFound("route_not_found", "接口不存在")
+	errAuthorNotFound  = apperr.NotFound("author_not_found", "作者不存在")
+	errSitemapNotFound = apperr.NotFound("sitemap_not_found", "站点地图不存在")
+
+	// 请求参数未通过校验，details.fields 列出每个字段的错误
+	errValidationFailed = apperr.Validation("validation_failed", "请求参数校验失败")
+
+	// 路径、查询参数或请求体无法解析
+	errInvalidParams     = apperr.BadRequest("invalid_params", "请求参数错误")
+	errInvalidID         = apperr.BadRequest("invalid_id", "ID格式错误")
+	errInvalidPostID     = apperr.BadRequest("invalid_post_id", "文章ID格式错误")
+	errPostIDRequired    = apperr.BadRequest("post_id_required", "文章ID不能为空")
+	errInvalidCommentID  = apperr.BadRequest("invalid_comment_id", "评论ID格式错误")
+	errCommentIDRequired = apperr.BadRequest("comment_id_required", "评论ID不能为空")
+	errInvalidMediaID    = apperr.BadRequest("invalid_media_id", "媒体ID格式错误")
+	errInvalidExportID   = apperr.BadRequest("invalid_export_id", "导出任务ID格式错误")
+	errInvalidFeedMode   = apperr.BadRequest("invalid_feed_mode", "mode 只能为 full 或 excerpt")
+	errMediaFileRequired = apperr.BadRequest("media_file_required", "请通过 file 字段上传文件")
+)
+
+// invalidParam 参数绑定失败：未通过校验时返回 422，字段错误由错误处理中间件翻译为字段列表；
+// 无法解析时返回 e 并附带原因
+func invalidParam(e *apperr.Error, err error) error {
+	if err == nil {
+		return e
+	}
+	if _, ok := err.(validator.ValidationErrors); ok {
+		return errValidationFailed.Wrap(err)
+	}
+	return e.Wrap(err).WithDetails(map[string]interface{}{"reason": err.Error()})
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/feed"
+	"go-my-blog/pkg/logger"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 订阅源格式
+const (
+	feedFormatRSS  = "rss"
+	feedFormatAtom = "atom"
+	feedFormatJSON = "json"
+)
+
+type FeedHandler struct {
+	feedService *service.FeedService
+}
+
+func NewFeedHandler(feedService *service.FeedService) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+	}
+}
+
+// RSS RSS 2.0 订阅源
+func (fh FeedHandler) RSS(context *gin.Context) {
+	fh.serve(context, feedFormatRSS)
+}
+
+// Atom Atom 1.0 订阅源
+func (fh FeedHandler) Atom(context *gin.Context) {
+	fh.serve(context, feedFormatAtom)
+}
+
+// JSON JSON Feed 1.1 订阅源
+func (fh FeedHandler) JSON(context *gin.Context) {
+	fh.serve(context, feedFormatJSON)
+}
+
+// serve 生成订阅源并处理条件请求（If-None-Match / If-Modified-Since），未变化时返回 304
+func (fh FeedHandler) serve(context *gin.Context, format string) {
+	mode := context.DefaultQuery("mode", config.Conf.Feed.Mode)
+	if mode != DTO.FeedModeFull && mode != DTO.FeedModeExcerpt {
+		context.Error(errInvalidFeedMode)
+		return
+	}
+
+	queryDTO := DTO.FeedQueryDTO{
+		Author: context.Param("username"),
+		Tag:    context.Param("tag"),
+		Mode:   mode,
+		Path:   context.Request.URL.Path,
+	}
+	result, err := fh.feedService.BuildFeed(context.Request.Context(), &queryDTO)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			context.Error(errAuthorNotFound.Wrap(err))
+			return
+		}
+		logger.Error("生成订阅源失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	if writeNotModified(context, result.ETag(format+":"+mode), result.Updated) {
+		return
+	}
+
+	var body []byte
+	var contentType string
+	switch format {
+	case feedFormatRSS:
+		body, err = feed.RSS(result)
+		contentType = feed.ContentTypeRSS
+	case feedFormatAtom:
+		body, err = feed.Atom(result)
+		contentType = feed.ContentTypeAtom
+	default:
+		body, err = feed.JSON(result)
+		contentType = feed.ContentTypeJSON
+	}
+	if err != nil {
+		logger.Error("订阅源编码失败", zap.String("format", format), zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.Data(http.StatusOK, contentType, body)
+}
+
+// writeNotModified 写出缓存相关响应头；客户端缓存仍然有效时直接返回 304 并返回 true
+func writeNotModified(context *gin.Context, etag string, lastModified time.Time) bool {
+	context.Header("ETag", etag)
+	context.Header("Cache-Control", "public, max-age=300")
+	if !lastModified.IsZero() {
+		context.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(context.Request, etag, lastModified) {
+		context.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// notModified 判断客户端缓存是否仍然有效：有 If-None-Match 时只比较 ETag，否则比较 If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err == nil && !lastModified.Truncate(time.Second).After(since) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveConditional 模拟订阅源接口：缓存有效时返回 304，否则返回 200 和内容
+func serveConditional(etag string, lastModified time.Time, header http.Header) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(w)
+	context.Request = httptest.NewRequest(http.MethodGet, "/feed.xml", nil)
+	for name, values := range header {
+		context.Request.Header[name] = values
+	}
+	if !writeNotModified(context, etag, lastModified) {
+		context.Data(http.StatusOK, "application/rss+xml", []byte("<rss/>"))
+	}
+	context.Writer.WriteHeaderNow()
+	return w
+}
+
+func TestWriteNotModified(t *testing.T) {
+	const etag = `W/"abc123"`
+	updated := time.Date(2026, 10, 1, 8, 0, 0, 500, time.UTC)
+
+	first := serveConditional(etag, updated, nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("首次请求状态码 = %d, want 200", first.Code)
+	}
+	if first.Header().Get("ETag") != etag {
+		t.Errorf("ETag = %q", first.Header().Get("ETag"))
+	}
+	lastModified := first.Header().Get("Last-Modified")
+	if lastModified != "Thu, 01 Oct 2026 08:00:00 GMT" {
+		t.Errorf("Last-Modified = %q", lastModified)
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   int
+	}{
+		{"ETag 相同", http.Header{"If-None-Match": {etag}}, http.StatusNotModified},
+		{"强校验值与弱校验值比较", http.Header{"If-None-Match": {`"abc123"`}}, http.StatusNotModified},
+		{"多个 ETag 之一相同", http.Header{"If-None-Match": {`W/"old", W/"abc123"`}}, http.StatusNotModified},
+		{"通配符", http.Header{"If-None-Match": {"*"}}, http.StatusNotModified},
+		{"ETag 不同", http.Header{"If-None-Match": {`W/"old"`}}, http.StatusOK},
+		{"Last-Modified 原样带回", http.Header{"If-Modified-Since": {lastModified}}, http.StatusNotModified},
+		{"缓存晚于更新时间", http.Header{"If-Modified-Since": {updated.Add(time.Hour).Format(http.TimeFormat)}}, http.StatusNotModified},
+		{"缓存早于更新时间", http.Header{"If-Modified-Since": {updated.Add(-time.Hour).Format(http.TimeFormat)}}, http.StatusOK},
+		// 同时携带时以 If-None-Match 为准
+		{"ETag 不同但时间未变", http.Header{"If-None-Match": {`W/"old"`}, "If-Modified-Since": {lastModified}}, http.StatusOK},
+		{"日期格式错误", http.Header{"If-Modified-Since": {"yesterday"}}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := serveConditional(etag, updated, tt.header)
+		if w.Code != tt.want {
+			t.Errorf("%s: 状态码 = %d, want %d", tt.name, w.Code, tt.want)
+		}
+		if w.Code == http.StatusNotModified && w.Body.Len() != 0 {
+			t.Errorf("%s: 304 响应不应有正文", tt.name)
+		}
+	}
+}
+
+func TestWriteNotModifiedWithoutPosts(t *testing.T) {
+	// 没有文章时不输出 Last-Modified，If-Modified-Since 不生效
+	w := serveConditional(`W/"empty"`, time.Time{}, http.Header{"If-Modified-Since": {time.Now().Format(http.TimeFormat)}})
+	if w.Code != http.StatusOK {
+		t.Errorf("状态码 = %d, want 200", w.Code)
+	}
+	if w.Header().Get("Last-Modified") != "" {
+		t.Error("没有文章时不应输出 Last-Modified")
+	}
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/theme"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// FrontendHandler 服务端渲染的 HTML 页面
+type FrontendHandler struct {
+	frontendService *service.FrontendService
+	theme           *theme.Theme
+}
+
+func NewFrontendHandler(frontendService *service.FrontendService, theme *theme.Theme) *FrontendHandler {
+	return &FrontendHandler{
+		frontendService: frontendService,
+		theme:           theme,
+	}
+}
+
+// Static 主题静态资源
+func (fh FrontendHandler) Static() fs.FS {
+	return fh.theme.Static()
+}
+
+// Home 首页：最新文章列表
+func (fh FrontendHandler) Home(context *gin.Context) {
+	page := theme.ParsePage(context.Param("page"), context.Query("page"))
+	postList, err := fh.frontendService.ListPosts(context.Request.Context(), "", page)
+	if err != nil {
+		fh.fail(context, err)
+		return
+	}
+	fh.renderList(context, "home", "", postList, page, "/", gin.H{})
+}
+
+// Tag 标签页
+func (fh FrontendHandler) Tag(context *gin.Context) {
+	tag := context.Param("tag")
+	page := theme.ParsePage(context.Param("page"), context.Query("page"))
+	postList, err := fh.frontendService.ListPosts(context.Request.Context(), tag, page)
+	if err != nil {
+		fh.fail(context, err)
+		return
+	}
+	if postList.Total == 0 {
+		fh.NotFound(context)
+		return
+	}
+	fh.renderList(context, "tag", "#"+tag, postList, page, "/tags/"+url.PathEscape(tag), gin.H{"Tag": tag})
+}
+
+// Author 作者页
+func (fh FrontendHandler) Author(context *gin.Context) {
+	username := context.Param("username")
+	page := theme.ParsePage(context.Param("page"), context.Query("page"))
+	postList, err := fh.frontendService.ListAuthorPosts(context.Request.Context(), username, page)
+	if err != nil {
+		fh.fail(context, err)
+		return
+	}
+	fh.renderList(context, "author", username, postList, page, "/authors/"+url.PathEscape(username), gin.H{"Author": username})
+}
+
+// Post 文章页
+func (fh FrontendHandler) Post(context *gin.Context) {
+	postID, err := strconv.ParseUint(context.Param("id"), 10, 0)
+	if err != nil {
+		fh.NotFound(context)
+		return
+	}
+	detail, err := fh.frontendService.PostDetail(context.Request.Context(), uint(postID))
+	if err != nil {
+		fh.fail(context, err)
+		return
+	}
+
+	data := gin.H{
+		"Title":       detail.Meta.Title,
+		"Description": detail.Meta.Description,
+		"Canonical":   detail.Meta.CanonicalURL,
+		"Robots":      detail.Meta.Robots,
+		"MetaTags":    detail.Meta.Tags,
+		"JSONLD":      detail.Meta.JSONLD,
+		"Post":        detail,
+	}
+	fh.render(context, http.StatusOK, "post", data)
+}
+
+// Archive 归档页
+func (fh FrontendHandler) Archive(context *gin.Context) {
+	archive, err := fh.frontendService.Archive(context.Request.Context())
+	if err != nil {
+		fh.fail(context, err)
+		return
+	}
+	fh.render(context, http.StatusOK, "archive", gin.H{"Title": "归档", "Archive": archive})
+}
+
+// NotFound 未匹配的地址：接口路径返回 JSON，其余返回主题的 404 页面
+func (fh FrontendHandler) NotFound(context *gin.Context) {
+	if strings.HasPrefix(context.Request.URL.Path, "/api/") {
+		context.Error(errRouteNotFound)
+		return
+	}
+	fh.render(context, http.StatusNotFound, "404", gin.H{"Title": "页面不存在", "Robots": "noindex"})
+}
+
+// renderList 渲染带分页的文章列表页，页码超出范围时返回 404
+func (fh FrontendHandler) renderList(context *gin.Context, page string, title string, postList *DTO.PostListDTO, pageNum int, basePath string, data gin.H) {
+	pagination := theme.NewPagination(pageNum, postList.PageSize, postList.Total, basePath)
+	if pageNum > pagination.TotalPages {
+		fh.NotFound(context)
+		return
+	}
+	data["Title"] = title
+	data["Canonical"] = config.Conf.Site.AbsoluteURL(pagination.URL(pageNum))
+	data["Posts"] = postList.Posts
+	data["Pagination"] = pagination
+	if title == "" {
+		data["Description"] = config.Conf.Site.Description
+	}
+	fh.render(context, http.StatusOK, page, data)
+}
+
+// render 先渲染到缓冲区，模板出错时不会输出半个页面
+func (fh FrontendHandler) render(context *gin.Context, status int, page string, data gin.H) {
+	data["Site"] = config.Conf.Site
+	data["Year"] = time.Now().Year()
+
+	var buf bytes.Buffer
+	if err := fh.theme.Render(&buf, page, data); err != nil {
+		logger.Error("页面渲染失败", zap.String("page", page), zap.Error(err))
+		context.String(http.StatusInternalServerError, "页面渲染失败")
+		return
+	}
+	context.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+func (fh FrontendHandler) fail(context *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		fh.NotFound(context)
+		return
+	}
+	logger.Error("页面数据查询失败", zap.Error(err))
+	context.String(http.StatusInternalServerError, "服务器内部错误")
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"errors"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/request"
+	"go-my-blog/internal/response"
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/logger"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+	"go.uber.org/zap"
+)
+
+type MediaHandler struct {
+	mediaService *service.MediaService
+}
+
+func NewMediaHandler(mediaService *service.MediaService) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+	}
+}
+
+// UploadMedia 上传图片（multipart/form-data，字段名 file）
+func (mh MediaHandler) UploadMedia(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	// 限制请求体大小（额外预留 1MB 给 multipart 边界和其他字段），超出时直接中断读取
+	maxSize := config.Conf.Media.GetMaxSize()
+	context.Request.Body = http.MaxBytesReader(context.Writer, context.Request.Body, maxSize+1<<20)
+	fileHeader, err := context.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			context.Error(service.MediaTooLarge())
+			return
+		}
+		logger.Error("上传文件读取失败", zap.Error(err))
+		context.Error(invalidParam(errMediaFileRequired, err))
+		return
+	}
+	if fileHeader.Size > maxSize {
+		context.Error(service.MediaTooLarge())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error("上传文件打开失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		logger.Error("上传文件读取失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	uploadDTO := DTO.UploadMediaDTO{UserID: userID.(uint), FileName: fileHeader.Filename, Data: data}
+	mediaDTO, err := mh.mediaService.Upload(context.Request.Context(), &uploadDTO)
+	if err != nil {
+		logger.Error("上传媒体失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var mediaResp response.MediaResponse
+	if err := copier.Copy(&mediaResp, mediaDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "media_uploaded"), "data": mediaResp})
+}
+
+// ListMedia 当前用户上传的媒体列表
+func (mh MediaHandler) ListMedia(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	var req request.ListMediaRequest
+	if err := context.ShouldBindQuery(&req); err != nil {
+		logger.Error("媒体列表参数绑定失败", zap.Error(err))
+		context.Error(invalidParam(errInvalidParams, err))
+		return
+	}
+	req.SetDefault()
+
+	listDTO := DTO.ListMediaDTO{UserID: userID.(uint), PageNum: req.PageNum, PageSize: req.PageSize}
+	mediaListDTO, err := mh.mediaService.List(context.Request.Context(), &listDTO)
+	if err != nil {
+		logger.Error("获取媒体列表失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var listResp response.MediaListResponse
+	if err := copier.Copy(&listResp, mediaListDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "media_list_fetched"), "data": listResp})
+}
+
+// MediaDetail 媒体详情（含引用该媒体的文章）
+func (mh MediaHandler) MediaDetail(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	mediaID, err := strconv.ParseUint(context.Param("id"), 10, 0)
+	if err != nil {
+		logger.Error("媒体ID格式错误", zap.Error(err))
+		context.Error(invalidParam(errInvalidMediaID, err))
+		return
+	}
+
+	mediaDTO, err := mh.mediaService.Detail(context.Request.Context(), uint(mediaID), userID.(uint))
+	if err != nil {
+		logger.Error("获取媒体详情失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var mediaResp response.MediaResponse
+	if err := copier.Copy(&mediaResp, mediaDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "media_detail_fetched"), "data": mediaResp})
+}
+
+// DeleteMedia 删除媒体（仍被文章引用时需传 force=true）
+func (mh MediaHandler) DeleteMedia(context *gin.Context) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
+	}
+
+	mediaID, err := strconv.ParseUint(context.Param("id"), 10, 0)
+	if err != nil {
+		logger.Error("媒体ID格式错误", zap.Error(err))
+		context.Error(invalidParam(errInvalidMediaID, err))
+		return
+	}
+
+	var req request.DeleteMediaRequest
+	if err := context.ShouldBindQuery(&req); err != nil {
+		logger.Error("删除媒体参数绑定失败", zap.Error(err))
+		context.Error(invalidParam(errInvalidParams, err))
+		return
+	}
+
+	deleteDTO := DTO.DeleteMediaDTO{ID: uint(mediaID), UserID: userID.(uint), Force: req.Force}
+	if err := mh.mediaService.Delete(context.Request.Context(), &deleteDTO); err != nil {
+		logger.Error("删除媒体失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "media_deleted")})
+}
@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"go-my-blog/internal/middleware"
+	"go-my-blog/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// message 按请求的语言翻译成功提示，文案键为 message.<key>
+func message(c *gin.Context, key string) string {
+	return i18n.T(middleware.GetLocale(c), "message."+key)
+}
@@ -11,7 +11,6 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"github.com/jinzhu/copier"
 	"go.uber.org/zap"
 )
@@ -36,7 +35,7 @@ func (ph *PostHandler) CreatePost(c *gin.Context) {
 	if !exists {
 		logger.Warn("用户授权失败")
 		// 如果用户ID不存在，返回未授权错误
-		c.JSON(http.StatusUnauthorized, gin.H{"msg": "Unauthorized: no user ID found in context"})
+		c.Error(errUnauthorized)
 		return
 	}
 
@@ -45,41 +44,49 @@ func (ph *PostHandler) CreatePost(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		// 如果参数绑定失败，返回参数错误信息
 		logger.Error("创建参数绑定失败", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"msg": "参数错误：" + err.Error()})
+		c.Error(invalidParam(errInvalidParams, err))
 		return
 	}
 
-	// 与CreatePostRequest中的validate标签配合使用，需要在此显示校验
-	if err := validator.New().Struct(req); err != nil {
-		logger.Error("创建参数校验失败", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"msg": "参数错误：" + err.Error()})
-	}
-
 	// 调用PostService的CreatePost方法创建文章，传入用户ID和请求参数
-	createPostDTO := DTO.CreatePostDTO{Title: req.Title, Content: req.Content}
-	postRespDTO, err := ph.postService.CreatePost(userID.(uint), &createPostDTO)
+	createPostDTO := DTO.CreatePostDTO{
+		Title:                req.Title,
+		Content:              req.Content,
+		ContentFormat:        req.ContentFormat,
+		Status:               req.Status,
+		TagNames:             req.Tags,
+		CommentPolicy:        req.CommentPolicy,
+		CommentAutoCloseDays: req.CommentAutoCloseDays,
+		CoverMediaID:         req.CoverMediaID,
+		SEOTitle:             req.SEOTitle,
+		SEODescription:       req.SEODescription,
+		CanonicalURL:         req.CanonicalURL,
+		NoIndex:              req.NoIndex,
+	}
+	postRespDTO, err := ph.postService.CreatePost(c.Request.Context(), userID.(uint), &createPostDTO)
 	if err != nil {
 		logger.Error("创建文章失败", zap.Error(err))
 		// 如果创建失败，返回服务器内部错误信息
-		c.JSON(http.StatusInternalServerError, gin.H{"msg": "创建文章失败：" + err.Error()})
+		c.Error(err)
 		return
 	}
 
 	var postResp response.CreatePostResponse
 	if err = copier.Copy(&postResp, &postRespDTO); err != nil {
 		logger.Error("拷贝失败", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"msg": "拷贝失败：" + err.Error()})
+		c.Error(err)
+		return
 	}
 
 	// 创建成功，返回成功响应和文章数据
-	c.JSON(http.StatusOK, gin.H{"msg": "文章创建成功", "data": postResp})
+	c.JSON(http.StatusOK, gin.H{"msg": message(c, "post_created"), "data": postResp})
 }
 
 func (ph *PostHandler) UpdatePost(c *gin.Context) {
 	idStr := c.Param("id")
 	if idStr == "" {
 		logger.Error("文章ID不能为空")
-		c.JSON(http.StatusBadRequest, gin.H{"msg": "文章ID不能为空"})
+		c.Error(errPostIDRequired)
 		return
 	}
 
@@ -87,47 +94,48 @@ func (ph *PostHandler) UpdatePost(c *gin.Context) {
 	if !exists {
 		logger.Warn("用户授权失败")
 		// 如果用户ID不存在，返回未授权错误
-		c.JSON(http.StatusUnauthorized, gin.H{"msg": "Unauthorized: no user ID found in context"})
+		c.Error(errUnauthorized)
 		return
 	}
 
 	var req request.UpdatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		logger.Error("更新文章参数绑定失败", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"msg": "请求参数错误：" + err.Error()})
+		c.Error(invalidParam(errInvalidParams, err))
 		return
 	}
 
 	var updatePostDTO DTO.UpdatePostDTO
 	if err := copier.Copy(&updatePostDTO, &req); err != nil {
 		logger.Error("拷贝失败", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"msg": "拷贝失败：" + err.Error()})
+		c.Error(err)
 		return
 	}
+	updatePostDTO.TagNames = req.Tags
 	idUint, err := strconv.ParseUint(idStr, 10, 0)
 	if err != nil {
 		logger.Error("文章ID格式错误", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"msg": "文章ID格式错误：" + err.Error()})
+		c.Error(invalidParam(errInvalidPostID, err))
 		return
 	}
 	updatePostDTO.ID = uint(idUint)
 	updatePostDTO.UserID = userID.(uint)
 
-	postDTO, err := ph.postService.UpdatePost(&updatePostDTO)
+	postDTO, err := ph.postService.UpdatePost(c.Request.Context(), &updatePostDTO)
 	if err != nil {
 		logger.Error("更新文章失败", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"msg": "更新文章失败：" + err.Error()})
+		c.Error(err)
 		return
 	}
 
 	var updatePostResponse response.UpdatePostResponse
 	if err := copier.Copy(&updatePostResponse, &postDTO); err != nil {
 		logger.Error("拷贝失败", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"msg": "拷贝失败：" + err.Error()})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"msg": "更新文章成功", "data": updatePostResponse})
+	c.JSON(http.StatusOK, gin.H{"msg": message(c, "post_updated"), "data": updatePostResponse})
 
 }
 
@@ -135,7 +143,7 @@ func (ph *PostHandler) DeletePost(context *gin.Context) {
 	idStr := context.Param("id")
 	if idStr == "" {
 		logger.Error("文章ID不能为空")
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "文章ID不能为空"})
+		context.Error(errPostIDRequired)
 		return
 	}
 
@@ -143,30 +151,31 @@ func (ph *PostHandler) DeletePost(context *gin.Context) {
 	if !exists {
 		logger.Warn("用户授权失败")
 		// 如果用户ID不存在，返回未授权错误
-		context.JSON(http.StatusUnauthorized, gin.H{"msg": "Unauthorized: no user ID found in context"})
+		context.Error(errUnauthorized)
 		return
 	}
 
 	idUint, err := strconv.ParseUint(idStr, 10, 0)
 	if err != nil {
 		logger.Error("文章ID格式错误", zap.Error(err))
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "文章ID格式错误：" + err.Error()})
+		context.Error(invalidParam(errInvalidPostID, err))
 		return
 	}
 
-	err = ph.postService.DeletePost(uint(idUint), userID.(uint))
+	err = ph.postService.DeletePost(context.Request.Context(), uint(idUint), userID.(uint))
 	if err != nil {
 		logger.Error("删除文章失败", zap.Error(err))
-		context.JSON(http.StatusInternalServerError, gin.H{"msg": "删除文章失败：" + err.Error()})
+		context.Error(err)
+		return
 	}
-	context.JSON(http.StatusOK, gin.H{"msg": "删除文章成功"})
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "post_deleted")})
 }
 
 func (ph *PostHandler) PostList(context *gin.Context) {
 	var req request.PostListRequest
 	if err := context.ShouldBindQuery(&req); err != nil {
 		logger.Error("获取文章列表参数绑定失败", zap.Error(err))
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "请求参数错误：" + err.Error()})
+		context.Error(invalidParam(errInvalidParams, err))
 		return
 	}
 	req.SetDefault()
@@ -175,7 +184,21 @@ func (ph *PostHandler) PostList(context *gin.Context) {
 	if !exists {
 		logger.Warn("用户授权失败")
 		// 如果用户ID不存在，返回未授权错误
-		context.JSON(http.StatusUnauthorized, gin.H{"msg": "Unauthorized: no user ID found in context"})
+		context.Error(errUnauthorized)
+		return
+	}
+
+	// 解析作者筛选（支持 author=me）和日期区间
+	authorID, err := req.ResolveAuthorID(userID.(uint))
+	if err != nil {
+		logger.Error("获取文章列表作者参数错误", zap.Error(err))
+		context.Error(invalidParam(errInvalidParams, err))
+		return
+	}
+	startTime, endTime, err := req.ParseDateRange()
+	if err != nil {
+		logger.Error("获取文章列表日期参数错误", zap.Error(err))
+		context.Error(invalidParam(errInvalidParams, err))
 		return
 	}
 
@@ -183,54 +206,66 @@ func (ph *PostHandler) PostList(context *gin.Context) {
 	copyErr := copier.Copy(&listPostDTO, &req)
 	if copyErr != nil {
 		logger.Error("拷贝失败", zap.Error(copyErr))
-		context.JSON(http.StatusInternalServerError, gin.H{"msg": "拷贝失败：" + copyErr.Error()})
+		context.Error(copyErr)
 		return
 	}
 	listPostDTO.UserID = userID.(uint)
+	listPostDTO.AuthorID = authorID
+	listPostDTO.StartTime = startTime
+	listPostDTO.EndTime = endTime
 
-	postDTOList, err := ph.postService.PostList(&listPostDTO)
+	postDTOList, err := ph.postService.PostList(context.Request.Context(), &listPostDTO)
 	if err != nil {
 		logger.Error("获取文章列表失败", zap.Error(err))
-		context.JSON(http.StatusInternalServerError, gin.H{"msg": "获取文章列表失败：" + err.Error()})
+		context.Error(err)
 		return
 	}
 
 	var postListResponse response.PostListResponse
 	if err := copier.Copy(&postListResponse, &postDTOList); err != nil {
 		logger.Error("拷贝失败", zap.Error(err))
-		context.JSON(http.StatusInternalServerError, gin.H{"msg": "拷贝失败：" + err.Error()})
+		context.Error(err)
 		return
 	}
 
-	context.JSON(http.StatusOK, gin.H{"msg": "获取文章列表成功", "data": postListResponse})
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "post_list_fetched"), "data": postListResponse})
 }
 
 func (ph *PostHandler) PostDetail(context *gin.Context) {
 	idStr := context.Param("id")
 	if idStr == "" {
 		logger.Error("文章ID不能为空")
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "文章ID不能为空"})
+		context.Error(errPostIDRequired)
 		return
 	}
 
 	parseUint, parseErr := strconv.ParseUint(idStr, 10, 0)
 	if parseErr != nil {
 		logger.Error("文章ID格式错误", zap.Error(parseErr))
-		context.JSON(http.StatusBadRequest, gin.H{"msg": "文章ID格式错误：" + parseErr.Error()})
+		context.Error(invalidParam(errInvalidPostID, parseErr))
+		return
+	}
+
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return
 	}
 
-	postDetailDTO, err := ph.postService.PostDetail(uint(parseUint))
+	postDetailDTO, err := ph.postService.PostDetail(context.Request.Context(), uint(parseUint), userID.(uint))
 	if err != nil {
 		logger.Error("获取文章详情失败", zap.Error(err))
-		context.JSON(http.StatusInternalServerError, gin.H{"msg": "获取文章详情失败：" + err.Error()})
+		context.Error(err)
 		return
 	}
 
 	var postResp response.PostDetailResponse
 	if err := copier.Copy(&postResp, &postDetailDTO); err != nil {
 		logger.Error("拷贝失败", zap.Error(err))
-		context.JSON(http.StatusInternalServerError, gin.H{"msg": "拷贝失败：" + err.Error()})
+		context.Error(err)
+		return
 	}
 
-	context.JSON(http.StatusOK, gin.H{"msg": "获取文章详情成功", "data": postResp})
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "post_detail_fetched"), "data": postResp})
 }
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RedirectHandler struct {
+	redirectService *service.RedirectService
+}
+
+func NewRedirectHandler(redirectService *service.RedirectService) *RedirectHandler {
+	return &RedirectHandler{
+		redirectService: redirectService,
+	}
+}
+
+// Redirect 旧地址命中重定向时返回 301，否则交给后续处理器（404 页面或首页）
+func (rh RedirectHandler) Redirect(context *gin.Context) {
+	if context.Request.Method != http.MethodGet && context.Request.Method != http.MethodHead {
+		context.Next()
+		return
+	}
+	target, err := rh.redirectService.Resolve(context.Request.Context(), context.Request.URL)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error("重定向查询失败", zap.String("path", context.Request.URL.Path), zap.Error(err))
+		}
+		context.Next()
+		return
+	}
+	context.Redirect(http.StatusMovedPermanently, target)
+	context.Abort()
+}
+
+// LegacyQuery 首页带 WordPress 旧式参数（?p=、?page_id=）时尝试重定向，其余请求直接放行
+func (rh RedirectHandler) LegacyQuery(context *gin.Context) {
+	query := context.Request.URL.Query()
+	if query.Has("p") || query.Has("page_id") {
+		rh.Redirect(context)
+		return
+	}
+	context.Next()
+}
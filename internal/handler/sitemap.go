@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/sitemap"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SitemapHandler struct {
+	sitemapService *service.SitemapService
+}
+
+func NewSitemapHandler(sitemapService *service.SitemapService) *SitemapHandler {
+	return &SitemapHandler{
+		sitemapService: sitemapService,
+	}
+}
+
+// Sitemap 站点地图入口：URL 不多时直接输出 urlset，超过单文件上限时输出 sitemap 索引
+func (sh SitemapHandler) Sitemap(context *gin.Context) {
+	urls, sitemaps, err := sh.sitemapService.Root(context.Request.Context())
+	if err != nil {
+		logger.Error("生成站点地图失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var body []byte
+	if sitemaps != nil {
+		body, err = sitemap.Index(sitemaps)
+	} else {
+		body, err = sitemap.URLSet(urls)
+	}
+	sh.write(context, body, err)
+}
+
+// SitemapPage 拆分后的单个站点地图，地址形如 /sitemaps/2.xml
+func (sh SitemapHandler) SitemapPage(context *gin.Context) {
+	name := context.Param("page")
+	page, err := strconv.Atoi(strings.TrimSuffix(name, ".xml"))
+	if err != nil || !strings.HasSuffix(name, ".xml") {
+		context.Error(errSitemapNotFound)
+		return
+	}
+
+	urls, err := sh.sitemapService.Page(context.Request.Context(), page)
+	if err != nil {
+		logger.Error("生成站点地图失败", zap.Int("page", page), zap.Error(err))
+		context.Error(err)
+		return
+	}
+	if urls == nil {
+		context.Error(errSitemapNotFound)
+		return
+	}
+	body, err := sitemap.URLSet(urls)
+	sh.write(context, body, err)
+}
+
+// Robots robots.txt
+func (sh SitemapHandler) Robots(context *gin.Context) {
+	context.Header("Cache-Control", "public, max-age=3600")
+	context.String(http.StatusOK, sh.sitemapService.RobotsTxt())
+}
+
+func (sh SitemapHandler) write(context *gin.Context, body []byte, err error) {
+	if err != nil {
+		logger.Error("站点地图编码失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.Header("Cache-Control", "public, max-age=3600")
+	context.Data(http.StatusOK, sitemap.ContentType, body)
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/request"
+	"go-my-blog/internal/response"
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/logger"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+	"go.uber.org/zap"
+)
+
+type TrashHandler struct {
+	trashService *service.TrashService
+}
+
+func NewTrashHandler(trashService *service.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// ListPosts 回收站中的文章（作者看到自己的，管理员看到全部）
+func (th TrashHandler) ListPosts(context *gin.Context) {
+	listDTO, ok := th.bindList(context)
+	if !ok {
+		return
+	}
+	postListDTO, err := th.trashService.ListPosts(context.Request.Context(), listDTO)
+	if err != nil {
+		logger.Error("获取回收站文章失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var listResp response.TrashPostListResponse
+	if err := copier.Copy(&listResp, postListDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "trash_posts_fetched"), "data": listResp})
+}
+
+// ListComments 回收站中单独删除的评论
+func (th TrashHandler) ListComments(context *gin.Context) {
+	listDTO, ok := th.bindList(context)
+	if !ok {
+		return
+	}
+	commentListDTO, err := th.trashService.ListComments(context.Request.Context(), listDTO)
+	if err != nil {
+		logger.Error("获取回收站评论失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+
+	var listResp response.TrashCommentListResponse
+	if err := copier.Copy(&listResp, commentListDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "trash_comments_fetched"), "data": listResp})
+}
+
+// RestorePost 恢复文章（连同随文章删除的评论）
+func (th TrashHandler) RestorePost(context *gin.Context) {
+	userID, id, ok := th.bindTarget(context)
+	if !ok {
+		return
+	}
+	resultDTO, err := th.trashService.RestorePost(context.Request.Context(), id, userID)
+	if err != nil {
+		th.fail(context, "恢复文章失败", err)
+		return
+	}
+
+	var restoreResp response.RestorePostResponse
+	if err := copier.Copy(&restoreResp, resultDTO); err != nil {
+		logger.Error("拷贝失败", zap.Error(err))
+		context.Error(err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "post_restored"), "data": restoreResp})
+}
+
+// PurgePost 永久删除回收站中的文章
+func (th TrashHandler) PurgePost(context *gin.Context) {
+	userID, id, ok := th.bindTarget(context)
+	if !ok {
+		return
+	}
+	if err := th.trashService.PurgePost(context.Request.Context(), id, userID); err != nil {
+		th.fail(context, "永久删除文章失败", err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "post_purged")})
+}
+
+// RestoreComment 恢复评论
+func (th TrashHandler) RestoreComment(context *gin.Context) {
+	userID, id, ok := th.bindTarget(context)
+	if !ok {
+		return
+	}
+	if err := th.trashService.RestoreComment(context.Request.Context(), id, userID); err != nil {
+		th.fail(context, "恢复评论失败", err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "comment_restored")})
+}
+
+// PurgeComment 永久删除回收站中的评论
+func (th TrashHandler) PurgeComment(context *gin.Context) {
+	userID, id, ok := th.bindTarget(context)
+	if !ok {
+		return
+	}
+	if err := th.trashService.PurgeComment(context.Request.Context(), id, userID); err != nil {
+		th.fail(context, "永久删除评论失败", err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"msg": message(context, "comment_purged")})
+}
+
+func (th TrashHandler) bindList(context *gin.Context) (*DTO.ListTrashDTO, bool) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return nil, false
+	}
+	var req request.ListTrashRequest
+	if err := context.ShouldBindQuery(&req); err != nil {
+		logger.Error("回收站列表参数绑定失败", zap.Error(err))
+		context.Error(invalidParam(errInvalidParams, err))
+		return nil, false
+	}
+	req.SetDefault()
+	return &DTO.ListTrashDTO{UserID: userID.(uint), PageNum: req.PageNum, PageSize: req.PageSize}, true
+}
+
+func (th TrashHandler) bindTarget(context *gin.Context) (uint, uint, bool) {
+	userID, exists := context.Get("userID")
+	if !exists {
+		logger.Warn("用户授权失败")
+		context.Error(errUnauthorized)
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(context.Param("id"), 10, 0)
+	if err != nil {
+		logger.Error("ID格式错误", zap.Error(err))
+		context.Error(invalidParam(errInvalidID, err))
+		return 0, 0, false
+	}
+	return userID.(uint), uint(id), true
+}
+
+// fail 记录日志后交给错误处理中间件，不在回收站中的内容返回 404
+func (th TrashHandler) fail(context *gin.Context, msg string, err error) {
+	logger.Error(msg, zap.Error(err))
+	context.Error(err)
+}
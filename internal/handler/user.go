@@ -44,17 +44,17 @@ func (uh *UserHandler) UserRegister(c *gin.Context) {
 	var req request.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		logger.Warn("注册参数绑定失败", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"msg": "参数错误：" + err.Error()})
+		c.Error(invalidParam(errInvalidParams, err))
 		return
 	}
 
 	// 2. 调用服务层处理注册逻辑
 	// 将请求数据转换为DTO格式，调用用户服务层处理注册
 	userRegisterDTO := DTO.UserRegisterDTO{Username: req.Username, Password: req.Password, Email: req.Email}
-	user, err := uh.userService.UserRegister(&userRegisterDTO)
+	user, err := uh.userService.UserRegister(c.Request.Context(), &userRegisterDTO)
 	if err != nil {
 		logger.Error("注册业务处理失败", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"msg": "注册失败：" + err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -63,13 +63,14 @@ func (uh *UserHandler) UserRegister(c *gin.Context) {
 	err = copier.Copy(&userResponse, &user)
 	if err != nil {
 		logger.Error("注册业务处理失败：响应对象复制失败", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"msg": "注册失败：" + err.Error()})
+		c.Error(err)
+		return
 	}
 
 	//3. 返回成功响应
 	// 返回HTTP 200状态码和成功注册的用户信息
 	c.JSON(http.StatusOK, gin.H{
-		"msg":  "注册成功",
+		"msg":  message(c, "register_succeeded"),
 		"data": userResponse,
 	})
 
@@ -79,19 +80,19 @@ func (uh *UserHandler) UserLogin(c *gin.Context) {
 	var req request.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		logger.Warn("登入参数绑定失败", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"msg": "参数错误：" + err.Error()})
+		c.Error(invalidParam(errInvalidParams, err))
 		return
 	}
-	dto := DTO.LoginDTO{req.Username, req.Password}
-	loginResponse, err := uh.userService.UserLogin(&dto)
+	dto := DTO.LoginDTO{Username: req.Username, Password: req.Password}
+	loginResponse, err := uh.userService.UserLogin(c.Request.Context(), &dto)
 	if err != nil {
 		logger.Error("登录失败", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"msg": "登录失败：" + err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"msg":  "登录成功",
+		"msg":  message(c, "login_succeeded"),
 		"data": loginResponse,
 	})
 }
@@ -1,24 +1,28 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
+	"go-my-blog/pkg/apperr"
 	"go-my-blog/pkg/jwt" // 引入上面实现的 JWT 工具类
 
 	"github.com/gin-gonic/gin"
 )
 
+// 认证失败的错误，由 ErrorHandler 统一返回 401
+var (
+	errTokenMissing   = apperr.Unauthorized("token_missing", "请先登录（未携带 Authorization 头）")
+	errTokenMalformed = apperr.Unauthorized("token_malformed", "Token 格式错误（正确格式：Bearer <token>）")
+	errTokenInvalid   = apperr.Unauthorized("token_invalid", "Token 无效或已过期")
+)
+
 // JWTAuth JWT 认证中间件：验证请求中的 Token 有效性，通过后将用户 ID 存入上下文
 func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 1. 从请求头中获取 Token（格式：Authorization: Bearer <token>）
 		authHeader := c.Request.Header.Get("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code": 401,
-				"msg":  "请先登录（未携带 Authorization 头）",
-			})
+			c.Error(errTokenMissing)
 			c.Abort() // 拦截请求，不再执行后续 handler
 			return
 		}
@@ -26,10 +30,7 @@ func JWTAuth() gin.HandlerFunc {
 		// 2. 校验 Token 格式（必须是 "Bearer <token>" 格式）
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code": 401,
-				"msg":  "Token 格式错误（正确格式：Bearer <token>）",
-			})
+			c.Error(errTokenMalformed)
 			c.Abort()
 			return
 		}
@@ -37,10 +38,7 @@ func JWTAuth() gin.HandlerFunc {
 		// 3. 验证 Token 有效性并提取用户 ID
 		user, err := jwt.VerifyToken(parts[1])
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code": 401,
-				"msg":  "Token 无效或已过期：" + err.Error(),
-			})
+			c.Error(errTokenInvalid.Wrap(err))
 			c.Abort()
 			return
 		}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"go-my-blog/config"
+	"go-my-blog/pkg/apperr"
+	"go-my-blog/pkg/i18n"
+	"go-my-blog/pkg/logger"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 没有对应业务错误时使用的通用错误
+var (
+	errNotFound       = apperr.NotFound("not_found", "资源不存在")
+	errInternal       = apperr.Internal("internal_error", "服务器内部错误，请稍后重试")
+	errRequestTimeout = apperr.New(apperr.KindTimeout, "request_timeout", "请求处理超时，请稍后重试")
+	errClientClosed   = apperr.New(apperr.KindCanceled, "client_closed_request", "客户端已断开连接")
+)
+
+const problemContentType = "application/problem+json"
+
+// ErrorResponse 统一的错误响应
+type ErrorResponse struct {
+	Code      string      `json:"code"`              // 稳定的错误码，客户端据此区分失败原因
+	Message   string      `json:"message"`           // 面向用户的说明
+	Details   interface{} `json:"details,omitempty"` // 补充信息，如字段错误列表
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"` // 未通过的校验规则，如 required、max
+	Message string `json:"message"`
+}
+
+// ProblemResponse RFC 7807 格式的错误响应，扩展字段与 ErrorResponse 一致
+type ProblemResponse struct {
+	Type      string      `json:"type"`
+	Title     string      `json:"title"`
+	Status    int         `json:"status"`
+	Detail    string      `json:"detail"`
+	Instance  string      `json:"instance"`
+	Code      string      `json:"code"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// ErrorHandler 错误处理中间件：处理器通过 c.Error 记录错误后直接返回，
+// 由这里统一转换为对应的状态码和错误响应；处理器已写出响应时不再处理
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		AbortWithError(c, c.Errors.Last().Err)
+	}
+}
+
+// AbortWithError 终止请求并写出错误响应
+func AbortWithError(c *gin.Context, err error) {
+	e := resolveError(c, err)
+	if e.Status() >= http.StatusInternalServerError {
+		logger.Error("请求处理失败",
+			zap.String("request_id", GetRequestID(c)),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("code", e.Code),
+			zap.Error(err),
+		)
+	}
+	contentType, body := errorBody(c, e)
+	c.Header("Content-Type", contentType)
+	c.Status(e.Status())
+	_, _ = c.Writer.Write(body)
+	c.Abort()
+}
+
+// resolveError 把任意错误转换为业务错误：请求已超时或客户端已断开时以此为准，
+// 记录不存在按 404 处理，其余未归类的错误一律按 500 处理，不向客户端暴露内部信息
+func resolveError(c *gin.Context, err error) *apperr.Error {
+	if status := contextErrorStatus(c.Request.Context().Err()); status != 0 {
+		return contextError(status).Wrap(err)
+	}
+	if e, ok := apperr.As(err); ok {
+		return e
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errNotFound.Wrap(err)
+	}
+	return errInternal.Wrap(err)
+}
+
+// errorBody 按配置或请求的 Accept 头选择错误响应格式，说明按请求的语言翻译
+func errorBody(c *gin.Context, e *apperr.Error) (string, []byte) {
+	e = localize(GetLocale(c), e)
+	if wantsProblem(c) {
+		body, _ := json.Marshal(ProblemResponse{
+			Type:      "about:blank",
+			Title:     http.StatusText(e.Status()),
+			Status:    e.Status(),
+			Detail:    e.Message,
+			Instance:  c.Request.URL.Path,
+			Code:      e.Code,
+			Details:   e.Details,
+			RequestID: GetRequestID(c),
+		})
+		return problemContentType, body
+	}
+	body, _ := json.Marshal(ErrorResponse{
+		Code:      e.Code,
+		Message:   e.Message,
+		Details:   e.Details,
+		RequestID: GetRequestID(c),
+	})
+	return "application/json; charset=utf-8", body
+}
+
+// localize 按错误码翻译说明，Details 为 map 时作为模板参数；
+// 错误链中有字段校验错误时，Details 替换为翻译后的字段错误列表
+func localize(locale string, e *apperr.Error) *apperr.Error {
+	args, _ := e.Details.(map[string]interface{})
+	e = e.WithMessage(i18n.Text(locale, e.Code, args, e.Message))
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(e, &validationErrors) {
+		fields := make([]FieldError, 0, len(validationErrors))
+		for _, fe := range validationErrors {
+			fields = append(fields, FieldError{
+				Field:   fe.Field(),
+				Code:    fe.Tag(),
+				Message: i18n.FieldError(locale, fe, fe.Field()),
+			})
+		}
+		e = e.WithDetails(map[string]interface{}{"fields": fields})
+	}
+	return e
+}
+
+func wantsProblem(c *gin.Context) bool {
+	return config.Conf.Server.ProblemDetails || strings.Contains(c.GetHeader("Accept"), problemContentType)
+}
+
+func contextError(status int) *apperr.Error {
+	if status == http.StatusGatewayTimeout {
+		return errRequestTimeout
+	}
+	return errClientClosed
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-my-blog/config"
+	"go-my-blog/config/priority_config"
+	"go-my-blog/pkg/apperr"
+	"go-my-blog/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	// 日志输出到控制台且只记录错误，避免测试中写日志文件
+	priority_config.PriorityConf.Gin.Debug = true
+	priority_config.PriorityConf.Log.Level = "error"
+	logger.Init()
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+func setMiddlewareConfig(t *testing.T, problemDetails bool) {
+	t.Helper()
+	previous := config.Conf
+	config.Conf = &config.AppConfig{}
+	config.Conf.Server.ProblemDetails = problemDetails
+	t.Cleanup(func() { config.Conf = previous })
+}
+
+// serveError 经过 ErrorHandler 处理 handler 记录的错误
+func serveError(t *testing.T, err error, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(err)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestErrorHandlerStatus(t *testing.T) {
+	setMiddlewareConfig(t, false)
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"参数错误", apperr.BadRequest("invalid_params", "参数错误"), http.StatusBadRequest, "invalid_params"},
+		{"校验失败", apperr.Validation("validation_failed", "校验失败"), http.StatusUnprocessableEntity, "validation_failed"},
+		{"未登录", apperr.Unauthorized("unauthorized", "未登录"), http.StatusUnauthorized, "unauthorized"},
+		{"无权限", apperr.Forbidden("forbidden", "无权限"), http.StatusForbidden, "forbidden"},
+		{"业务不存在", apperr.NotFound("post_not_found", "文章不存在"), http.StatusNotFound, "post_not_found"},
+		{"冲突", apperr.Conflict("username_taken", "用户名已被注册"), http.StatusConflict, "username_taken"},
+		{"包装后的业务错误", fmt.Errorf("查询失败：%w", apperr.NotFound("post_not_found", "文章不存在")), http.StatusNotFound, "post_not_found"},
+		{"记录不存在", gorm.ErrRecordNotFound, http.StatusNotFound, "not_found"},
+		{"未归类错误", errors.New("dial tcp 10.0.0.1:3306: connection refused"), http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tt := range tests {
+		w := serveError(t, tt.err, "")
+		if w.Code != tt.status {
+			t.Errorf("%s: 状态码 = %d, want %d", tt.name, w.Code, tt.status)
+		}
+		var body ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Errorf("%s: 响应不是 JSON: %s", tt.name, w.Body.String())
+			continue
+		}
+		if body.Code != tt.code || body.Message == "" {
+			t.Errorf("%s: body = %+v, want code %s", tt.name, body, tt.code)
+		}
+		if strings.Contains(body.Message, "10.0.0.1") {
+			t.Errorf("%s: 响应暴露了内部错误: %s", tt.name, body.Message)
+		}
+	}
+}
+
+func TestErrorHandlerProblemJSON(t *testing.T) {
+	setMiddlewareConfig(t, false)
+	notFound := apperr.NotFound("post_not_found", "文章不存在")
+
+	// 普通请求返回 ErrorResponse
+	w := serveError(t, notFound, "application/json")
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	// Accept 中包含 problem+json 时返回 RFC 7807 格式
+	w = serveError(t, notFound, "application/problem+json, application/json;q=0.9")
+	if ct := w.Header().Get("Content-Type"); ct != problemContentType {
+		t.Errorf("Content-Type = %q, want %s", ct, problemContentType)
+	}
+	var problem ProblemResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Status != http.StatusNotFound || problem.Title != "Not Found" || problem.Instance != "/test" ||
+		problem.Code != "post_not_found" || problem.Detail == "" || problem.Type != "about:blank" {
+		t.Errorf("problem = %+v", problem)
+	}
+
+	// 配置开启后不看 Accept 一律返回 problem+json
+	setMiddlewareConfig(t, true)
+	w = serveError(t, notFound, "")
+	if ct := w.Header().Get("Content-Type"); ct != problemContentType {
+		t.Errorf("开启 problem_details 后 Content-Type = %q", ct)
+	}
+}
+
+func TestErrorHandlerKeepsWrittenResponse(t *testing.T) {
+	setMiddlewareConfig(t, false)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(errors.New("后台任务失败"))
+		c.JSON(http.StatusAccepted, gin.H{"msg": "ok"})
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	// 处理器已写出响应时不再改写
+	if w.Code != http.StatusAccepted || !strings.Contains(w.Body.String(), `"ok"`) {
+		t.Errorf("状态码 = %d, body = %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"go-my-blog/config"
+	"go-my-blog/internal/repo"
+	"go-my-blog/pkg/i18n"
+	"go-my-blog/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Locale 语言协商中间件：按 Accept-Language 选择接口提示信息的语言，都不支持时使用站点语言
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		SetLocale(c, i18n.Negotiate(c.GetHeader("Accept-Language"), defaultLocale()))
+		c.Next()
+	}
+}
+
+// UserLocale 已登录用户设置了语言偏好时以偏好为准，需放在 JWTAuth 之后；查询失败时保留协商结果
+func UserLocale(userRepo *repo.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID, ok := c.Get("userID"); ok {
+			locale, err := userRepo.GetLocale(c.Request.Context(), userID.(uint))
+			if err != nil {
+				logger.Warn("用户语言偏好查询失败", zap.Uint("user_id", userID.(uint)), zap.Error(err))
+			} else if matched, ok := i18n.Match(locale); ok {
+				SetLocale(c, matched)
+			}
+		}
+		c.Next()
+	}
+}
+
+// GetLocale 当前请求的语言，未经过 Locale 中间件时为站点语言
+func GetLocale(c *gin.Context) string {
+	if locale := c.GetString("locale"); locale != "" {
+		return locale
+	}
+	return defaultLocale()
+}
+
+// SetLocale 设置当前请求的语言（如用户刚修改了语言偏好）
+func SetLocale(c *gin.Context, locale string) {
+	c.Set("locale", locale)
+	c.Header("Content-Language", locale)
+}
+
+// defaultLocale 站点语言对应的接口语言，站点语言不受支持时使用 i18n.DefaultLocale
+func defaultLocale() string {
+	if locale, ok := i18n.Match(config.Conf.Site.Language); ok {
+		return locale
+	}
+	return i18n.DefaultLocale
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"go-my-blog/pkg/logger"
 	"net/http"
 	"time"
@@ -33,6 +34,7 @@ func GinLogger() gin.HandlerFunc {
 			zap.String("remote_addr", remoteAddr),
 			zap.Int("status_code", statusCode),
 			zap.Duration("duration", duration),
+			zap.String("request_id", GetRequestID(c)),
 		)
 	}
 }
@@ -48,26 +50,22 @@ func GinRecovery(debug bool) gin.HandlerFunc {
 					"请求处理 panic 恢复",
 					zap.Any("error", err),
 					zap.String("path", c.Request.URL.Path),
+					zap.String("request_id", GetRequestID(c)),
 					zap.Stack("stack_trace"), // 记录堆栈信息，便于排查问题
 				)
 
 				// 2. 返回 500 响应（开发环境显示错误详情，生产环境隐藏）
+				panicErr := fmt.Errorf("panic: %v", err)
 				if debug {
 					// 开发环境：返回错误信息和堆栈（方便调试）
-					c.JSON(http.StatusInternalServerError, gin.H{
-						"msg":   "服务器内部错误",
-						"error": err,
+					AbortWithError(c, errInternal.Wrap(panicErr).WithDetails(gin.H{
+						"error": fmt.Sprint(err),
 						"stack": zap.Stack("").String, // 堆栈信息
-					})
+					}))
 				} else {
-					// 生产环境：隐藏敏感错误信息
-					c.JSON(http.StatusInternalServerError, gin.H{
-						"msg": "服务器内部错误，请稍后重试",
-					})
+					// 生产环境：隐藏敏感错误信息（AbortWithError 会终止请求链，不再执行后续逻辑）
+					AbortWithError(c, panicErr)
 				}
-
-				// 3. 终止请求链，不再执行后续逻辑
-				c.Abort()
 			}
 		}()
 
@@ -91,10 +89,10 @@ func Cors() gin.HandlerFunc {
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 
 		// 3. 允许的请求头（包含自定义头如 Authorization）
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Request-ID")
 
 		// 4. 允许前端读取的响应头
-		c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin")
+		c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, X-Request-ID")
 
 		// 5. 是否允许携带 Cookie（跨域请求时）
 		c.Header("Access-Control-Allow-Credentials", "true")
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader 请求ID的请求头/响应头
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID 请求ID中间件：沿用上游（如网关）传入的合法请求ID，否则生成新的，
+// 写入上下文和响应头，日志与错误响应据此关联到同一个请求
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set("requestID", id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// GetRequestID 当前请求的ID，未经过 RequestID 中间件时为空
+func GetRequestID(c *gin.Context) string {
+	return c.GetString("requestID")
+}
+
+// validRequestID 只接受长度有限的字母、数字和 -_.，避免把任意内容写进日志和响应头
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
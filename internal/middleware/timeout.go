@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"go-my-blog/config"
+	"go-my-blog/pkg/apperr"
+	"go-my-blog/pkg/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// StatusClientClosedRequest 客户端在响应前断开连接（沿用 nginx 的 499 状态码）
+const StatusClientClosedRequest = apperr.StatusClientClosedRequest
+
+// Timeout 请求超时中间件：按路由配置为请求上下文设置超时，上下文取消后数据库查询随之中止。
+// 上下文已取消时处理器写出的错误响应会被替换：超时返回 504，客户端断开返回 499
+func Timeout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if timeout := config.Conf.Server.GetRequestTimeout(c.Request.Method, c.FullPath()); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+			c.Request = c.Request.WithContext(ctx)
+		}
+		writer := &contextErrorWriter{ResponseWriter: c.Writer, c: c, ctx: ctx}
+		c.Writer = writer
+
+		c.Next()
+
+		status := contextErrorStatus(ctx.Err())
+		if status == 0 {
+			return
+		}
+		if !writer.Written() {
+			AbortWithError(c, ctx.Err())
+		}
+		logger.Warn("请求未完成：上下文已取消",
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status_code", writer.Status()),
+			zap.Error(ctx.Err()),
+		)
+	}
+}
+
+// contextErrorWriter 上下文取消后，把处理器写出的错误响应替换为 499/504
+type contextErrorWriter struct {
+	gin.ResponseWriter
+	c        *gin.Context
+	ctx      context.Context
+	replaced bool
+	body     []byte
+}
+
+func (w *contextErrorWriter) WriteHeader(code int) {
+	if !w.replaced && !w.Written() && code >= http.StatusBadRequest {
+		if status := contextErrorStatus(w.ctx.Err()); status != 0 {
+			var contentType string
+			contentType, w.body = errorBody(w.c, contextError(status))
+			w.replaced = true
+			w.Header().Set("Content-Type", contentType)
+			code = status
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *contextErrorWriter) Write(data []byte) (int, error) {
+	if !w.replaced {
+		return w.ResponseWriter.Write(data)
+	}
+	// 丢弃处理器原本的错误内容，只写一次替换后的响应体
+	if !w.Written() {
+		if _, err := w.ResponseWriter.Write(w.body); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *contextErrorWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// contextErrorStatus 上下文错误对应的状态码，未取消时返回 0
+func contextErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	}
+	return 0
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveWithContext 用指定的请求上下文经过 Timeout 和 ErrorHandler 处理请求
+func serveWithContext(ctx context.Context, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(Timeout(), ErrorHandler())
+	r.GET("/test", handler)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil).WithContext(ctx))
+	return w
+}
+
+func expiredContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestTimeoutOverridesError(t *testing.T) {
+	setMiddlewareConfig(t, false)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		handler gin.HandlerFunc
+		status  int
+		code    string
+	}{
+		// 查询因上下文取消而失败，处理器记录的是数据库错误
+		{"超时后记录错误", expiredContext(t), func(c *gin.Context) { c.Error(c.Request.Context().Err()) }, http.StatusGatewayTimeout, "request_timeout"},
+		{"断开后记录错误", canceledContext(), func(c *gin.Context) { c.Error(c.Request.Context().Err()) }, StatusClientClosedRequest, "client_closed_request"},
+		// 处理器自己写出的错误响应也会被替换
+		{"超时后写出 500", expiredContext(t), func(c *gin.Context) {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": "internal_error"})
+		}, http.StatusGatewayTimeout, "request_timeout"},
+		{"断开后写出 404", canceledContext(), func(c *gin.Context) {
+			c.JSON(http.StatusNotFound, gin.H{"code": "post_not_found"})
+		}, StatusClientClosedRequest, "client_closed_request"},
+		// 处理器没有写出任何响应
+		{"超时后未写响应", expiredContext(t), func(c *gin.Context) {}, http.StatusGatewayTimeout, "request_timeout"},
+	}
+	for _, tt := range tests {
+		w := serveWithContext(tt.ctx, tt.handler)
+		if w.Code != tt.status {
+			t.Errorf("%s: 状态码 = %d, want %d", tt.name, w.Code, tt.status)
+		}
+		var body ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Code != tt.code {
+			t.Errorf("%s: body = %s, want code %s", tt.name, w.Body.String(), tt.code)
+		}
+	}
+}
+
+func TestTimeoutKeepsSuccess(t *testing.T) {
+	setMiddlewareConfig(t, false)
+	// 成功响应不因上下文已取消而改写
+	w := serveWithContext(canceledContext(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"msg": "ok"})
+	})
+	if w.Code != http.StatusOK || w.Body.String() != `{"msg":"ok"}` {
+		t.Errorf("状态码 = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	// 上下文未取消时错误照常返回
+	w = serveWithContext(context.Background(), func(c *gin.Context) {
+		c.Error(errNotFound)
+	})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("状态码 = %d, want 404", w.Code)
+	}
+}
+
+func TestContextErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{context.Canceled, StatusClientClosedRequest},
+	}
+	for _, tt := range tests {
+		if got := contextErrorStatus(tt.err); got != tt.want {
+			t.Errorf("contextErrorStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
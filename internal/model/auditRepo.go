@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+// 审计动作
+const (
+	AuditDataExport            = "account.data_export"        // 申请个人数据导出
+	AuditAccountDeletion       = "account.deletion_requested" // 申请注销账号
+	AuditAccountDeletionCancel = "account.deletion_cancelled" // 撤销注销申请
+	AuditAccountDeleted        = "account.deleted"            // 账号已删除
+)
+
+// AuditLog 审计记录：不与用户表建立外键，账号删除后记录仍然保留
+type AuditLog struct {
+	ID        uint      `gorm:"type:bigint;primaryKey;autoIncrement;comment:审计记录唯一标识" json:"id"`
+	Action    string    `gorm:"type:varchar(50);not null;index:idx_audit_action;comment:动作" json:"action"`
+	ActorID   uint      `gorm:"type:bigint;not null;default:0;comment:操作者ID（后台任务为0）" json:"actor_id"`
+	SubjectID uint      `gorm:"type:bigint;not null;index:idx_audit_subject;comment:被操作的用户ID" json:"subject_id"`
+	IP        string    `gorm:"type:varchar(45);not null;default:'';comment:操作者IP" json:"ip"`
+	Detail    string    `gorm:"type:text;comment:详情（JSON）" json:"detail"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+}
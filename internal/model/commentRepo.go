@@ -1,21 +1,78 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// 评论审核状态
+const (
+	CommentStatusPending  = "pending"  // 待审核
+	CommentStatusApproved = "approved" // 已通过（公开可见）
+	CommentStatusRejected = "rejected" // 已拒绝
+	CommentStatusSpam     = "spam"     // 垃圾评论
+)
+
+// 评论训练垃圾评论分类器时使用的标签
+const (
+	SpamLabelSpam = "spam" // 作为垃圾评论训练
+	SpamLabelHam  = "ham"  // 作为正常评论训练
+)
+
 // Comment 评论模型
 type Comment struct {
-	ID        uint           `gorm:"type:bigint;primaryKey;autoIncrement;comment:评论唯一标识" json:"id"`
-	Content   string         `gorm:"type:text;not null;comment:评论内容" json:"content"`
-	UserID    uint           `gorm:"type:bigint;not null;index:idx_comment_user;comment:评论者ID" json:"user_id"`
-	PostID    uint           `gorm:"type:bigint;not null;index:idx_comment_post;comment:所属文章ID" json:"post_id"`
-	CreatedAt time.Time      `gorm:"comment:创建时间" json:"created_at"`
-	UpdatedAt time.Time      `gorm:"comment:更新时间" json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"comment:软删除标记" json:"-"`
+	ID            uint           `gorm:"type:bigint;primaryKey;autoIncrement;comment:评论唯一标识" json:"id"`
+	Content       string         `gorm:"type:text;not null;comment:评论内容" json:"content"`
+	ContentFormat string         `gorm:"type:varchar(10);not null;default:plain;comment:内容格式（markdown/plain/html）" json:"content_format"`
+	ContentHTML   string         `gorm:"type:text;comment:渲染并清洗后的HTML缓存" json:"-"`
+	RenderVersion int            `gorm:"type:int;not null;default:0;comment:HTML缓存对应的渲染器版本" json:"-"`
+	UserID        *uint          `gorm:"type:bigint;index:idx_comment_user;comment:评论者ID（游客评论为空）" json:"user_id"`
+	PostID        uint           `gorm:"type:bigint;not null;index:idx_comment_post;comment:所属文章ID" json:"post_id"`
+	ParentID      *uint          `gorm:"type:bigint;index:idx_comment_parent;comment:父评论ID（根评论为空）" json:"parent_id"`
+	Path          string         `gorm:"type:varchar(255);not null;default:'';index:idx_comment_path;comment:物化路径" json:"path"` // 从根评论到当前评论的ID链，如 0000000001/0000000005/
+	Depth         int            `gorm:"type:int;not null;default:0;comment:嵌套深度（根评论为0）" json:"depth"`
+	ReplyCount    int            `gorm:"type:int;not null;default:0;comment:直接回复数" json:"reply_count"`
+	IsDeleted     bool           `gorm:"not null;default:false;comment:已删除占位（有回复的评论删除后保留节点）" json:"is_deleted"`
+	Status        string         `gorm:"type:varchar(20);not null;default:approved;index:idx_comment_status;comment:审核状态（pending/approved/rejected/spam）" json:"status"`
+	SpamScore     float64        `gorm:"type:decimal(5,4);not null;default:0;comment:垃圾评论得分（0~1）" json:"spam_score"`
+	SpamLabel     string         `gorm:"type:varchar(10);not null;default:'';comment:训练分类器时使用的标签（spam/ham，未训练为空）" json:"-"`
+	IP            string         `gorm:"type:varchar(45);not null;default:'';comment:评论者IP" json:"-"`
+	GuestName     string         `gorm:"type:varchar(50);not null;default:'';comment:游客昵称" json:"guest_name"`
+	GuestEmail    string         `gorm:"type:varchar(100);not null;default:'';comment:游客邮箱" json:"-"`
+	GuestWebsite  string         `gorm:"type:varchar(200);not null;default:'';comment:游客个人网站" json:"guest_website"`
+	SourceID      string         `gorm:"type:varchar(64);not null;default:'';index:idx_comment_source;comment:导入来源中的评论标识，用于重复导入时去重" json:"-"`
+	EditedAt      *time.Time     `gorm:"comment:最后编辑时间（未编辑为空）" json:"edited_at"`
+	CreatedAt     time.Time      `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"comment:更新时间" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"comment:软删除标记" json:"-"`
 	// 关联关系：评论的作者和所属文章
 	User User `gorm:"foreignKey:UserID" json:"user"` // 预加载评论者信息
 	Post Post `gorm:"foreignKey:PostID" json:"post"` // 可选：关联文章信息
 }
+
+// IsGuest 是否为游客评论
+func (c *Comment) IsGuest() bool {
+	return c.UserID == nil
+}
+
+// IsAuthoredBy 是否由指定登录用户发表（游客评论始终返回 false）
+func (c *Comment) IsAuthoredBy(userID uint) bool {
+	return c.UserID != nil && *c.UserID == userID
+}
+
+// CommentPathSegment 生成评论在物化路径中的一段，定长补零保证按字符串排序即按树的先序排列
+func CommentPathSegment(id uint) string {
+	return fmt.Sprintf("%010d/", id)
+}
+
+// CommentRevision 评论历史版本：每次编辑前保存旧内容
+type CommentRevision struct {
+	ID            uint      `gorm:"type:bigint;primaryKey;autoIncrement;comment:版本唯一标识" json:"id"`
+	CommentID     uint      `gorm:"type:bigint;not null;index:idx_revision_comment;comment:所属评论ID" json:"comment_id"`
+	Content       string    `gorm:"type:text;not null;comment:编辑前的评论内容" json:"content"`
+	ContentFormat string    `gorm:"type:varchar(10);not null;default:plain;comment:编辑前的内容格式（markdown/plain/html）" json:"content_format"`
+	EditorID      uint      `gorm:"type:bigint;not null;comment:编辑者ID" json:"editor_id"`
+	CreatedAt     time.Time `gorm:"comment:创建时间（即编辑时间）" json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// 个人数据导出状态
+const (
+	DataExportPending = "pending" // 生成中
+	DataExportReady   = "ready"   // 可下载
+	DataExportFailed  = "failed"  // 生成失败
+)
+
+// DataExport 个人数据导出任务：归档在后台生成，保存在本地目录中，过期后删除
+type DataExport struct {
+	ID        uint       `gorm:"type:bigint;primaryKey;autoIncrement;comment:导出任务唯一标识" json:"id"`
+	UserID    uint       `gorm:"type:bigint;not null;index:idx_export_user;comment:申请导出的用户ID" json:"user_id"`
+	Status    string     `gorm:"type:varchar(20);not null;default:pending;comment:状态（pending/ready/failed）" json:"status"`
+	FileName  string     `gorm:"type:varchar(255);not null;default:'';comment:归档文件名（位于导出目录下）" json:"-"`
+	Size      int64      `gorm:"type:bigint;not null;default:0;comment:归档大小（字节）" json:"size"`
+	Error     string     `gorm:"type:varchar(500);not null;default:'';comment:失败原因" json:"error"`
+	ExpiresAt *time.Time `gorm:"index:idx_export_expires;comment:过期时间（生成完成后设置）" json:"expires_at"`
+	CreatedAt time.Time  `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"comment:更新时间" json:"updated_at"`
+}
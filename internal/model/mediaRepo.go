@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+)
+
+// Media 媒体文件模型（图片），文件本体保存在存储中，这里只记录元数据
+type Media struct {
+	ID         uint      `gorm:"type:bigint;primaryKey;autoIncrement;comment:媒体唯一标识" json:"id"`
+	UID        string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_media_uid;comment:随机标识（出现在文件路径中，用于识别文章引用）" json:"uid"`
+	UserID     uint      `gorm:"type:bigint;not null;index:idx_media_user;comment:上传者ID" json:"user_id"`
+	FileName   string    `gorm:"type:varchar(255);not null;default:'';comment:原始文件名" json:"file_name"`
+	MimeType   string    `gorm:"type:varchar(50);not null;comment:文件类型（按内容识别）" json:"mime_type"`
+	Size       int64     `gorm:"type:bigint;not null;default:0;comment:处理后原图大小（字节）" json:"size"`
+	Width      int       `gorm:"type:int;not null;default:0;comment:宽度（像素）" json:"width"`
+	Height     int       `gorm:"type:int;not null;default:0;comment:高度（像素）" json:"height"`
+	Storage    string    `gorm:"type:varchar(10);not null;comment:存储类型（local/s3）" json:"storage"`
+	StorageKey string    `gorm:"type:varchar(255);not null;comment:原图在存储中的key" json:"storage_key"`
+	Thumbnails string    `gorm:"type:text;comment:缩略图列表（JSON）" json:"-"`
+	CreatedAt  time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"comment:更新时间" json:"updated_at"`
+	// 引用该媒体的文章：多对多，中间表 post_media，由文章内容中的媒体地址自动维护
+	Posts []Post `gorm:"many2many:post_media" json:"posts"`
+}
+
+// MediaThumbnail 缩略图（存放在 Media.Thumbnails 中）
+type MediaThumbnail struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Key    string `json:"key"`
+}
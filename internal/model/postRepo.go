@@ -6,17 +6,55 @@ import (
 	"gorm.io/gorm"
 )
 
+// 文章状态
+const (
+	PostStatusDraft     = "draft"     // 草稿
+	PostStatusPublished = "published" // 已发布
+)
+
+// 评论策略
+const (
+	CommentPolicyInherit   = ""          // 继承全局配置
+	CommentPolicyOpen      = "open"      // 开放：评论直接发布
+	CommentPolicyModerated = "moderated" // 审核：评论需版主通过后才公开
+	CommentPolicyClosed    = "closed"    // 关闭：不允许发表评论
+)
+
 // Post 文章模型
 type Post struct {
-	ID        uint           `gorm:"type:bigint;primaryKey;autoIncrement;comment:文章唯一标识" json:"id"`
-	Title     string         `gorm:"type:varchar(200);not null;index:idx_title;comment:文章标题" json:"title"`
-	Content   string         `gorm:"type:text;not null;comment:文章内容" json:"content"`
-	UserID    uint           `gorm:"type:bigint;not null;index:idx_post_user;comment:作者ID" json:"user_id"`
-	CreatedAt time.Time      `gorm:"comment:创建时间" json:"created_at"`
-	UpdatedAt time.Time      `gorm:"comment:更新时间" json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"comment:软删除标记" json:"deleted_at"`
+	ID                   uint           `gorm:"type:bigint;primaryKey;autoIncrement;comment:文章唯一标识" json:"id"`
+	Title                string         `gorm:"type:varchar(200);not null;index:idx_title;comment:文章标题" json:"title"`
+	Content              string         `gorm:"type:text;not null;comment:文章内容" json:"content"`
+	ContentFormat        string         `gorm:"type:varchar(10);not null;default:markdown;comment:内容格式（markdown/plain/html）" json:"content_format"`
+	ContentHTML          string         `gorm:"type:longtext;comment:渲染并清洗后的HTML缓存" json:"-"`
+	RenderVersion        int            `gorm:"type:int;not null;default:0;comment:HTML缓存对应的渲染器版本" json:"-"`
+	Excerpt              string         `gorm:"type:text;comment:摘要（<!--more-->之前的内容或正文前N个字）" json:"excerpt"`
+	WordCount            int            `gorm:"type:int;not null;default:0;comment:字数（中文按字、英文按词）" json:"word_count"`
+	ReadingMinutes       int            `gorm:"type:int;not null;default:0;comment:预计阅读时长（分钟）" json:"reading_minutes"`
+	TOC                  string         `gorm:"column:toc;type:text;comment:目录（JSON）" json:"-"`
+	UserID               uint           `gorm:"type:bigint;not null;index:idx_post_user;comment:作者ID" json:"user_id"`
+	Status               string         `gorm:"type:varchar(20);not null;default:published;index:idx_post_status;comment:文章状态（draft/published）" json:"status"`
+	ViewCount            int64          `gorm:"type:bigint;not null;default:0;comment:浏览次数" json:"view_count"`
+	CommentPolicy        string         `gorm:"type:varchar(20);not null;default:'';comment:评论策略（open/moderated/closed，空为继承全局）" json:"comment_policy"`
+	CommentAutoCloseDays int            `gorm:"type:int;not null;default:0;comment:发布N天后自动关闭评论（0为继承全局）" json:"comment_auto_close_days"`
+	CoverMediaID         *uint          `gorm:"type:bigint;index:idx_post_cover;comment:封面图片（媒体ID）" json:"cover_media_id"`
+	SEOTitle             string         `gorm:"type:varchar(200);not null;default:'';comment:SEO标题（为空时使用文章标题）" json:"seo_title"`
+	SEODescription       string         `gorm:"type:varchar(500);not null;default:'';comment:SEO描述（为空时使用摘要）" json:"seo_description"`
+	CanonicalURL         string         `gorm:"type:varchar(500);not null;default:'';comment:规范链接（为空时使用本站文章地址）" json:"canonical_url"`
+	NoIndex              bool           `gorm:"not null;default:false;comment:是否禁止搜索引擎收录" json:"no_index"`
+	Slug                 string         `gorm:"type:varchar(191);not null;default:'';index:idx_post_slug;comment:文章别名（导入的文章使用原站点的 slug）" json:"slug"`
+	SourcePath           string         `gorm:"type:varchar(255);not null;default:'';index:idx_post_source;comment:导入来源（文件相对路径或原站点标识），用于重复导入时匹配" json:"source_path"`
+	CreatedAt            time.Time      `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt            time.Time      `gorm:"comment:更新时间" json:"updated_at"`
+	DeletedAt            gorm.DeletedAt `gorm:"comment:软删除标记" json:"deleted_at"`
 	// 关联作者：无需级联（删除文章不影响用户），保持不变
 	User User `gorm:"foreignKey:UserID" json:"user"`
+	// 封面图片：删除媒体时置空
+	CoverMedia *Media `gorm:"foreignKey:CoverMediaID;constraint:OnDelete:SET NULL" json:"cover_media"`
 	// 关键修改：添加 OnDelete:CASCADE，与 SQL 级联删除逻辑对齐
 	Comments []Comment `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"comments"`
+	// 关联标签：多对多，中间表 post_tags
+	Tags []Tag `gorm:"many2many:post_tags" json:"tags"`
+	// 文章引用的媒体：多对多，中间表 post_media
+	Media []Media `gorm:"many2many:post_media" json:"media"`
 }
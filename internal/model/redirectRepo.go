@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Redirect 旧地址重定向：迁移前的文章地址永久重定向到本站文章页
+type Redirect struct {
+	ID        uint      `gorm:"type:bigint;primaryKey;autoIncrement;comment:重定向唯一标识" json:"id"`
+	FromPath  string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_redirect_from;comment:旧地址（路径，可带查询参数，如 /2019/05/hello/ 或 /?p=12）" json:"from_path"`
+	PostID    uint      `gorm:"type:bigint;not null;index:idx_redirect_post;comment:目标文章ID" json:"post_id"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+}
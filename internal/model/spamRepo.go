@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+// SpamToken 垃圾评论贝叶斯分类器的词频统计
+type SpamToken struct {
+	ID        uint      `gorm:"type:bigint;primaryKey;autoIncrement;comment:唯一标识" json:"id"`
+	Token     string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_spam_token;comment:词" json:"token"`
+	SpamCount int64     `gorm:"type:bigint;not null;default:0;comment:出现该词的垃圾评论数" json:"spam_count"`
+	HamCount  int64     `gorm:"type:bigint;not null;default:0;comment:出现该词的正常评论数" json:"ham_count"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+}
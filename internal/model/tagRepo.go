@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+// Tag 标签模型
+type Tag struct {
+	ID        uint      `gorm:"type:bigint;primaryKey;autoIncrement;comment:标签唯一标识" json:"id"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_tag_name;comment:标签名（唯一）" json:"name"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	// 关联文章：多对多，中间表 post_tags
+	Posts []Post `gorm:"many2many:post_tags" json:"posts"`
+}
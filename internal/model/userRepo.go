@@ -6,20 +6,53 @@ import (
 	"gorm.io/gorm"
 )
 
+// 用户角色
+const (
+	UserRoleUser      = "user"      // 普通用户
+	UserRoleModerator = "moderator" // 版主：可管理他人评论
+	UserRoleAdmin     = "admin"     // 管理员
+)
+
 // User 用户模型
 type User struct {
-	ID        uint           `gorm:"type:bigint;primaryKey;autoIncrement;comment:用户唯一标识" json:"id"`
-	Username  string         `gorm:"type:varchar(50);not null;uniqueIndex:idx_username;comment:用户名（唯一）" json:"username"`
-	Password  string         `gorm:"type:varchar(100);not null;comment:加密存储的密码" json:"password"`
-	Email     string         `gorm:"type:varchar(100);not null;uniqueIndex:idx_email;comment:邮箱（唯一）" json:"email"`
-	CreatedAt time.Time      `gorm:"comment:创建时间" json:"created_at"`
-	UpdatedAt time.Time      `gorm:"comment:更新时间" json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"comment:软删除标记" json:"deleted_at"`
+	ID       uint   `gorm:"type:bigint;primaryKey;autoIncrement;comment:用户唯一标识" json:"id"`
+	Username string `gorm:"type:varchar(50);not null;uniqueIndex:idx_username;comment:用户名（唯一）" json:"username"`
+	Password string `gorm:"type:varchar(100);not null;comment:加密存储的密码" json:"password"`
+	Email    string `gorm:"type:varchar(100);not null;uniqueIndex:idx_email;comment:邮箱（唯一）" json:"email"`
+	Role     string `gorm:"type:varchar(20);not null;default:user;comment:角色（user/moderator/admin）" json:"role"`
+	// 接口提示信息的语言偏好，为空时按请求头 Accept-Language 协商
+	Locale string `gorm:"type:varchar(10);not null;default:'';comment:语言偏好（zh-CN/en-US）" json:"locale"`
+	// 导入的用户没有可用密码，需重置密码后才能登录
+	PasswordResetRequired bool `gorm:"not null;default:false;comment:是否需要重置密码" json:"password_reset_required"`
+	// 导入工具创建的用户记录导入来源，重复导入时只复用同一来源创建的用户，不会与同名的注册用户合并
+	ImportSource string `gorm:"type:varchar(20);not null;default:'';comment:导入来源（如 wordpress，注册用户为空）" json:"import_source"`
+	// 申请注销后记录计划删除时间，冷静期内可撤销；到期后由后台任务删除账号
+	DeletionScheduledAt *time.Time     `gorm:"index:idx_user_deletion;comment:计划删除时间（未申请注销为空）" json:"deletion_scheduled_at"`
+	DeletionCommentMode string         `gorm:"type:varchar(10);not null;default:'';comment:注销时评论的处理方式（anonymize/delete）" json:"deletion_comment_mode"`
+	CreatedAt           time.Time      `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt           time.Time      `gorm:"comment:更新时间" json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `gorm:"comment:软删除标记" json:"deleted_at"`
 	// 添加 OnDelete:CASCADE，与 SQL 级联删除逻辑对齐
 	Posts    []Post    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"posts"`
 	Comments []Comment `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"comments"`
 }
 
+// 注销账号时评论的处理方式
+const (
+	CommentModeAnonymize = "anonymize" // 保留评论，去除作者信息
+	CommentModeDelete    = "delete"    // 删除评论（有回复的评论保留已删除占位）
+)
+
+// IsModerator 是否拥有评论管理权限（版主或管理员）
+func (u *User) IsModerator() bool {
+	return u.Role == UserRoleModerator || u.Role == UserRoleAdmin
+}
+
+// IsAdmin 是否为管理员
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
 //func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
 //
 //}
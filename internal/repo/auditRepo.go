@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"context"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (ar *AuditRepository) Create(ctx context.Context, log *model.AuditLog) error {
+	if err := ar.db.WithContext(ctx).Create(log).Error; err != nil {
+		logger.Error("AuditRepository.Create is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
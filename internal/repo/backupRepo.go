@@ -0,0 +1,158 @@
+package repo
+
+import (
+	"context"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// backupBatchSize 导出时每批读取的记录数
+const backupBatchSize = 500
+
+// PostRelation 文章与标签/媒体的关联（TargetID 为标签或媒体ID）
+type PostRelation struct {
+	PostID   uint
+	TargetID uint
+}
+
+// BackupRepository 整站导出和恢复：按批次读取全部数据（含软删除），恢复时在一个事务中写入
+type BackupRepository struct {
+	db *gorm.DB
+}
+
+func NewBackupRepository(db *gorm.DB) *BackupRepository {
+	return &BackupRepository{db: db}
+}
+
+// eachBatch 按主键顺序分批读取全部记录（含软删除）
+func eachBatch[T any](db *gorm.DB, name string, fn func([]T) error) error {
+	var batch []T
+	err := db.Unscoped().Model(new(T)).FindInBatches(&batch, backupBatchSize, func(tx *gorm.DB, n int) error {
+		return fn(batch)
+	}).Error
+	if err != nil {
+		logger.Error("BackupRepository."+name+" is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (ar *BackupRepository) EachUser(ctx context.Context, fn func([]model.User) error) error {
+	return eachBatch(ar.db.WithContext(ctx), "EachUser", fn)
+}
+
+func (ar *BackupRepository) EachMedia(ctx context.Context, fn func([]model.Media) error) error {
+	return eachBatch(ar.db.WithContext(ctx), "EachMedia", fn)
+}
+
+func (ar *BackupRepository) EachTag(ctx context.Context, fn func([]model.Tag) error) error {
+	return eachBatch(ar.db.WithContext(ctx), "EachTag", fn)
+}
+
+func (ar *BackupRepository) EachPost(ctx context.Context, fn func([]model.Post) error) error {
+	return eachBatch(ar.db.WithContext(ctx), "EachPost", fn)
+}
+
+func (ar *BackupRepository) EachComment(ctx context.Context, fn func([]model.Comment) error) error {
+	return eachBatch(ar.db.WithContext(ctx), "EachComment", fn)
+}
+
+func (ar *BackupRepository) EachCommentRevision(ctx context.Context, fn func([]model.CommentRevision) error) error {
+	return eachBatch(ar.db.WithContext(ctx), "EachCommentRevision", fn)
+}
+
+func (ar *BackupRepository) EachRedirect(ctx context.Context, fn func([]model.Redirect) error) error {
+	return eachBatch(ar.db.WithContext(ctx), "EachRedirect", fn)
+}
+
+func (ar *BackupRepository) EachSpamToken(ctx context.Context, fn func([]model.SpamToken) error) error {
+	return eachBatch(ar.db.WithContext(ctx), "EachSpamToken", fn)
+}
+
+// EachPostTag 分批读取文章与标签的关联
+func (ar *BackupRepository) EachPostTag(ctx context.Context, fn func([]PostRelation) error) error {
+	return ar.eachRelation(ctx, "post_tags", "tag_id", fn)
+}
+
+// EachPostMedia 分批读取文章与媒体的引用关系
+func (ar *BackupRepository) EachPostMedia(ctx context.Context, fn func([]PostRelation) error) error {
+	return ar.eachRelation(ctx, "post_media", "media_id", fn)
+}
+
+// eachRelation 中间表没有自增主键，按联合主键排序分页读取
+func (ar *BackupRepository) eachRelation(ctx context.Context, table string, column string, fn func([]PostRelation) error) error {
+	for offset := 0; ; offset += backupBatchSize {
+		var batch []PostRelation
+		err := ar.db.WithContext(ctx).Table(table).Select("post_id, " + column + " AS target_id").
+			Order("post_id ASC, " + column + " ASC").Offset(offset).Limit(backupBatchSize).Scan(&batch).Error
+		if err != nil {
+			logger.Error("BackupRepository.eachRelation is error", zap.String("table", table), zap.Error(err))
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < backupBatchSize {
+			return nil
+		}
+	}
+}
+
+// IsEmpty 数据库中是否没有任何内容数据（含软删除），恢复只允许在空库上进行
+func (ar *BackupRepository) IsEmpty(ctx context.Context) (bool, error) {
+	for _, value := range []interface{}{&model.User{}, &model.Post{}, &model.Tag{}, &model.Comment{}, &model.Media{}, &model.Redirect{}, &model.SpamToken{}} {
+		var count int64
+		if err := ar.db.WithContext(ctx).Unscoped().Model(value).Count(&count).Error; err != nil {
+			logger.Error("BackupRepository.IsEmpty is error", zap.Error(err))
+			return false, err
+		}
+		if count > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Create 写入一条记录（不处理关联），ID 由数据库重新分配
+func (ar *BackupRepository) Create(ctx context.Context, value interface{}) error {
+	if err := ar.db.WithContext(ctx).Omit(clause.Associations).Create(value).Error; err != nil {
+		logger.Error("BackupRepository.Create is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// UpdateCommentPath 评论入库拿到新ID后回写物化路径
+func (ar *BackupRepository) UpdateCommentPath(ctx context.Context, id uint, path string) error {
+	if err := ar.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", id).Update("path", path).Error; err != nil {
+		logger.Error("BackupRepository.UpdateCommentPath is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// CreatePostTag 写入文章与标签的关联
+func (ar *BackupRepository) CreatePostTag(ctx context.Context, postID uint, tagID uint) error {
+	return ar.createRelation(ctx, "post_tags", "tag_id", postID, tagID)
+}
+
+// CreatePostMedia 写入文章与媒体的引用关系
+func (ar *BackupRepository) CreatePostMedia(ctx context.Context, postID uint, mediaID uint) error {
+	return ar.createRelation(ctx, "post_media", "media_id", postID, mediaID)
+}
+
+func (ar *BackupRepository) createRelation(ctx context.Context, table string, column string, postID uint, targetID uint) error {
+	err := ar.db.WithContext(ctx).Table(table).Create(map[string]interface{}{"post_id": postID, column: targetID}).Error
+	if err != nil {
+		logger.Error("BackupRepository.createRelation is error", zap.String("table", table), zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -1,9 +1,10 @@
 package repo
 
 import (
-	"go-my-blog/internal/DTO"
+	"context"
 	"go-my-blog/internal/model"
 	"go-my-blog/pkg/logger"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -17,68 +18,238 @@ func NewCommentRepository(db *gorm.DB) *CommentRepository {
 	return &CommentRepository{db: db}
 }
 
-func (cr CommentRepository) ListComments(dto DTO.ListCommentDTO) (*[]model.Comment, int64, error) {
-	tx := cr.db.Model(&model.Comment{})
+// DeleteByPostId 随文章软删除其下的评论：删除时间与文章的删除时间一致（文章需先删除），
+// 恢复文章时据此只恢复随文章一起删除的评论，之前单独删除的评论仍留在回收站
+func (cr CommentRepository) DeleteByPostId(ctx context.Context, postId uint) error {
+	tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("post_id = ? AND deleted_at IS NULL", postId).
+		UpdateColumn("deleted_at", cr.db.WithContext(ctx).Unscoped().Model(&model.Post{}).Select("deleted_at").Where("id = ?", postId))
+	if tx.Error != nil {
+		logger.Error("CommentRepository.DeleteByPostId is error", zap.Error(tx.Error))
+		return tx.Error
+	}
+	return nil
+}
 
-	if dto.Keyword != "" {
-		tx = tx.Where("title LIKE ? OR content LIKE ?", "%"+dto.Keyword+"%", "%"+dto.Keyword+"%")
+func (cr CommentRepository) GetById(ctx context.Context, id uint) (*model.Comment, error) {
+	var comment model.Comment
+	tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", id).First(&comment)
+	if tx.Error != nil {
+		logger.Error("CommentRepository.GetById is error", zap.Error(tx.Error))
+		return nil, tx.Error
 	}
+	return &comment, nil
+}
 
-	var total int64
-	if err := tx.Where("post_id = ?", dto.PostId).Count(&total).Error; err != nil {
-		logger.Error("PostRepository.ListPosts db.Count is error", zap.Error(err))
-		return nil, 0, err
+func (cr CommentRepository) DeleteById(ctx context.Context, id uint) error {
+	tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", id).Delete(&model.Comment{})
+	if tx.Error != nil {
+		logger.Error("CommentRepository.DeleteById is error", zap.Error(tx.Error))
+		return tx.Error
+	}
+	return nil
+}
+
+func (cr CommentRepository) Create(ctx context.Context, comment *model.Comment) (*model.Comment, error) {
+	if err := cr.db.WithContext(ctx).Create(comment).Error; err != nil {
+		logger.Error("CommentRepository.Create is error", zap.Error(err))
+		return nil, err
 	}
+	return comment, nil
+}
 
+// ListImported 文章下已导入的评论（按来源标识），用于重复导入时去重和恢复回复关系
+func (cr CommentRepository) ListImported(ctx context.Context, postId uint) ([]model.Comment, error) {
 	var comments []model.Comment
-	if dto.PageNum != 0 && dto.PageSize != 0 {
-		offset := (dto.PageNum - 1) * dto.PageSize
-		if err := tx.Where("post_id = ?", dto.PostId).Offset(offset).Limit(dto.PageSize).Find(&comments).Error; err != nil {
-			logger.Error("PostRepository.ListPosts db.Find is error", zap.Error(err))
-			return nil, 0, err
-		}
+	if err := cr.db.WithContext(ctx).Model(&model.Comment{}).Select("id", "source_id", "path", "depth", "parent_id").
+		Where("post_id = ? AND source_id <> ''", postId).Find(&comments).Error; err != nil {
+		logger.Error("CommentRepository.ListImported is error", zap.Error(err))
+		return nil, err
 	}
+	return comments, nil
+}
 
-	if err := tx.Where("post_id = ?", dto.PostId).Find(&comments).Error; err != nil {
-		logger.Error("PostRepository.ListPosts db.Find is error", zap.Error(err))
-		return nil, 0, err
+// ListByUser 查询用户发表的全部评论（不含已删除占位），个人数据导出使用
+func (cr CommentRepository) ListByUser(ctx context.Context, userID uint) ([]model.Comment, error) {
+	var comments []model.Comment
+	if err := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("user_id = ? AND is_deleted = ?", userID, false).Order("id ASC").Find(&comments).Error; err != nil {
+		logger.Error("CommentRepository.ListByUser is error", zap.Error(err))
+		return nil, err
 	}
+	return comments, nil
+}
 
-	return &comments, total, nil
+// UpdatePath 创建评论后回填物化路径（路径中包含自身ID，需在拿到自增ID后写入）
+func (cr CommentRepository) UpdatePath(ctx context.Context, id uint, path string) error {
+	tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", id).UpdateColumn("path", path)
+	if tx.Error != nil {
+		logger.Error("CommentRepository.UpdatePath is error", zap.Error(tx.Error))
+		return tx.Error
+	}
+	return nil
 }
 
-func (cr CommentRepository) DeleteByPostId(postId uint) error {
-	tx := cr.db.Model(&model.Comment{}).Where("post_id = ?", postId).Delete(&model.Comment{})
+// IncrReplyCount 调整评论的直接回复数，delta 可为负数
+func (cr CommentRepository) IncrReplyCount(ctx context.Context, id uint, delta int) error {
+	tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", id).UpdateColumn("reply_count", gorm.Expr("CASE WHEN reply_count + ? > 0 THEN reply_count + ? ELSE 0 END", delta, delta))
 	if tx.Error != nil {
-		logger.Error("CommentRepository.DeleteByPostId is error", zap.Error(tx.Error))
+		logger.Error("CommentRepository.IncrReplyCount is error", zap.Error(tx.Error))
 		return tx.Error
 	}
 	return nil
 }
 
-func (cr CommentRepository) GetById(id uint) (*model.Comment, error) {
-	var comment model.Comment
-	tx := cr.db.Model(&model.Comment{}).Where("id = ?", id).First(&comment)
+// MarkDeleted 将评论标记为已删除占位：清空内容但保留节点，保证回复树结构完整
+func (cr CommentRepository) MarkDeleted(ctx context.Context, id uint) error {
+	tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"is_deleted":   true,
+		"content":      "",
+		"content_html": "",
+	})
 	if tx.Error != nil {
-		logger.Error("CommentRepository.GetById is error", zap.Error(tx.Error))
-		return nil, tx.Error
+		logger.Error("CommentRepository.MarkDeleted is error", zap.Error(tx.Error))
+		return tx.Error
 	}
-	return &comment, nil
+	return nil
+}
+
+// ListByPostId 查询文章下审核通过的全部评论，按物化路径排序（父评论总在其回复之前）
+func (cr CommentRepository) ListByPostId(ctx context.Context, postId uint) ([]model.Comment, error) {
+	var comments []model.Comment
+	if err := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("post_id = ? AND status = ?", postId, model.CommentStatusApproved).Order("path ASC, id ASC").Find(&comments).Error; err != nil {
+		logger.Error("CommentRepository.ListByPostId is error", zap.Error(err))
+		return nil, err
+	}
+	return comments, nil
+}
+
+// ListApprovedStamps 查询全部审核通过的评论中影响文章页渲染的字段，按文章和ID排序（静态导出判断文章页是否需要重新生成）
+func (cr CommentRepository) ListApprovedStamps(ctx context.Context) ([]model.Comment, error) {
+	var comments []model.Comment
+	if err := cr.db.WithContext(ctx).Model(&model.Comment{}).
+		Select("id", "post_id", "reply_count", "is_deleted", "edited_at", "updated_at").
+		Where("status = ?", model.CommentStatusApproved).Order("post_id ASC, id ASC").Find(&comments).Error; err != nil {
+		logger.Error("CommentRepository.ListApprovedStamps is error", zap.Error(err))
+		return nil, err
+	}
+	return comments, nil
 }
 
-func (cr CommentRepository) DeleteById(id uint) error {
-	tx := cr.db.Model(&model.Comment{}).Where("id = ?", id).Delete(&model.Comment{})
+// ListByPathPrefix 查询物化路径以 prefix 开头、审核通过的整棵子树（含根节点），走 path 索引的前缀匹配
+func (cr CommentRepository) ListByPathPrefix(ctx context.Context, prefix string) ([]model.Comment, error) {
+	var comments []model.Comment
+	if err := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("path LIKE ? AND status = ?", prefix+"%", model.CommentStatusApproved).Order("path ASC").Find(&comments).Error; err != nil {
+		logger.Error("CommentRepository.ListByPathPrefix is error", zap.Error(err))
+		return nil, err
+	}
+	return comments, nil
+}
+
+// BackfillRootPaths 为历史根评论回填物化路径（引入楼中楼之前创建的评论 path 为空）；
+// 路径在 Go 中生成，不依赖特定数据库的字符串函数
+func (cr CommentRepository) BackfillRootPaths(ctx context.Context) error {
+	var ids []uint
+	if err := cr.db.WithContext(ctx).Model(&model.Comment{}).Unscoped().Where("path = '' AND parent_id IS NULL").Pluck("id", &ids).Error; err != nil {
+		logger.Error("CommentRepository.BackfillRootPaths Pluck is error", zap.Error(err))
+		return err
+	}
+	for _, id := range ids {
+		tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Unscoped().Where("id = ?", id).UpdateColumn("path", model.CommentPathSegment(id))
+		if tx.Error != nil {
+			logger.Error("CommentRepository.BackfillRootPaths is error", zap.Error(tx.Error))
+			return tx.Error
+		}
+	}
+	return nil
+}
+
+// UpdateContent 更新评论内容及其 HTML 缓存、重新检测后的审核状态和垃圾评论得分，并记录编辑时间
+func (cr CommentRepository) UpdateContent(ctx context.Context, comment *model.Comment, editedAt time.Time) error {
+	tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", comment.ID).Updates(map[string]interface{}{
+		"content":        comment.Content,
+		"content_format": comment.ContentFormat,
+		"content_html":   comment.ContentHTML,
+		"render_version": comment.RenderVersion,
+		"status":         comment.Status,
+		"spam_score":     comment.SpamScore,
+		"edited_at":      editedAt,
+	})
 	if tx.Error != nil {
-		logger.Error("CommentRepository.DeleteById is error", zap.Error(tx.Error))
+		logger.Error("CommentRepository.UpdateContent is error", zap.Error(tx.Error))
 		return tx.Error
 	}
 	return nil
 }
 
-func (cr CommentRepository) Create(comment *model.Comment) (*model.Comment, error) {
-	if err := cr.db.Create(comment).Error; err != nil {
-		logger.Error("CommentRepository.Create is error", zap.Error(err))
+// CreateRevision 保存评论的一个历史版本
+func (cr CommentRepository) CreateRevision(ctx context.Context, revision *model.CommentRevision) error {
+	if err := cr.db.WithContext(ctx).Create(revision).Error; err != nil {
+		logger.Error("CommentRepository.CreateRevision is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListByStatus 按审核状态分页查询评论（审核队列），先提交的先审核
+func (cr CommentRepository) ListByStatus(ctx context.Context, status string, pageNum int, pageSize int) ([]model.Comment, int64, error) {
+	tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("status = ?", status)
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		logger.Error("CommentRepository.ListByStatus db.Count is error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	var comments []model.Comment
+	offset := (pageNum - 1) * pageSize
+	if err := tx.Order("created_at ASC, id ASC").Offset(offset).Limit(pageSize).Find(&comments).Error; err != nil {
+		logger.Error("CommentRepository.ListByStatus db.Find is error", zap.Error(err))
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// GetByIds 根据ID批量查询评论
+func (cr CommentRepository) GetByIds(ctx context.Context, ids []uint) ([]model.Comment, error) {
+	var comments []model.Comment
+	if err := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("id IN ?", ids).Find(&comments).Error; err != nil {
+		logger.Error("CommentRepository.GetByIds is error", zap.Error(err))
 		return nil, err
 	}
-	return comment, nil
+	return comments, nil
+}
+
+// UpdateStatus 更新评论审核状态
+func (cr CommentRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
+	tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", id).UpdateColumn("status", status)
+	if tx.Error != nil {
+		logger.Error("CommentRepository.UpdateStatus is error", zap.Error(tx.Error))
+		return tx.Error
+	}
+	return nil
+}
+
+// UpdateSpamLabel 记录评论训练分类器时使用的标签，改判时据此撤销上一次训练
+func (cr CommentRepository) UpdateSpamLabel(ctx context.Context, id uint, label string) error {
+	tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", id).UpdateColumn("spam_label", label)
+	if tx.Error != nil {
+		logger.Error("CommentRepository.UpdateSpamLabel is error", zap.Error(tx.Error))
+		return tx.Error
+	}
+	return nil
+}
+
+// CountDuplicates 统计 since 之后同一用户或同一IP发表的相同内容评论数（重复评论检测）
+func (cr CommentRepository) CountDuplicates(ctx context.Context, userID uint, ip string, content string, since time.Time) (int64, error) {
+	var count int64
+	tx := cr.db.WithContext(ctx).Model(&model.Comment{}).Where("content = ? AND created_at >= ?", content, since)
+	if ip != "" {
+		tx = tx.Where("user_id = ? OR ip = ?", userID, ip)
+	} else {
+		tx = tx.Where("user_id = ?", userID)
+	}
+	if err := tx.Count(&count).Error; err != nil {
+		logger.Error("CommentRepository.CountDuplicates is error", zap.Error(err))
+		return 0, err
+	}
+	return count, nil
 }
@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type DataExportRepository struct {
+	db *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) *DataExportRepository {
+	return &DataExportRepository{db: db}
+}
+
+func (dr *DataExportRepository) Create(ctx context.Context, export *model.DataExport) error {
+	if err := dr.db.WithContext(ctx).Create(export).Error; err != nil {
+		logger.Error("DataExportRepository.Create is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (dr *DataExportRepository) GetById(ctx context.Context, id uint) (*model.DataExport, error) {
+	var export model.DataExport
+	if err := dr.db.WithContext(ctx).Model(&model.DataExport{}).Where("id = ?", id).First(&export).Error; err != nil {
+		logger.Error("DataExportRepository.GetById is error", zap.Error(err))
+		return nil, err
+	}
+	return &export, nil
+}
+
+// Updates 更新导出任务的状态和结果
+func (dr *DataExportRepository) Updates(ctx context.Context, id uint, updateMap map[string]interface{}) error {
+	if err := dr.db.WithContext(ctx).Model(&model.DataExport{}).Where("id = ?", id).Updates(updateMap).Error; err != nil {
+		logger.Error("DataExportRepository.Updates is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// CountPending 用户在 since 之后创建、仍在生成中的导出任务数（更早的视为服务重启后中断的任务）
+func (dr *DataExportRepository) CountPending(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	var count int64
+	if err := dr.db.WithContext(ctx).Model(&model.DataExport{}).Where("user_id = ? AND status = ? AND created_at > ?", userID, model.DataExportPending, since).Count(&count).Error; err != nil {
+		logger.Error("DataExportRepository.CountPending is error", zap.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListExpired 已过期的导出任务
+func (dr *DataExportRepository) ListExpired(ctx context.Context, now time.Time) ([]model.DataExport, error) {
+	var exports []model.DataExport
+	if err := dr.db.WithContext(ctx).Model(&model.DataExport{}).Where("expires_at IS NOT NULL AND expires_at <= ?", now).Find(&exports).Error; err != nil {
+		logger.Error("DataExportRepository.ListExpired is error", zap.Error(err))
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (dr *DataExportRepository) Delete(ctx context.Context, id uint) error {
+	if err := dr.db.WithContext(ctx).Delete(&model.DataExport{}, id).Error; err != nil {
+		logger.Error("DataExportRepository.Delete is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
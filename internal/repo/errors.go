@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlErrDuplicateEntry 违反唯一索引
+const mysqlErrDuplicateEntry = 1062
+
+// IsDuplicateKey 写入违反唯一索引（如用户名已存在）
+func IsDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
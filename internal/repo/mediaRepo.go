@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"context"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type MediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository(db *gorm.DB) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+func (mr *MediaRepository) Create(ctx context.Context, media *model.Media) error {
+	if err := mr.db.WithContext(ctx).Create(media).Error; err != nil {
+		logger.Error("MediaRepository.Create is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (mr *MediaRepository) GetById(ctx context.Context, id uint) (*model.Media, error) {
+	var media model.Media
+	if err := mr.db.WithContext(ctx).Model(&model.Media{}).Where("id = ?", id).First(&media).Error; err != nil {
+		logger.Error("MediaRepository.GetById is error", zap.Error(err))
+		return nil, err
+	}
+	return &media, nil
+}
+
+// ListByUser 分页查询用户上传的媒体，最新的在前
+func (mr *MediaRepository) ListByUser(ctx context.Context, userID uint, pageNum int, pageSize int) ([]model.Media, int64, error) {
+	tx := mr.db.WithContext(ctx).Model(&model.Media{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		logger.Error("MediaRepository.ListByUser db.Count is error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	var media []model.Media
+	offset := (pageNum - 1) * pageSize
+	if err := tx.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&media).Error; err != nil {
+		logger.Error("MediaRepository.ListByUser db.Find is error", zap.Error(err))
+		return nil, 0, err
+	}
+	return media, total, nil
+}
+
+// ListAll 全部媒体（只取存储相关字段，静态导出复制文件使用）
+func (mr *MediaRepository) ListAll(ctx context.Context) ([]model.Media, error) {
+	var media []model.Media
+	if err := mr.db.WithContext(ctx).Model(&model.Media{}).Select("id", "storage", "storage_key", "thumbnails").Order("id ASC").Find(&media).Error; err != nil {
+		logger.Error("MediaRepository.ListAll is error", zap.Error(err))
+		return nil, err
+	}
+	return media, nil
+}
+
+// FindByUIDs 根据随机标识批量查询媒体
+func (mr *MediaRepository) FindByUIDs(ctx context.Context, uids []string) ([]model.Media, error) {
+	media := []model.Media{}
+	if len(uids) == 0 {
+		return media, nil
+	}
+	if err := mr.db.WithContext(ctx).Model(&model.Media{}).Where("uid IN ?", uids).Find(&media).Error; err != nil {
+		logger.Error("MediaRepository.FindByUIDs is error", zap.Error(err))
+		return nil, err
+	}
+	return media, nil
+}
+
+// ReplacePostMedia 用给定媒体整体替换文章的媒体引用
+func (mr *MediaRepository) ReplacePostMedia(ctx context.Context, postID uint, media []model.Media) error {
+	post := model.Post{ID: postID}
+	if err := mr.db.WithContext(ctx).Model(&post).Association("Media").Replace(media); err != nil {
+		logger.Error("MediaRepository.ReplacePostMedia Association.Replace is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListReferencingPosts 查询引用该媒体的文章（只取 ID 和标题）
+func (mr *MediaRepository) ListReferencingPosts(ctx context.Context, mediaID uint) ([]model.Post, error) {
+	var posts []model.Post
+	err := mr.db.WithContext(ctx).Model(&model.Post{}).Select("posts.id", "posts.title").
+		Joins("JOIN post_media ON post_media.post_id = posts.id").
+		Where("post_media.media_id = ?", mediaID).
+		Order("posts.id DESC").Find(&posts).Error
+	if err != nil {
+		logger.Error("MediaRepository.ListReferencingPosts is error", zap.Error(err))
+		return nil, err
+	}
+	return posts, nil
+}
+
+// CountReferences 批量统计媒体被多少篇文章引用，返回 mediaID -> 文章数
+func (mr *MediaRepository) CountReferences(ctx context.Context, mediaIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(mediaIDs))
+	if len(mediaIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		MediaID uint
+		Total   int64
+	}
+	err := mr.db.WithContext(ctx).Table("post_media").
+		Select("post_media.media_id, COUNT(*) AS total").
+		Joins("JOIN posts ON posts.id = post_media.post_id AND posts.deleted_at IS NULL").
+		Where("post_media.media_id IN ?", mediaIDs).
+		Group("post_media.media_id").Scan(&rows).Error
+	if err != nil {
+		logger.Error("MediaRepository.CountReferences is error", zap.Error(err))
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.MediaID] = row.Total
+	}
+	return counts, nil
+}
+
+// Delete 删除媒体记录及其文章引用关系
+func (mr *MediaRepository) Delete(ctx context.Context, media *model.Media) error {
+	return mr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(media).Association("Posts").Clear(); err != nil {
+			logger.Error("MediaRepository.Delete Association.Clear is error", zap.Error(err))
+			return err
+		}
+		if err := tx.Delete(&model.Media{}, media.ID).Error; err != nil {
+			logger.Error("MediaRepository.Delete db.Delete is error", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+}
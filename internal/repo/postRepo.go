@@ -1,6 +1,8 @@
 package repo
 
 import (
+	"context"
+	"errors"
 	"go-my-blog/internal/DTO"
 	"go-my-blog/internal/model"
 	"go-my-blog/pkg/logger"
@@ -17,8 +19,8 @@ func NewPostRepository(db *gorm.DB) *PostRepository {
 	return &PostRepository{db: db}
 }
 
-func (pr *PostRepository) Create(post *model.Post) (*model.Post, error) {
-	if err := pr.db.Create(post).Error; err != nil {
+func (pr *PostRepository) Create(ctx context.Context, post *model.Post) (*model.Post, error) {
+	if err := pr.db.WithContext(ctx).Create(post).Error; err != nil {
 		logger.Error("PostRepository.Create db.Create is error", zap.Error(err))
 		return nil, err
 	}
@@ -33,12 +35,12 @@ func (pr *PostRepository) Create(post *model.Post) (*model.Post, error) {
 // 返回值:
 //   - *model.Post: 指向文章模型的指针，如果找到则返回文章数据
 //   - error: 错误信息，如果查询过程中发生错误则返回错误
-func (pr *PostRepository) GetById(id uint) (*model.Post, error) {
+func (pr *PostRepository) GetById(ctx context.Context, id uint) (*model.Post, error) {
 	// 声明一个Post结构体变量，用于存储查询结果
 	var post model.Post
 	// 执行数据库查询，根据ID查找文章
 	// 如果查询过程中发生错误，记录错误日志并返回错误
-	if err := pr.db.Model(&model.Post{}).Where("id = ?", id).First(&post).Error; err != nil {
+	if err := pr.db.WithContext(ctx).Model(&model.Post{}).Where("id = ?", id).First(&post).Error; err != nil {
 		logger.Error("PostRepository.GetById db.First is error", zap.Error(err))
 		return nil, err
 	}
@@ -55,9 +57,9 @@ func (pr *PostRepository) GetById(id uint) (*model.Post, error) {
 // 返回值:
 //
 //	error - 操作过程中遇到的错误，如果没有错误则返回nil
-func (pr *PostRepository) Updates(id uint, updateMap *map[string]interface{}) error {
+func (pr *PostRepository) Updates(ctx context.Context, id uint, updateMap *map[string]interface{}) error {
 	// 创建数据库事务，更新指定ID的帖子记录
-	tx := pr.db.Model(&model.Post{}).Where("id = ?", id).Updates(updateMap)
+	tx := pr.db.WithContext(ctx).Model(&model.Post{}).Where("id = ?", id).Updates(updateMap)
 	// 检查数据库操作是否出错
 	if tx.Error != nil {
 		logger.Error("PostRepository.Updates db.Updates is error", zap.Error(tx.Error))
@@ -80,19 +82,46 @@ func (pr *PostRepository) Updates(id uint, updateMap *map[string]interface{}) er
 // 返回值:
 //
 //	error: 如果删除失败则返回错误信息
-func (pr *PostRepository) Delete(id uint) error {
+func (pr *PostRepository) Delete(ctx context.Context, id uint) error {
 	// 使用GORM的Model方法和Where条件找到指定ID的帖子记录
 	// 然后调用Delete方法删除该记录
 	// 如果删除过程中出现错误，则返回该错误
-	return pr.db.Model(&model.Post{}).Where("id = ?", id).Delete(&model.Post{}).Error
+	return pr.db.WithContext(ctx).Model(&model.Post{}).Where("id = ?", id).Delete(&model.Post{}).Error
 }
 
-func (pr *PostRepository) ListPosts(dto *DTO.ListPostDTO) (*[]model.Post, int64, error) {
-	tx := pr.db.Model(&model.Post{})
+// postSortOrders 排序方式白名单到 ORDER BY 子句的映射，只允许白名单内的排序进入 SQL
+var postSortOrders = map[string]string{
+	DTO.PostSortNewest:        "posts.created_at DESC, posts.id DESC",
+	DTO.PostSortOldest:        "posts.created_at ASC, posts.id ASC",
+	DTO.PostSortMostCommented: "(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL) DESC, posts.id DESC",
+	DTO.PostSortMostViewed:    "posts.view_count DESC, posts.id DESC",
+	DTO.PostSortTitle:         "posts.title ASC, posts.id ASC",
+}
+
+func (pr *PostRepository) ListPosts(ctx context.Context, dto *DTO.ListPostDTO) (*[]model.Post, int64, error) {
+	tx := pr.db.WithContext(ctx).Model(&model.Post{})
 
 	if dto.Keyword != "" {
 		tx = tx.Where("title LIKE ? OR content LIKE ?", "%"+dto.Keyword+"%", "%"+dto.Keyword+"%")
 	}
+	if dto.AuthorID != 0 {
+		tx = tx.Where("posts.user_id = ?", dto.AuthorID)
+	}
+	if dto.Status != "" {
+		tx = tx.Where("posts.status = ?", dto.Status)
+	}
+	if dto.StartTime != nil {
+		tx = tx.Where("posts.created_at >= ?", *dto.StartTime)
+	}
+	if dto.EndTime != nil {
+		tx = tx.Where("posts.created_at < ?", *dto.EndTime)
+	}
+	if dto.Tag != "" {
+		tx = tx.Where("posts.id IN (?)", pr.db.WithContext(ctx).Table("post_tags").
+			Select("post_tags.post_id").
+			Joins("JOIN tags ON tags.id = post_tags.tag_id").
+			Where("tags.name = ?", dto.Tag))
+	}
 
 	var total int64
 	if err := tx.Count(&total).Error; err != nil {
@@ -100,11 +129,174 @@ func (pr *PostRepository) ListPosts(dto *DTO.ListPostDTO) (*[]model.Post, int64,
 		return nil, 0, err
 	}
 
+	order, ok := postSortOrders[dto.Sort]
+	if !ok {
+		order = postSortOrders[DTO.PostSortNewest]
+	}
+
 	var posts []model.Post
 	offset := (dto.PageNum - 1) * dto.PageSize
-	if err := tx.Offset(offset).Limit(dto.PageSize).Find(&posts).Error; err != nil {
+	if err := tx.Preload("Tags").Preload("CoverMedia").Order(order).Offset(offset).Limit(dto.PageSize).Find(&posts).Error; err != nil {
 		logger.Error("PostRepository.ListPosts db.Find is error", zap.Error(err))
 		return nil, 0, err
 	}
 	return &posts, total, nil
 }
+
+// ListRecentPublished 按创建时间倒序查询最近发布的文章，预加载作者、标签和封面（订阅源使用）
+// 参数:
+//   - authorID: 作者ID，0 表示不限
+//   - tag: 标签名，空表示不限
+//   - limit: 最多返回条数
+func (pr *PostRepository) ListRecentPublished(ctx context.Context, authorID uint, tag string, limit int) ([]model.Post, error) {
+	tx := pr.db.WithContext(ctx).Model(&model.Post{}).Where("posts.status = ?", model.PostStatusPublished)
+	if authorID != 0 {
+		tx = tx.Where("posts.user_id = ?", authorID)
+	}
+	if tag != "" {
+		tx = tx.Where("posts.id IN (?)", pr.db.WithContext(ctx).Table("post_tags").
+			Select("post_tags.post_id").
+			Joins("JOIN tags ON tags.id = post_tags.tag_id").
+			Where("tags.name = ?", tag))
+	}
+
+	var posts []model.Post
+	if err := tx.Preload("User").Preload("Tags").Preload("CoverMedia").
+		Order("posts.created_at DESC, posts.id DESC").Limit(limit).Find(&posts).Error; err != nil {
+		logger.Error("PostRepository.ListRecentPublished db.Find is error", zap.Error(err))
+		return nil, err
+	}
+	return posts, nil
+}
+
+// FindImported 查找之前导入的文章：优先按来源匹配，其次按作者的 slug 匹配；未找到时返回 gorm.ErrRecordNotFound
+func (pr *PostRepository) FindImported(ctx context.Context, authorID uint, sourcePath string, slug string) (*model.Post, error) {
+	var post model.Post
+	err := pr.db.WithContext(ctx).Model(&model.Post{}).Preload("Tags").
+		Where("source_path = ? AND source_path <> ''", sourcePath).First(&post).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) && slug != "" {
+		err = pr.db.WithContext(ctx).Model(&model.Post{}).Preload("Tags").
+			Where("user_id = ? AND slug = ?", authorID, slug).First(&post).Error
+	}
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error("PostRepository.FindImported db.First is error", zap.Error(err))
+		}
+		return nil, err
+	}
+	return &post, nil
+}
+
+// ListArchive 按创建时间倒序查询全部已发布文章（只取 ID、标题、创建和更新时间，归档页和静态导出使用）
+func (pr *PostRepository) ListArchive(ctx context.Context) ([]model.Post, error) {
+	var posts []model.Post
+	if err := pr.db.WithContext(ctx).Model(&model.Post{}).Select("posts.id", "posts.title", "posts.created_at", "posts.updated_at").
+		Where("posts.status = ?", model.PostStatusPublished).
+		Order("posts.created_at DESC, posts.id DESC").Find(&posts).Error; err != nil {
+		logger.Error("PostRepository.ListArchive db.Find is error", zap.Error(err))
+		return nil, err
+	}
+	return posts, nil
+}
+
+// sitemapPosts 可被搜索引擎收录的文章：已发布且未设置 noindex
+func (pr *PostRepository) sitemapPosts(ctx context.Context) *gorm.DB {
+	return pr.db.WithContext(ctx).Model(&model.Post{}).Where("posts.status = ? AND posts.no_index = ?", model.PostStatusPublished, false)
+}
+
+// CountSitemapPosts 统计可被收录的文章数
+func (pr *PostRepository) CountSitemapPosts(ctx context.Context) (int64, error) {
+	var total int64
+	if err := pr.sitemapPosts(ctx).Count(&total).Error; err != nil {
+		logger.Error("PostRepository.CountSitemapPosts db.Count is error", zap.Error(err))
+		return 0, err
+	}
+	return total, nil
+}
+
+// ListSitemapPosts 按 ID 顺序分段查询可被收录的文章，只取 ID 和更新时间
+func (pr *PostRepository) ListSitemapPosts(ctx context.Context, offset int, limit int) ([]model.Post, error) {
+	var posts []model.Post
+	if err := pr.sitemapPosts(ctx).Select("posts.id", "posts.updated_at").
+		Order("posts.id ASC").Offset(offset).Limit(limit).Find(&posts).Error; err != nil {
+		logger.Error("PostRepository.ListSitemapPosts db.Find is error", zap.Error(err))
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListTagLastMods 有已发布文章的标签及其文章的最近更新时间
+func (pr *PostRepository) ListTagLastMods(ctx context.Context) ([]DTO.LastModDTO, error) {
+	var rows []DTO.LastModDTO
+	err := pr.sitemapPosts(ctx).
+		Select("tags.name AS name, MAX(posts.updated_at) AS last_mod").
+		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Group("tags.name").Order("tags.name ASC").Scan(&rows).Error
+	if err != nil {
+		logger.Error("PostRepository.ListTagLastMods is error", zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListAuthorLastMods 有已发布文章的作者及其文章的最近更新时间
+func (pr *PostRepository) ListAuthorLastMods(ctx context.Context) ([]DTO.LastModDTO, error) {
+	var rows []DTO.LastModDTO
+	err := pr.sitemapPosts(ctx).
+		Select("users.username AS name, MAX(posts.updated_at) AS last_mod").
+		Joins("JOIN users ON users.id = posts.user_id AND users.deleted_at IS NULL").
+		Group("users.username").Order("users.username ASC").Scan(&rows).Error
+	if err != nil {
+		logger.Error("PostRepository.ListAuthorLastMods is error", zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListByUser 查询用户的全部文章（含草稿），并预加载标签，个人数据导出使用
+func (pr *PostRepository) ListByUser(ctx context.Context, userID uint) ([]model.Post, error) {
+	var posts []model.Post
+	if err := pr.db.WithContext(ctx).Model(&model.Post{}).Preload("Tags").Where("user_id = ?", userID).Order("id ASC").Find(&posts).Error; err != nil {
+		logger.Error("PostRepository.ListByUser db.Find is error", zap.Error(err))
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetDetailById 根据ID获取文章信息，并预加载标签
+func (pr *PostRepository) GetDetailById(ctx context.Context, id uint) (*model.Post, error) {
+	var post model.Post
+	if err := pr.db.WithContext(ctx).Model(&model.Post{}).Preload("Tags").Preload("CoverMedia").Where("id = ?", id).First(&post).Error; err != nil {
+		logger.Error("PostRepository.GetDetailById db.First is error", zap.Error(err))
+		return nil, err
+	}
+	return &post, nil
+}
+
+// UpdateRendered 刷新文章的 HTML 缓存及摘要、字数、目录等派生字段（使用 UpdateColumns，不刷新 updated_at）
+func (pr *PostRepository) UpdateRendered(ctx context.Context, post *model.Post) error {
+	tx := pr.db.WithContext(ctx).Model(&model.Post{}).Where("id = ?", post.ID).UpdateColumns(map[string]interface{}{
+		"content_html":    post.ContentHTML,
+		"render_version":  post.RenderVersion,
+		"excerpt":         post.Excerpt,
+		"word_count":      post.WordCount,
+		"reading_minutes": post.ReadingMinutes,
+		"toc":             post.TOC,
+	})
+	if tx.Error != nil {
+		logger.Error("PostRepository.UpdateRendered db.UpdateColumns is error", zap.Error(tx.Error))
+		return tx.Error
+	}
+	return nil
+}
+
+// IncrViewCount 文章浏览数加一（使用 UpdateColumn，不刷新 updated_at）
+func (pr *PostRepository) IncrViewCount(ctx context.Context, id uint) error {
+	tx := pr.db.WithContext(ctx).Model(&model.Post{}).Where("id = ?", id).UpdateColumn("view_count", gorm.Expr("view_count + ?", 1))
+	if tx.Error != nil {
+		logger.Error("PostRepository.IncrViewCount db.UpdateColumn is error", zap.Error(tx.Error))
+		return tx.Error
+	}
+	return nil
+}
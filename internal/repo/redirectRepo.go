@@ -0,0 +1,42 @@
+package repo
+
+import (
+	"context"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RedirectRepository struct {
+	db *gorm.DB
+}
+
+func NewRedirectRepository(db *gorm.DB) *RedirectRepository {
+	return &RedirectRepository{db: db}
+}
+
+// Save 保存重定向，旧地址已存在时改为指向新的文章
+func (rr *RedirectRepository) Save(ctx context.Context, fromPath string, postID uint) error {
+	redirect := model.Redirect{FromPath: fromPath, PostID: postID}
+	err := rr.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "from_path"}},
+		DoUpdates: clause.AssignmentColumns([]string{"post_id", "updated_at"}),
+	}).Create(&redirect).Error
+	if err != nil {
+		logger.Error("RedirectRepository.Save is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// FindByPaths 按候选旧地址查找重定向，返回第一个匹配的；都不匹配时返回 gorm.ErrRecordNotFound
+func (rr *RedirectRepository) FindByPaths(ctx context.Context, paths []string) (*model.Redirect, error) {
+	var redirect model.Redirect
+	if err := rr.db.WithContext(ctx).Model(&model.Redirect{}).Where("from_path IN ?", paths).First(&redirect).Error; err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
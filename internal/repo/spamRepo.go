@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"context"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/spam"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SpamDocCountToken 保留词：该行的 spam_count/ham_count 记录训练过的评论总数
+const SpamDocCountToken = "__doc_count__"
+
+type SpamRepository struct {
+	db *gorm.DB
+}
+
+func NewSpamRepository(db *gorm.DB) *SpamRepository {
+	return &SpamRepository{db: db}
+}
+
+// GetTokenCounts 查询词频统计，同时返回训练过的垃圾/正常评论总数
+func (sr *SpamRepository) GetTokenCounts(ctx context.Context, tokens []string) (map[string]spam.TokenCount, int64, int64, error) {
+	keys := make([]string, 0, len(tokens)+1)
+	keys = append(keys, tokens...)
+	keys = append(keys, SpamDocCountToken)
+
+	var rows []model.SpamToken
+	if err := sr.db.WithContext(ctx).Model(&model.SpamToken{}).Where("token IN ?", keys).Find(&rows).Error; err != nil {
+		logger.Error("SpamRepository.GetTokenCounts db.Find is error", zap.Error(err))
+		return nil, 0, 0, err
+	}
+
+	counts := make(map[string]spam.TokenCount, len(rows))
+	var spamDocs, hamDocs int64
+	for _, row := range rows {
+		if row.Token == SpamDocCountToken {
+			spamDocs, hamDocs = row.SpamCount, row.HamCount
+			continue
+		}
+		counts[row.Token] = spam.TokenCount{Spam: row.SpamCount, Ham: row.HamCount}
+	}
+	return counts, spamDocs, hamDocs, nil
+}
+
+// Train 用一条评论的词训练分类器：词频和评论总数各加一
+func (sr *SpamRepository) Train(ctx context.Context, tokens []string, isSpam bool) error {
+	return trainSpamTokens(sr.db.WithContext(ctx), tokens, isSpam)
+}
+
+// Retrain 评论改判时在同一事务中撤销按原标签的训练并按新标签重新训练
+func (sr *SpamRepository) Retrain(ctx context.Context, tokens []string, wasSpam bool, isSpam bool) error {
+	return sr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := untrainSpamTokens(tx, tokens, wasSpam); err != nil {
+			return err
+		}
+		return trainSpamTokens(tx, tokens, isSpam)
+	})
+}
+
+func trainSpamTokens(db *gorm.DB, tokens []string, isSpam bool) error {
+	column := "ham_count"
+	if isSpam {
+		column = "spam_count"
+	}
+
+	keys := make([]string, 0, len(tokens)+1)
+	keys = append(keys, tokens...)
+	keys = append(keys, SpamDocCountToken)
+
+	rows := make([]model.SpamToken, 0, len(keys))
+	for _, token := range keys {
+		row := model.SpamToken{Token: token}
+		if isSpam {
+			row.SpamCount = 1
+		} else {
+			row.HamCount = 1
+		}
+		rows = append(rows, row)
+	}
+
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{column: gorm.Expr(column + " + 1")}),
+	}).CreateInBatches(&rows, 200).Error
+	if err != nil {
+		logger.Error("SpamRepository.trainSpamTokens db.Create is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// untrainSpamTokens 撤销一条评论的训练：词频和评论总数各减一，不会减到负数
+func untrainSpamTokens(db *gorm.DB, tokens []string, isSpam bool) error {
+	column := "ham_count"
+	if isSpam {
+		column = "spam_count"
+	}
+
+	keys := make([]string, 0, len(tokens)+1)
+	keys = append(keys, tokens...)
+	keys = append(keys, SpamDocCountToken)
+
+	err := db.Model(&model.SpamToken{}).Where("token IN ?", keys).
+		UpdateColumn(column, gorm.Expr("CASE WHEN "+column+" > 0 THEN "+column+" - 1 ELSE 0 END")).Error
+	if err != nil {
+		logger.Error("SpamRepository.untrainSpamTokens db.UpdateColumn is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
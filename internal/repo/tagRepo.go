@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"context"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// FindOrCreateByNames 根据标签名批量查询标签，不存在的标签会被自动创建
+// 参数:
+//   - names: 标签名列表（调用方负责去重和去空）
+//
+// 返回值:
+//   - []model.Tag: 与 names 对应的标签列表
+//   - error: 查询或创建失败时返回错误
+func (tr *TagRepository) FindOrCreateByNames(ctx context.Context, names []string) ([]model.Tag, error) {
+	if len(names) == 0 {
+		return []model.Tag{}, nil
+	}
+
+	tags := make([]model.Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, model.Tag{Name: name})
+	}
+	// 已存在的标签名忽略冲突，避免并发创建同名标签报错
+	if err := tr.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		logger.Error("TagRepository.FindOrCreateByNames db.Create is error", zap.Error(err))
+		return nil, err
+	}
+
+	var result []model.Tag
+	if err := tr.db.WithContext(ctx).Model(&model.Tag{}).Where("name IN ?", names).Find(&result).Error; err != nil {
+		logger.Error("TagRepository.FindOrCreateByNames db.Find is error", zap.Error(err))
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReplacePostTags 用给定标签整体替换文章的标签
+func (tr *TagRepository) ReplacePostTags(ctx context.Context, postID uint, tags []model.Tag) error {
+	post := model.Post{ID: postID}
+	if err := tr.db.WithContext(ctx).Model(&post).Association("Tags").Replace(tags); err != nil {
+		logger.Error("TagRepository.ReplacePostTags Association.Replace is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
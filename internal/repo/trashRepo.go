@@ -0,0 +1,227 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TrashRepository 回收站：查询、恢复和永久删除软删除的文章与评论
+type TrashRepository struct {
+	db *gorm.DB
+}
+
+func NewTrashRepository(db *gorm.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// ListPosts 回收站中的文章，按删除时间倒序；userID 为 0 时查询全部（管理员）
+func (tr *TrashRepository) ListPosts(ctx context.Context, userID uint, pageNum int, pageSize int) ([]model.Post, int64, error) {
+	tx := tr.db.WithContext(ctx).Unscoped().Model(&model.Post{}).Where("deleted_at IS NOT NULL")
+	if userID != 0 {
+		tx = tx.Where("user_id = ?", userID)
+	}
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		logger.Error("TrashRepository.ListPosts db.Count is error", zap.Error(err))
+		return nil, 0, err
+	}
+	var posts []model.Post
+	if err := tx.Select("id", "title", "user_id", "status", "deleted_at").
+		Order("deleted_at DESC, id DESC").Offset((pageNum - 1) * pageSize).Limit(pageSize).Find(&posts).Error; err != nil {
+		logger.Error("TrashRepository.ListPosts db.Find is error", zap.Error(err))
+		return nil, 0, err
+	}
+	return posts, total, nil
+}
+
+// CountCommentsDeletedWith 统计各文章随文章一起删除的评论数（删除时间与文章相同）
+func (tr *TrashRepository) CountCommentsDeletedWith(ctx context.Context, postIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(postIDs))
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		PostID uint
+		Count  int64
+	}
+	if err := tr.db.WithContext(ctx).Table("comments").Select("comments.post_id, COUNT(*) AS count").
+		Joins("JOIN posts ON posts.id = comments.post_id AND comments.deleted_at = posts.deleted_at").
+		Where("comments.post_id IN ?", postIDs).Group("comments.post_id").Scan(&rows).Error; err != nil {
+		logger.Error("TrashRepository.CountCommentsDeletedWith is error", zap.Error(err))
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.PostID] = row.Count
+	}
+	return counts, nil
+}
+
+// ListComments 回收站中单独删除的评论（所属文章未删除，不含已删除占位），按删除时间倒序，预加载文章标题；userID 为 0 时查询全部
+func (tr *TrashRepository) ListComments(ctx context.Context, userID uint, pageNum int, pageSize int) ([]model.Comment, int64, error) {
+	tx := tr.db.WithContext(ctx).Unscoped().Model(&model.Comment{}).
+		Joins("JOIN posts ON posts.id = comments.post_id AND posts.deleted_at IS NULL").
+		Where("comments.deleted_at IS NOT NULL AND comments.is_deleted = ?", false)
+	if userID != 0 {
+		tx = tx.Where("comments.user_id = ?", userID)
+	}
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		logger.Error("TrashRepository.ListComments db.Count is error", zap.Error(err))
+		return nil, 0, err
+	}
+	var comments []model.Comment
+	if err := tx.Select("comments.*").Preload("Post", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "title")
+	}).Order("comments.deleted_at DESC, comments.id DESC").Offset((pageNum - 1) * pageSize).Limit(pageSize).Find(&comments).Error; err != nil {
+		logger.Error("TrashRepository.ListComments db.Find is error", zap.Error(err))
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// GetPost 查询回收站中的文章；文章不存在或未删除时返回 gorm.ErrRecordNotFound
+func (tr *TrashRepository) GetPost(ctx context.Context, id uint) (*model.Post, error) {
+	var post model.Post
+	if err := tr.db.WithContext(ctx).Unscoped().Model(&model.Post{}).Where("id = ? AND deleted_at IS NOT NULL", id).First(&post).Error; err != nil {
+		logger.Error("TrashRepository.GetPost is error", zap.Error(err))
+		return nil, err
+	}
+	return &post, nil
+}
+
+// GetComment 查询回收站中的评论（含所属文章的删除状态）；评论不存在或未删除时返回 gorm.ErrRecordNotFound
+func (tr *TrashRepository) GetComment(ctx context.Context, id uint) (*model.Comment, error) {
+	var comment model.Comment
+	if err := tr.db.WithContext(ctx).Unscoped().Model(&model.Comment{}).Preload("Post", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Select("id", "title", "deleted_at")
+	}).Where("id = ? AND deleted_at IS NOT NULL", id).First(&comment).Error; err != nil {
+		logger.Error("TrashRepository.GetComment is error", zap.Error(err))
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// RestorePost 恢复文章及随文章一起删除的评论（删除时间与文章相同），之前单独删除的评论不恢复
+func (tr *TrashRepository) RestorePost(ctx context.Context, post *model.Post) (int64, error) {
+	var restored int64
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&model.Comment{}).
+			Where("post_id = ? AND deleted_at = ?", post.ID, post.DeletedAt.Time).UpdateColumn("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		restored = result.RowsAffected
+		return tx.Unscoped().Model(&model.Post{}).Where("id = ?", post.ID).UpdateColumn("deleted_at", nil).Error
+	})
+	if err != nil {
+		logger.Error("TrashRepository.RestorePost is error", zap.Error(err))
+		return 0, err
+	}
+	return restored, nil
+}
+
+// RestoreComment 恢复评论并恢复父评论的回复数；父评论是随最后一条回复一起清理的已删除占位时，逐级向上一并恢复
+func (tr *TrashRepository) RestoreComment(ctx context.Context, comment *model.Comment) error {
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current := comment
+		for {
+			if err := tx.Unscoped().Model(&model.Comment{}).Where("id = ?", current.ID).UpdateColumn("deleted_at", nil).Error; err != nil {
+				return err
+			}
+			if current.ParentID == nil {
+				return nil
+			}
+			if current.Status == model.CommentStatusApproved {
+				if err := tx.Unscoped().Model(&model.Comment{}).Where("id = ?", *current.ParentID).
+					UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error; err != nil {
+					return err
+				}
+			}
+			var parent model.Comment
+			err := tx.Unscoped().Model(&model.Comment{}).Where("id = ? AND deleted_at IS NOT NULL AND is_deleted = ?", *current.ParentID, true).First(&parent).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			current = &parent
+		}
+	})
+	if err != nil {
+		logger.Error("TrashRepository.RestoreComment is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// PurgePost 永久删除文章及其全部评论、评论历史和关联数据
+func (tr *TrashRepository) PurgePost(ctx context.Context, id uint) error {
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		comments := tx.Unscoped().Model(&model.Comment{}).Select("id").Where("post_id = ?", id)
+		if err := tx.Where("comment_id IN (?)", comments).Delete(&model.CommentRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("post_id = ?", id).Delete(&model.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM post_tags WHERE post_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM post_media WHERE post_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ?", id).Delete(&model.Redirect{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&model.Post{}).Error
+	})
+	if err != nil {
+		logger.Error("TrashRepository.PurgePost is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// PurgeComment 永久删除评论及其历史版本
+func (tr *TrashRepository) PurgeComment(ctx context.Context, id uint) error {
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("comment_id = ?", id).Delete(&model.CommentRevision{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&model.Comment{}).Error
+	})
+	if err != nil {
+		logger.Error("TrashRepository.PurgeComment is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListExpiredPostIDs 删除时间早于 before 的文章ID
+func (tr *TrashRepository) ListExpiredPostIDs(ctx context.Context, before time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	if err := tr.db.WithContext(ctx).Unscoped().Model(&model.Post{}).Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("id ASC").Limit(limit).Pluck("id", &ids).Error; err != nil {
+		logger.Error("TrashRepository.ListExpiredPostIDs is error", zap.Error(err))
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListExpiredCommentIDs 删除时间早于 before 的评论ID
+func (tr *TrashRepository) ListExpiredCommentIDs(ctx context.Context, before time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	if err := tr.db.WithContext(ctx).Unscoped().Model(&model.Comment{}).Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("id ASC").Limit(limit).Pluck("id", &ids).Error; err != nil {
+		logger.Error("TrashRepository.ListExpiredCommentIDs is error", zap.Error(err))
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"go-my-blog/pkg/logger"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 死锁（1213）与锁等待超时（1205）时 MySQL 已回滚事务（或语句），整体重试即可
+const (
+	mysqlErrDeadlock        = 1213
+	mysqlErrLockWaitTimeout = 1205
+
+	unitOfWorkMaxRetries = 3
+	unitOfWorkRetryDelay = 50 * time.Millisecond
+)
+
+// Repositories 绑定到同一事务的仓储集合，只在 UnitOfWork.Do 的回调内有效
+type Repositories struct {
+	Users       *UserRepository
+	Posts       *PostRepository
+	Comments    *CommentRepository
+	Tags        *TagRepository
+	Media       *MediaRepository
+	Redirects   *RedirectRepository
+	Spam        *SpamRepository
+	DataExports *DataExportRepository
+	Audit       *AuditRepository
+	Trash       *TrashRepository
+	Backup      *BackupRepository
+}
+
+func newRepositories(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Users:       NewUserRepository(db),
+		Posts:       NewPostRepository(db),
+		Comments:    NewCommentRepository(db),
+		Tags:        NewTagRepository(db),
+		Media:       NewMediaRepository(db),
+		Redirects:   NewRedirectRepository(db),
+		Spam:        NewSpamRepository(db),
+		DataExports: NewDataExportRepository(db),
+		Audit:       NewAuditRepository(db),
+		Trash:       NewTrashRepository(db),
+		Backup:      NewBackupRepository(db),
+	}
+}
+
+// UnitOfWork 在一个事务中执行跨多个仓储的操作
+type UnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+type txContextKey struct{}
+
+// Do 在事务中执行 fn，fn 返回错误（或 panic）时回滚，否则提交。
+// 当前事务通过 ctx 向下传递：fn 内以回调收到的 ctx 再次调用 Do 时，嵌套操作在同一事务的保存点中执行，
+// 嵌套失败只回滚到保存点，由外层决定是否继续。
+// 最外层事务遇到死锁或锁等待超时时整体重试，fn 可能被执行多次，不应依赖上一次执行留下的内存状态
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repos *Repositories) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	run := func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx), newRepositories(tx))
+	}
+	if tx, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return tx.Transaction(run)
+	}
+
+	return retryTransaction(ctx, func() error {
+		return u.db.WithContext(ctx).Transaction(run)
+	})
+}
+
+// retryTransaction 执行最外层事务，遇到死锁或锁等待超时时整体重试，最多重试 unitOfWorkMaxRetries 次
+func retryTransaction(ctx context.Context, transaction func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := transaction()
+		if err == nil || !isRetryableTxError(err) || attempt > unitOfWorkMaxRetries {
+			return err
+		}
+		logger.Warn("UnitOfWork.Do 事务冲突，准备重试", zap.Int("attempt", attempt), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * unitOfWorkRetryDelay):
+		}
+	}
+}
+
+func isRetryableTxError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == mysqlErrDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+}
@@ -0,0 +1,117 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-my-blog/config/priority_config"
+	"go-my-blog/pkg/logger"
+	"os"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestMain(m *testing.M) {
+	// 日志输出到控制台且只记录错误，避免测试中写日志文件
+	priority_config.PriorityConf.Gin.Debug = true
+	priority_config.PriorityConf.Log.Level = "error"
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+var (
+	errDeadlock        = &mysql.MySQLError{Number: mysqlErrDeadlock, Message: "Deadlock found when trying to get lock"}
+	errLockWaitTimeout = &mysql.MySQLError{Number: mysqlErrLockWaitTimeout, Message: "Lock wait timeout exceeded"}
+)
+
+func TestIsRetryableTxError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"死锁", errDeadlock, true},
+		{"锁等待超时", errLockWaitTimeout, true},
+		{"包装后的死锁", fmt.Errorf("保存失败：%w", errDeadlock), true},
+		{"唯一键冲突", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, false},
+		{"非 MySQL 错误", errors.New("记录不存在"), false},
+		{"上下文取消", context.Canceled, false},
+		{"无错误", nil, false},
+	}
+	for _, tt := range tests {
+		if got := isRetryableTxError(tt.err); got != tt.want {
+			t.Errorf("%s: isRetryableTxError(%v) = %t, want %t", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetryTransaction(t *testing.T) {
+	// 前两次事务冲突，第三次提交成功
+	calls := 0
+	err := retryTransaction(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return errDeadlock
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("err = %v, calls = %d, want nil, 3", err, calls)
+	}
+
+	// 一直冲突时重试 unitOfWorkMaxRetries 次后返回最后一次的错误
+	calls = 0
+	err = retryTransaction(context.Background(), func() error {
+		calls++
+		return errLockWaitTimeout
+	})
+	if !errors.Is(err, errLockWaitTimeout) || calls != unitOfWorkMaxRetries+1 {
+		t.Errorf("err = %v, calls = %d, want 锁等待超时, %d", err, calls, unitOfWorkMaxRetries+1)
+	}
+
+	// 其他错误不重试
+	calls = 0
+	errFailed := errors.New("写入失败")
+	err = retryTransaction(context.Background(), func() error {
+		calls++
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) || calls != 1 {
+		t.Errorf("err = %v, calls = %d, want 写入失败, 1", err, calls)
+	}
+}
+
+func TestRetryTransactionCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := retryTransaction(ctx, func() error {
+		calls++
+		cancel()
+		return errDeadlock
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Errorf("err = %v, calls = %d, want context.Canceled, 1", err, calls)
+	}
+}
+
+func TestRetryTransactionStartedGuard(t *testing.T) {
+	// 只能执行一次的操作（如读取归档流）用 started 标记拒绝重试，重试返回的错误不可重试，不会无限循环
+	errRetried := errors.New("事务冲突，请重新执行")
+	started := false
+	calls := 0
+	fn := func(ctx context.Context, repos *Repositories) error {
+		if started {
+			return errRetried
+		}
+		started = true
+		return errDeadlock
+	}
+	err := retryTransaction(context.Background(), func() error {
+		calls++
+		return fn(context.Background(), newRepositories(nil))
+	})
+	if !errors.Is(err, errRetried) || calls != 2 {
+		t.Errorf("err = %v, calls = %d, want 事务冲突, 2", err, calls)
+	}
+}
@@ -1,10 +1,12 @@
 package repo
 
 import (
+	"context"
 	"errors"
 	"go-my-blog/internal/model"
 	"go-my-blog/pkg/logger"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -25,9 +27,9 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 // 返回值:
 //   - *model.User: 注册成功的用户信息
 //   - error: 错误信息，如果注册失败则返回相应的错误
-func (ur *UserRepository) UserRegister(user *model.User) (*model.User, error) {
+func (ur *UserRepository) UserRegister(ctx context.Context, user *model.User) (*model.User, error) {
 	// 获取数据库连接
-	db := ur.db
+	db := ur.db.WithContext(ctx)
 	// 尝试在数据库中创建新用户记录
 	tx := db.Create(user)
 	// 检查是否有错误发生
@@ -41,10 +43,10 @@ func (ur *UserRepository) UserRegister(user *model.User) (*model.User, error) {
 	return user, nil
 }
 
-func (ur *UserRepository) FindByUserName(username string) (*model.User, error) {
+func (ur *UserRepository) FindByUserName(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
 	// 获取数据库连接
-	db := ur.db
+	db := ur.db.WithContext(ctx)
 	tx := db.Model(&model.User{}).Where("username = ?", username).First(&user)
 	if tx.Error != nil {
 		// 用户不存在
@@ -57,9 +59,9 @@ func (ur *UserRepository) FindByUserName(username string) (*model.User, error) {
 	return &user, nil
 }
 
-func (ur *UserRepository) FindById(id uint) (*model.User, error) {
+func (ur *UserRepository) FindById(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	tx := ur.db.Model(&model.User{}).Where("id = ?", id).First(&user)
+	tx := ur.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).First(&user)
 	if tx.Error != nil {
 		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
 			logger.Warn("UserRepository.FindById user not found:"+strconv.FormatUint(uint64(id), 10), zap.Error(tx.Error))
@@ -72,3 +74,145 @@ func (ur *UserRepository) FindById(id uint) (*model.User, error) {
 	return &user, nil
 
 }
+
+// GetLocale 用户的语言偏好，未设置时为空
+func (ur *UserRepository) GetLocale(ctx context.Context, id uint) (string, error) {
+	var locale string
+	tx := ur.db.WithContext(ctx).Model(&model.User{}).Select("locale").Where("id = ?", id).Scan(&locale)
+	if tx.Error != nil {
+		logger.Error("UserRepository.GetLocale is error", zap.Error(tx.Error))
+		return "", tx.Error
+	}
+	return locale, nil
+}
+
+// UpdateLocale 设置用户的语言偏好，为空表示清除
+func (ur *UserRepository) UpdateLocale(ctx context.Context, id uint, locale string) error {
+	tx := ur.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).UpdateColumn("locale", locale)
+	if tx.Error != nil {
+		logger.Error("UserRepository.UpdateLocale is error", zap.Error(tx.Error))
+		return tx.Error
+	}
+	return nil
+}
+
+// ScheduleDeletion 设置（或撤销，at 为空时）账号的计划删除时间和评论处理方式
+func (ur *UserRepository) ScheduleDeletion(ctx context.Context, id uint, at *time.Time, commentMode string) error {
+	tx := ur.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deletion_scheduled_at": at,
+		"deletion_comment_mode": commentMode,
+	})
+	if tx.Error != nil {
+		logger.Error("UserRepository.ScheduleDeletion is error", zap.Error(tx.Error))
+		return tx.Error
+	}
+	return nil
+}
+
+// ListDueDeletions 冷静期已过、需要删除的账号
+func (ur *UserRepository) ListDueDeletions(ctx context.Context, now time.Time) ([]model.User, error) {
+	var users []model.User
+	if err := ur.db.WithContext(ctx).Unscoped().Model(&model.User{}).Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now).Find(&users).Error; err != nil {
+		logger.Error("UserRepository.ListDueDeletions is error", zap.Error(err))
+		return nil, err
+	}
+	return users, nil
+}
+
+// Purge 在一个事务中永久删除用户及其数据，与 User 模型上的 OnDelete:CASCADE 保持一致：
+// 本人的文章（连同文章下所有评论）和本人的评论随用户一起物理删除。这里按依赖顺序显式删除，
+// 不依赖数据库中外键是否已建立；没有外键约束的关联（中间表、重定向、评论历史、媒体、导出任务）也一并清理。
+// 本人在他人文章下的评论按 commentMode 处理：anonymize 解除作者关联后保留，
+// delete 删除（有回复的评论保留已删除占位，与删除单条评论一致）。
+// 返回被删除的媒体和导出任务，文件由调用方在事务提交后删除
+func (ur *UserRepository) Purge(ctx context.Context, userID uint, commentMode string, anonymousName string) ([]model.Media, []model.DataExport, error) {
+	var media []model.Media
+	var exports []model.DataExport
+	err := ur.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ownPosts := func() *gorm.DB {
+			return tx.Unscoped().Model(&model.Post{}).Select("id").Where("user_id = ?", userID)
+		}
+		otherComments := func() *gorm.DB {
+			return tx.Unscoped().Model(&model.Comment{}).Where("user_id = ? AND post_id NOT IN (?)", userID, ownPosts())
+		}
+
+		// 评论历史：本人文章下的评论一律删除；本人的评论只在删除模式下删除
+		revisionComments := tx.Unscoped().Model(&model.Comment{}).Select("id").Where("post_id IN (?)", ownPosts())
+		if commentMode == model.CommentModeDelete {
+			revisionComments = tx.Unscoped().Model(&model.Comment{}).Select("id").Where("post_id IN (?) OR user_id = ?", ownPosts(), userID)
+		}
+		if err := tx.Where("comment_id IN (?)", revisionComments).Delete(&model.CommentRevision{}).Error; err != nil {
+			return err
+		}
+
+		detached := map[string]interface{}{"user_id": nil, "ip": "", "guest_email": "", "guest_website": ""}
+		if commentMode == model.CommentModeDelete {
+			// 有回复的评论保留占位；其余评论随用户删除，先扣减父评论的回复数
+			placeholder := map[string]interface{}{"is_deleted": true, "content": "", "content_html": "", "guest_name": ""}
+			for key, value := range detached {
+				placeholder[key] = value
+			}
+			if err := otherComments().Where("reply_count > 0").UpdateColumns(placeholder).Error; err != nil {
+				return err
+			}
+			var parentIDs []uint
+			if err := otherComments().Where("parent_id IS NOT NULL AND status = ? AND deleted_at IS NULL", model.CommentStatusApproved).
+				Pluck("parent_id", &parentIDs).Error; err != nil {
+				return err
+			}
+			for _, parentID := range parentIDs {
+				if err := tx.Model(&model.Comment{}).Where("id = ?", parentID).
+					UpdateColumn("reply_count", gorm.Expr("CASE WHEN reply_count > 0 THEN reply_count - 1 ELSE 0 END")).Error; err != nil {
+					return err
+				}
+			}
+		} else {
+			detached["guest_name"] = anonymousName
+			if err := otherComments().UpdateColumns(detached).Error; err != nil {
+				return err
+			}
+		}
+
+		// 媒体：先解除引用再删除记录，他人文章的封面置空
+		if err := tx.Model(&model.Media{}).Where("user_id = ?", userID).Find(&media).Error; err != nil {
+			return err
+		}
+		mediaIDs := tx.Model(&model.Media{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Exec("DELETE FROM post_media WHERE post_id IN (?) OR media_id IN (?)", ownPosts(), mediaIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&model.Post{}).Where("cover_media_id IN (?)", mediaIDs).UpdateColumn("cover_media_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&model.Media{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM post_tags WHERE post_id IN (?)", ownPosts()).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id IN (?)", ownPosts()).Delete(&model.Redirect{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.DataExport{}).Where("user_id = ?", userID).Find(&exports).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&model.DataExport{}).Error; err != nil {
+			return err
+		}
+
+		// 级联部分：文章下的评论、本人的评论、本人的文章，最后删除用户
+		if err := tx.Unscoped().Where("post_id IN (?) OR user_id = ?", ownPosts(), userID).Delete(&model.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.Post{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&model.User{}, userID).Error
+	})
+	if err != nil {
+		logger.Error("UserRepository.Purge is error", zap.Uint("user_id", userID), zap.Error(err))
+		return nil, nil, err
+	}
+	return media, exports, nil
+}
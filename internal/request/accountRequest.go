@@ -0,0 +1,12 @@
+package request
+
+// DeleteAccountRequest 申请注销账号参数
+type DeleteAccountRequest struct {
+	Password string `json:"password"` // 当前密码，用于确认是本人操作
+	Comments string `json:"comments"` // 评论处理方式：anonymize（默认）/ delete
+}
+
+// UpdateLocaleRequest 设置语言偏好参数
+type UpdateLocaleRequest struct {
+	Locale string `json:"locale"` // zh-CN / en-US，传空字符串清除偏好（按 Accept-Language 协商）
+}
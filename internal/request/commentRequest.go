@@ -1,6 +1,51 @@
 package request
 
 type CreateCommentRequest struct {
-	Content string `json:"content" validate:"required"`
-	PostID  uint   `json:"postId" validate:"required"`
+	Content       string `json:"content" validate:"required,max=2000"`
+	ContentFormat string `json:"contentFormat" validate:"omitempty,oneof=markdown plain html"` // 内容格式，默认 plain
+	PostID        uint   `json:"postId" validate:"required"`
+	ParentID      uint   `json:"parentId"` // 回复的父评论ID，不传或为 0 表示发表根评论
+}
+
+type UpdateCommentRequest struct {
+	Content       string `json:"content" validate:"required,max=2000"`
+	ContentFormat string `json:"contentFormat" validate:"omitempty,oneof=markdown plain html"` // 不传保持不变
+}
+
+// ModerationQueueRequest 审核队列查询参数
+type ModerationQueueRequest struct {
+	Status   string `form:"status" validate:"omitempty,oneof=pending rejected spam"`
+	PageNum  int    `form:"pageNum"`
+	PageSize int    `form:"pageSize"`
+}
+
+// 初始化时设置默认值
+func (r *ModerationQueueRequest) SetDefault() {
+	if r.Status == "" {
+		r.Status = "pending" // 默认查看待审核评论
+	}
+	if r.PageNum <= 0 {
+		r.PageNum = 1
+	}
+	if r.PageSize <= 0 || r.PageSize > 100 {
+		r.PageSize = 20
+	}
+}
+
+// ModerateCommentsRequest 批量审核评论参数
+type ModerateCommentsRequest struct {
+	IDs    []uint `json:"ids" validate:"required,min=1,max=100,dive,required"`
+	Action string `json:"action" validate:"required,oneof=approve reject spam ham"` // spam/ham 会同时训练垃圾评论分类器
+}
+
+// CreateGuestCommentRequest 游客评论参数：需附带工作量证明题目及答案
+type CreateGuestCommentRequest struct {
+	Content       string `json:"content" validate:"required,max=2000"`
+	ContentFormat string `json:"contentFormat" validate:"omitempty,oneof=markdown plain"` // 内容格式，默认 plain
+	ParentID      uint   `json:"parentId"`
+	Name          string `json:"name" validate:"required,max=50"`
+	Email         string `json:"email" validate:"required,email,max=100"`
+	Website       string `json:"website" validate:"omitempty,url,max=200"`
+	Challenge     string `json:"challenge" validate:"required"`
+	Solution      string `json:"solution" validate:"required,max=32"`
 }
@@ -0,0 +1,22 @@
+package request
+
+// ListMediaRequest 媒体列表查询参数
+type ListMediaRequest struct {
+	PageNum  int `form:"pageNum"`
+	PageSize int `form:"pageSize"`
+}
+
+// 初始化时设置默认值
+func (r *ListMediaRequest) SetDefault() {
+	if r.PageNum <= 0 {
+		r.PageNum = 1
+	}
+	if r.PageSize <= 0 || r.PageSize > 100 {
+		r.PageSize = 20
+	}
+}
+
+// DeleteMediaRequest 删除媒体参数
+type DeleteMediaRequest struct {
+	Force bool `form:"force"` // 媒体仍被文章引用时，需传 force=true 才会删除
+}
@@ -1,13 +1,45 @@
 package request
 
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// 筛选日期格式，如 2024-06-01
+const DateLayout = "2006-01-02"
+
+// AuthorMe author 参数的快捷值，表示当前登录用户
+const AuthorMe = "me"
+
 type CreatePostRequest struct {
-	Title   string `json:"title" validate:"required"`
-	Content string `json:"content" validate:"required"`
+	Title                string   `json:"title" validate:"required,max=200"`
+	Content              string   `json:"content" validate:"required,max=20000"`
+	ContentFormat        string   `json:"contentFormat" validate:"omitempty,oneof=markdown plain html"` // 内容格式，默认 markdown
+	Status               string   `json:"status" validate:"omitempty,oneof=draft published"`
+	Tags                 []string `json:"tags" validate:"max=10,dive,required,max=50"`
+	CommentPolicy        string   `json:"commentPolicy" validate:"omitempty,oneof=inherit open moderated closed"` // 评论策略，不传或 inherit 继承全局配置
+	CommentAutoCloseDays int      `json:"commentAutoCloseDays" validate:"min=0"`                                  // 发布 N 天后自动关闭评论，0 继承全局配置
+	CoverMediaID         *uint    `json:"coverMediaId"`                                                           // 封面图片，需为自己上传的媒体
+	SEOTitle             string   `json:"seoTitle" validate:"max=200"`
+	SEODescription       string   `json:"seoDescription" validate:"max=500"`
+	CanonicalURL         string   `json:"canonicalUrl" validate:"omitempty,url,max=500"`
+	NoIndex              bool     `json:"noIndex"`
 }
 
 type UpdatePostRequest struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title                *string  `json:"title" validate:"omitempty,min=1,max=200"`                     // 不传保持不变，不能改为空
+	Content              *string  `json:"content" validate:"omitempty,min=1,max=20000"`                 // 不传保持不变（不重新渲染，也不改动媒体引用），不能改为空
+	ContentFormat        string   `json:"contentFormat" validate:"omitempty,oneof=markdown plain html"` // 不传保持不变
+	Status               string   `json:"status" validate:"omitempty,oneof=draft published"`
+	Tags                 []string `json:"tags" validate:"max=10,dive,required,max=50"`
+	CommentPolicy        string   `json:"commentPolicy" validate:"omitempty,oneof=inherit open moderated closed"` // 不传保持不变，inherit 恢复继承全局配置
+	CommentAutoCloseDays *int     `json:"commentAutoCloseDays" validate:"omitempty,min=0"`                        // 不传保持不变
+	CoverMediaID         *uint    `json:"coverMediaId"`                                                           // 不传保持不变，传 0 移除封面
+	SEOTitle             *string  `json:"seoTitle" validate:"omitempty,max=200"`                                  // 以下 SEO 字段不传保持不变，传空字符串清空
+	SEODescription       *string  `json:"seoDescription" validate:"omitempty,max=500"`
+	CanonicalURL         *string  `json:"canonicalUrl" validate:"omitempty,clearable_url,max=500"`
+	NoIndex              *bool    `json:"noIndex"`
 }
 
 // form query参数或form表单，get请求
@@ -16,6 +48,13 @@ type PostListRequest struct {
 	PageNum  int    `form:"pageNum"`
 	PageSize int    `form:"pageSize"`
 	Keyword  string `form:"keyword"`
+
+	Author    string `form:"author"`                                                                         // 作者ID，传 me 表示当前登录用户
+	StartDate string `form:"startDate" validate:"omitempty,datetime=2006-01-02"`                             // 创建时间起（含）
+	EndDate   string `form:"endDate" validate:"omitempty,datetime=2006-01-02"`                               // 创建时间止（含）
+	Status    string `form:"status" validate:"omitempty,oneof=draft published"`                              // 文章状态
+	Tag       string `form:"tag" validate:"omitempty,max=50"`                                                // 标签名
+	Sort      string `form:"sort" validate:"omitempty,oneof=newest oldest most_commented most_viewed title"` // 排序方式（白名单）
 }
 
 // 初始化时设置默认值
@@ -26,4 +65,46 @@ func (r *PostListRequest) SetDefault() {
 	if r.PageSize <= 0 || r.PageSize > 100 {
 		r.PageSize = 10 // 没传或超出范围，用默认 10
 	}
+	if r.Sort == "" {
+		r.Sort = "newest" // 没传排序方式，默认按最新发布
+	}
+}
+
+// ResolveAuthorID 解析 author 参数：me 返回当前登录用户ID，空串返回 0（不筛选）
+func (r *PostListRequest) ResolveAuthorID(currentUserID uint) (uint, error) {
+	if r.Author == "" {
+		return 0, nil
+	}
+	if r.Author == AuthorMe {
+		return currentUserID, nil
+	}
+	id, err := strconv.ParseUint(r.Author, 10, 0)
+	if err != nil || id == 0 {
+		return 0, errors.New("author 参数只能是用户ID或 me")
+	}
+	return uint(id), nil
+}
+
+// ParseDateRange 解析起止日期，结束日期包含当天（返回次日零点作为开区间上界）
+func (r *PostListRequest) ParseDateRange() (*time.Time, *time.Time, error) {
+	var start, end *time.Time
+	if r.StartDate != "" {
+		t, err := time.ParseInLocation(DateLayout, r.StartDate, time.Local)
+		if err != nil {
+			return nil, nil, err
+		}
+		start = &t
+	}
+	if r.EndDate != "" {
+		t, err := time.ParseInLocation(DateLayout, r.EndDate, time.Local)
+		if err != nil {
+			return nil, nil, err
+		}
+		t = t.AddDate(0, 0, 1)
+		end = &t
+	}
+	if start != nil && end != nil && !start.Before(*end) {
+		return nil, nil, errors.New("startDate 不能晚于 endDate")
+	}
+	return start, end, nil
 }
@@ -0,0 +1,64 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func bindUpdatePost(t *testing.T, body string) (*UpdatePostRequest, error) {
+	t.Helper()
+	previous := binding.Validator
+	binding.Validator = &Validator{}
+	t.Cleanup(func() { binding.Validator = previous })
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v2/posts/1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	var r UpdatePostRequest
+	err := binding.JSON.Bind(req, &r)
+	return &r, err
+}
+
+func TestUpdatePostRequestPartial(t *testing.T) {
+	r, err := bindUpdatePost(t, `{"status":"draft","commentPolicy":"closed"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 不传标题和正文时为 nil，服务层据此保持原值
+	if r.Title != nil || r.Content != nil {
+		t.Errorf("Title = %v, Content = %v, want nil", r.Title, r.Content)
+	}
+	if r.Status != "draft" || r.CommentPolicy != "closed" {
+		t.Errorf("r = %+v", r)
+	}
+}
+
+func TestUpdatePostRequestRejects(t *testing.T) {
+	for _, body := range []string{
+		`{"title":""}`,   // 标题不能改为空
+		`{"content":""}`, // 正文不能改为空
+		`{"title":"` + strings.Repeat("a", 201) + `"}`,
+		`{"status":"archived"}`,
+		`{"canonicalUrl":"not a url"}`,
+		`{"canonicalUrl":"/relative/path"}`,
+	} {
+		if _, err := bindUpdatePost(t, body); err == nil {
+			t.Errorf("%s 应校验失败", body)
+		}
+	}
+}
+
+func TestUpdatePostRequestCanonicalURL(t *testing.T) {
+	r, err := bindUpdatePost(t, `{"canonicalUrl":"https://example.com/original"}`)
+	if err != nil || r.CanonicalURL == nil || *r.CanonicalURL != "https://example.com/original" {
+		t.Fatalf("有效网址应通过校验: %v", err)
+	}
+	// 传空字符串表示清空
+	r, err = bindUpdatePost(t, `{"canonicalUrl":""}`)
+	if err != nil || r.CanonicalURL == nil || *r.CanonicalURL != "" {
+		t.Fatalf("空字符串应通过校验: %v", err)
+	}
+}
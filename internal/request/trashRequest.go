@@ -0,0 +1,17 @@
+package request
+
+// ListTrashRequest 回收站列表查询参数
+type ListTrashRequest struct {
+	PageNum  int `form:"pageNum"`
+	PageSize int `form:"pageSize"`
+}
+
+// 初始化时设置默认值
+func (r *ListTrashRequest) SetDefault() {
+	if r.PageNum <= 0 {
+		r.PageNum = 1
+	}
+	if r.PageSize <= 0 || r.PageSize > 100 {
+		r.PageSize = 20
+	}
+}
@@ -1,7 +1,7 @@
 package request
 
 type RegisterRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Email    string `json:"email"`
+	Username string `json:"username" validate:"required,username"` // 字母开头，3~20 位字母、数字或下划线
+	Password string `json:"password" validate:"required,password"` // 8~72 位，同时包含字母和数字
+	Email    string `json:"email" validate:"required,email,max=100"`
 }
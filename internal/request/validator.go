@@ -0,0 +1,104 @@
+package request
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// 账号规则
+const (
+	PasswordMinLength = 8
+	PasswordMaxLength = 72 // bcrypt 只使用前 72 个字节
+)
+
+// UsernamePattern 用户名：字母开头，3~20 位字母、数字或下划线
+const UsernamePattern = `^[A-Za-z][A-Za-z0-9_]{2,19}$`
+
+var usernamePattern = regexp.MustCompile(UsernamePattern)
+
+// Validator 请求参数校验器，替换 Gin 默认的校验器后，ShouldBind 系列方法在绑定时按 validate 标签校验。
+// 字段错误中的字段名使用 json（或 form）标签名，与客户端提交的参数名一致
+type Validator struct {
+	once     sync.Once
+	validate *validator.Validate
+}
+
+var _ binding.StructValidator = (*Validator)(nil)
+
+// ValidateStruct 校验结构体（或结构体指针），其他类型不校验
+func (v *Validator) ValidateStruct(obj interface{}) error {
+	if obj == nil {
+		return nil
+	}
+	value := reflect.ValueOf(obj)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	return v.Engine().(*validator.Validate).Struct(obj)
+}
+
+// Engine 底层的 validator 实例
+func (v *Validator) Engine() interface{} {
+	v.once.Do(func() {
+		v.validate = newValidate()
+	})
+	return v.validate
+}
+
+func newValidate() *validator.Validate {
+	validate := validator.New()
+	validate.SetTagName("validate")
+	validate.RegisterTagNameFunc(fieldName)
+	_ = validate.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+		return usernamePattern.MatchString(fl.Field().String())
+	})
+	_ = validate.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return isStrongPassword(fl.Field().String())
+	})
+	// 可清空的网址：更新接口中传空字符串表示清空，其他值必须是有效网址
+	validate.RegisterAlias("clearable_url", "len=0|url")
+	return validate
+}
+
+// fieldName 字段在请求中的名称：优先 json 标签，其次 form 标签
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// isStrongPassword 密码强度：长度 8~72 个字节，同时包含字母和数字
+func isStrongPassword(password string) bool {
+	if len(password) < PasswordMinLength || len(password) > PasswordMaxLength {
+		return false
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	return hasLetter && hasDigit
+}
@@ -0,0 +1,77 @@
+package request
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type accountSample struct {
+	Username string `json:"username" validate:"required,username"`
+	Password string `json:"password" validate:"required,password"`
+}
+
+func TestUsernameRule(t *testing.T) {
+	tests := []struct {
+		username string
+		valid    bool
+	}{
+		{"alice", true},
+		{"bob_2024", true},
+		{"abc", true},
+		{"a" + strings.Repeat("b", 19), true},
+		{"ab", false},                          // 太短
+		{"a" + strings.Repeat("b", 20), false}, // 太长
+		{"2alice", false},                      // 数字开头
+		{"_alice", false},                      // 下划线开头
+		{"alice-bob", false},                   // 不允许 -
+		{"张三丰", false},                         // 只允许 ASCII 字母
+		{"alice ", false},
+	}
+	v := &Validator{}
+	for _, tt := range tests {
+		err := v.ValidateStruct(&accountSample{Username: tt.username, Password: "passw0rd"})
+		if (err == nil) != tt.valid {
+			t.Errorf("username %q: err = %v, want valid = %t", tt.username, err, tt.valid)
+		}
+	}
+}
+
+func TestPasswordRule(t *testing.T) {
+	tests := []struct {
+		password string
+		valid    bool
+	}{
+		{"passw0rd", true},
+		{"密码密码abc123", true},
+		{strings.Repeat("a", 71) + "1", true},
+		{"pass0rd", false},                     // 少于 8 位
+		{strings.Repeat("a", 72) + "1", false}, // 超过 bcrypt 的 72 字节
+		{"password", false},                    // 没有数字
+		{"12345678", false},                    // 没有字母
+		{"密码密码密码1", true},                      // 中文按字母计，按字节计算长度
+	}
+	v := &Validator{}
+	for _, tt := range tests {
+		err := v.ValidateStruct(&accountSample{Username: "alice", Password: tt.password})
+		if (err == nil) != tt.valid {
+			t.Errorf("password %q: err = %v, want valid = %t", tt.password, err, tt.valid)
+		}
+	}
+}
+
+func TestValidatorFieldNames(t *testing.T) {
+	err := (&Validator{}).ValidateStruct(&accountSample{Username: "1", Password: "x"})
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) || len(validationErrors) != 2 {
+		t.Fatalf("err = %v", err)
+	}
+	// 字段名使用 json 标签，规则名为自定义规则名
+	for i, want := range []string{"username", "password"} {
+		if fe := validationErrors[i]; fe.Field() != want || fe.Tag() != want {
+			t.Errorf("字段错误 = %s/%s, want %s/%s", fe.Field(), fe.Tag(), want, want)
+		}
+	}
+}
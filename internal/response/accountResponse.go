@@ -0,0 +1,19 @@
+package response
+
+type DataExportResponse struct {
+	ID        uint   `json:"id"`
+	Status    string `json:"status"` // pending / ready / failed
+	Size      int64  `json:"size"`
+	Error     string `json:"error,omitempty"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type AccountDeletionResponse struct {
+	ScheduledAt string `json:"scheduledAt"` // 计划删除时间，之前可撤销
+	CommentMode string `json:"commentMode"`
+}
+
+type LocaleResponse struct {
+	Locale string `json:"locale"` // 为空表示未设置偏好
+}
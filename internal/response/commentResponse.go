@@ -1,14 +1,67 @@
 package response
 
 type CommentDetailResponse struct {
-	ID      uint   `json:"id"`
-	Content string `json:"content"`
-	UserID  uint   `json:"userId"`
+	ID            uint   `json:"id"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"contentFormat"`
+	ContentHTML   string `json:"contentHtml"` // 服务端渲染并清洗后的 HTML，可直接插入页面
+	UserID        uint   `json:"userId"`
 	//Username  string `json:"username"`
-	CreatedAt string `json:"createdAt"`
-	UpdatedAt string `json:"updatedAt"`
+	GuestName    string `json:"guestName"`
+	GuestWebsite string `json:"guestWebsite"`
+	ParentID     uint   `json:"parentId"`
+	Depth        int    `json:"depth"`
+	ReplyCount   int    `json:"replyCount"`
+	Deleted      bool   `json:"deleted"`
+	EditedAt     string `json:"editedAt"`
+	CreatedAt    string `json:"createdAt"`
+	UpdatedAt    string `json:"updatedAt"`
+
+	Replies []CommentDetailResponse `json:"replies"`
 }
 
 type CreateCommentResponse struct {
-	Content string `json:"content"`
+	ID          uint   `json:"id"`
+	Content     string `json:"content"`
+	ContentHTML string `json:"contentHtml"`
+	ParentID    uint   `json:"parentId"`
+	Depth       int    `json:"depth"`
+	Status      string `json:"status"`
+}
+
+type UpdateCommentResponse struct {
+	ID          uint   `json:"id"`
+	Content     string `json:"content"`
+	ContentHTML string `json:"contentHtml"`
+	EditedAt    string `json:"editedAt"`
+}
+
+type ModerationCommentResponse struct {
+	ID        uint   `json:"id"`
+	PostID    uint   `json:"postId"`
+	UserID    uint   `json:"userId"`
+	GuestName string `json:"guestName"`
+	ParentID  uint   `json:"parentId"`
+	Content   string `json:"content"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type ModerationCommentListResponse struct {
+	Comments []ModerationCommentResponse `json:"comments"`
+	Total    int64                       `json:"total"`
+	PageNum  int                         `json:"pageNum"`
+	PageSize int                         `json:"pageSize"`
+}
+
+type ModerateResultResponse struct {
+	Updated []uint `json:"updated"`
+	Skipped []uint `json:"skipped"`
+}
+
+type GuestChallengeResponse struct {
+	Token      string `json:"token"`
+	Difficulty int    `json:"difficulty"`
+	ExpiresAt  int64  `json:"expiresAt"`
+	Algorithm  string `json:"algorithm"` // 求解方式说明
 }
@@ -0,0 +1,34 @@
+package response
+
+type MediaThumbnailResponse struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	URL    string `json:"url"`
+}
+
+type MediaPostResponse struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+type MediaResponse struct {
+	ID         uint                     `json:"id"`
+	UID        string                   `json:"uid"`
+	FileName   string                   `json:"fileName"`
+	MimeType   string                   `json:"mimeType"`
+	Size       int64                    `json:"size"`
+	Width      int                      `json:"width"`
+	Height     int                      `json:"height"`
+	URL        string                   `json:"url"`
+	Thumbnails []MediaThumbnailResponse `json:"thumbnails"`
+	References int64                    `json:"references"`      // 引用该媒体的文章数
+	Posts      []MediaPostResponse      `json:"posts,omitempty"` // 引用该媒体的文章（仅详情返回）
+	CreatedAt  string                   `json:"createdAt"`
+}
+
+type MediaListResponse struct {
+	Media    []MediaResponse `json:"media"`
+	Total    int64           `json:"total"`
+	PageNum  int             `json:"pageNum"`
+	PageSize int             `json:"pageSize"`
+}
@@ -1,37 +1,115 @@
 package response
 
+import "time"
+
 type CreatePostResponse struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Status   string   `json:"status"`
+	TagNames []string `json:"tags"`
 }
 
 type UpdatePostResponse struct {
-	ID      uint   `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	ID       uint     `json:"id"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Status   string   `json:"status"`
+	TagNames []string `json:"tags"`
 }
 
+// PostResponse 列表项只返回摘要，正文通过详情接口获取
 type PostResponse struct {
-	ID      uint   `json:"id"`
-	Title   string `json:"title"`
+	ID             uint      `json:"id"`
+	Title          string    `json:"title"`
+	Excerpt        string    `json:"excerpt"`
+	WordCount      int       `json:"wordCount"`
+	ReadingMinutes int       `json:"readingMinutes"`
+	UserID         uint      `json:"userId"`
+	Status         string    `json:"status"`
+	ViewCount      int64     `json:"viewCount"`
+	TagNames       []string  `json:"tags"`
+	CoverURL       string    `json:"coverUrl,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type PostCoverResponse struct {
+	MediaID    uint                     `json:"mediaId"`
+	URL        string                   `json:"url"`
+	Width      int                      `json:"width"`
+	Height     int                      `json:"height"`
+	Thumbnails []MediaThumbnailResponse `json:"thumbnails"`
+}
+
+// MetaTagResponse 输出为 <meta {attr}="{key}" content="{content}">
+type MetaTagResponse struct {
+	Attr    string `json:"attr"`
+	Key     string `json:"key"`
 	Content string `json:"content"`
 }
 
+// PostMetaResponse 文章页 <head> 元数据
+type PostMetaResponse struct {
+	Title        string                 `json:"title"`
+	Description  string                 `json:"description"`
+	CanonicalURL string                 `json:"canonicalUrl"`
+	Robots       string                 `json:"robots"`
+	Tags         []MetaTagResponse      `json:"tags"`
+	JSONLD       map[string]interface{} `json:"jsonLd"` // 序列化后放入 <script type="application/ld+json">
+}
+
+// TOCItemResponse 目录条目，Anchor 对应 contentHtml 中标题的 id
+type TOCItemResponse struct {
+	Level    int               `json:"level"`
+	Text     string            `json:"text"`
+	Anchor   string            `json:"anchor"`
+	Children []TOCItemResponse `json:"children,omitempty"`
+}
+
+// PostListFilterResponse 回显本次列表查询实际生效的筛选条件
+type PostListFilterResponse struct {
+	Keyword   string `json:"keyword,omitempty"`
+	AuthorID  uint   `json:"authorId,omitempty"`
+	StartDate string `json:"startDate,omitempty"`
+	EndDate   string `json:"endDate,omitempty"`
+	Status    string `json:"status"`
+	Tag       string `json:"tag,omitempty"`
+	Sort      string `json:"sort"`
+}
+
 type PostListResponse struct {
-	Posts    []PostResponse `json:"posts"`
-	Total    int            `json:"total"`
-	PageNum  int            `form:"page_num"`
-	PageSize int            `form:"page_size"`
+	Posts    []PostResponse         `json:"posts"`
+	Total    int                    `json:"total"`
+	PageNum  int                    `form:"page_num"`
+	PageSize int                    `form:"page_size"`
+	Filters  PostListFilterResponse `json:"filters"`
 }
 
 type PostDetailResponse struct {
-	ID        uint   `json:"id"`
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	UserID    uint   `json:"userId"`
-	CreatedAt string `json:"createdAt"`
-	UpdatedAt string `json:"updatedAt"`
-	Username  string `json:"username"`
+	ID             uint              `json:"id"`
+	Title          string            `json:"title"`
+	Content        string            `json:"content"`
+	ContentFormat  string            `json:"contentFormat"`
+	ContentHTML    string            `json:"contentHtml"` // 服务端渲染并清洗后的 HTML，可直接插入页面
+	WordCount      int               `json:"wordCount"`
+	ReadingMinutes int               `json:"readingMinutes"`
+	TOC            []TOCItemResponse `json:"toc"`
+	UserID         uint              `json:"userId"`
+	Status         string            `json:"status"`
+	ViewCount      int64             `json:"viewCount"`
+	TagNames       []string          `json:"tags"`
+	CreatedAt      string            `json:"createdAt"`
+	UpdatedAt      string            `json:"updatedAt"`
+	Username       string            `json:"username"`
+	// 当前实际生效的评论策略（open/moderated/closed）
+	CommentPolicy string `json:"commentPolicy"`
+	// 封面及 SEO 设置原值
+	Cover          *PostCoverResponse `json:"cover"`
+	SEOTitle       string             `json:"seoTitle"`
+	SEODescription string             `json:"seoDescription"`
+	CanonicalURL   string             `json:"canonicalUrl"`
+	NoIndex        bool               `json:"noIndex"`
+	// 可直接使用的页面元数据（Open Graph、Twitter 卡片、JSON-LD BlogPosting）
+	Meta PostMetaResponse `json:"meta"`
 
 	Comments []CommentDetailResponse `json:"comments"`
 }
@@ -0,0 +1,41 @@
+package response
+
+type TrashPostResponse struct {
+	ID        uint   `json:"id"`
+	Title     string `json:"title"`
+	UserID    uint   `json:"userId"`
+	Status    string `json:"status"`
+	Comments  int64  `json:"comments"` // 恢复时一并恢复的评论数
+	DeletedAt string `json:"deletedAt"`
+	PurgeAt   string `json:"purgeAt"` // 超过保留期后永久删除的时间
+}
+
+type TrashPostListResponse struct {
+	Posts    []TrashPostResponse `json:"posts"`
+	Total    int64               `json:"total"`
+	PageNum  int                 `json:"pageNum"`
+	PageSize int                 `json:"pageSize"`
+}
+
+type TrashCommentResponse struct {
+	ID        uint   `json:"id"`
+	PostID    uint   `json:"postId"`
+	PostTitle string `json:"postTitle"`
+	UserID    uint   `json:"userId"`
+	Content   string `json:"content"`
+	Status    string `json:"status"`
+	DeletedAt string `json:"deletedAt"`
+	PurgeAt   string `json:"purgeAt"`
+}
+
+type TrashCommentListResponse struct {
+	Comments []TrashCommentResponse `json:"comments"`
+	Total    int64                  `json:"total"`
+	PageNum  int                    `json:"pageNum"`
+	PageSize int                    `json:"pageSize"`
+}
+
+type RestorePostResponse struct {
+	ID       uint  `json:"id"`
+	Comments int64 `json:"comments"` // 一并恢复的评论数
+}
@@ -0,0 +1,461 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"go-my-blog/internal/repo"
+	"go-my-blog/pkg/archive"
+	"go-my-blog/pkg/i18n"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/storage"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dataExportTimeout 超过该时长仍未完成的导出任务视为已中断（如服务重启），不再阻止重新申请
+const dataExportTimeout = time.Hour
+
+type AccountService struct {
+	userRepo       *repo.UserRepository
+	postRepo       *repo.PostRepository
+	commentRepo    *repo.CommentRepository
+	dataExportRepo *repo.DataExportRepository
+	storage        storage.Storage
+	uow            *repo.UnitOfWork
+}
+
+func NewAccountService(userRepo *repo.UserRepository, postRepo *repo.PostRepository, commentRepo *repo.CommentRepository,
+	dataExportRepo *repo.DataExportRepository, storage storage.Storage, uow *repo.UnitOfWork) *AccountService {
+	return &AccountService{
+		userRepo:       userRepo,
+		postRepo:       postRepo,
+		commentRepo:    commentRepo,
+		dataExportRepo: dataExportRepo,
+		storage:        storage,
+		uow:            uow,
+	}
+}
+
+// RequestExport 申请导出个人数据：立即返回任务，归档在后台生成
+func (as *AccountService) RequestExport(ctx context.Context, userID uint, ip string) (*DTO.DataExportDTO, error) {
+	user, err := as.userRepo.FindById(ctx, userID)
+	if err != nil {
+		logger.Error("用户查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	// 导出任务与审计记录一起提交，不会出现没有审计记录的导出
+	var export *model.DataExport
+	err = as.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		pending, err := repos.DataExports.CountPending(ctx, userID, time.Now().Add(-dataExportTimeout))
+		if err != nil {
+			logger.Error("导出任务查询失败", zap.Error(err))
+			return err
+		}
+		if pending > 0 {
+			return ErrExportPending
+		}
+		export = &model.DataExport{UserID: userID, Status: model.DataExportPending}
+		if err := repos.DataExports.Create(ctx, export); err != nil {
+			logger.Error("导出任务创建失败", zap.Error(err))
+			return err
+		}
+		return audit(ctx, repos.Audit, model.AuditDataExport, userID, userID, ip, map[string]interface{}{"export_id": export.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 归档在后台生成，不随请求结束而取消
+	go as.buildExport(context.WithoutCancel(ctx), export.ID, user)
+
+	exportDTO := toDataExportDTO(export)
+	return &exportDTO, nil
+}
+
+// ExportStatus 查询本人的导出任务
+func (as *AccountService) ExportStatus(ctx context.Context, id uint, userID uint) (*DTO.DataExportDTO, error) {
+	export, err := as.getOwnedExport(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	exportDTO := toDataExportDTO(export)
+	return &exportDTO, nil
+}
+
+// ExportFile 已生成的归档文件路径和下载文件名
+func (as *AccountService) ExportFile(ctx context.Context, id uint, userID uint) (string, string, error) {
+	export, err := as.getOwnedExport(ctx, id, userID)
+	if err != nil {
+		return "", "", err
+	}
+	if export.Status != model.DataExportReady {
+		return "", "", ErrExportNotReady
+	}
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(time.Now()) {
+		return "", "", ErrExportExpired
+	}
+	fileName := fmt.Sprintf("go-my-blog-data-%s.tar.gz", export.CreatedAt.Format("20060102-150405"))
+	return filepath.Join(config.Conf.Account.ExportDir, export.FileName), fileName, nil
+}
+
+// RequestDeletion 申请注销账号：校验密码后进入冷静期，冷静期结束后由后台任务删除
+func (as *AccountService) RequestDeletion(ctx context.Context, d *DTO.DeleteAccountDTO) (*DTO.AccountDeletionDTO, error) {
+	if d.CommentMode == "" {
+		d.CommentMode = model.CommentModeAnonymize
+	}
+	if d.CommentMode != model.CommentModeAnonymize && d.CommentMode != model.CommentModeDelete {
+		return nil, ErrCommentModeInvalid
+	}
+	user, err := as.userRepo.FindById(ctx, d.UserID)
+	if err != nil {
+		logger.Error("用户查询失败", zap.Error(err))
+		return nil, err
+	}
+	if user.DeletionScheduledAt != nil {
+		return nil, ErrDeletionScheduled
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(d.Password)); err != nil {
+		logger.Warn("注销账号密码校验失败", zap.Uint("user_id", d.UserID))
+		return nil, ErrPasswordIncorrect
+	}
+
+	scheduledAt := time.Now().Add(config.Conf.Account.GetDeletionGrace())
+	err = as.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		if err := repos.Users.ScheduleDeletion(ctx, user.ID, &scheduledAt, d.CommentMode); err != nil {
+			logger.Error("注销申请保存失败", zap.Error(err))
+			return err
+		}
+		return audit(ctx, repos.Audit, model.AuditAccountDeletion, user.ID, user.ID, d.IP, map[string]interface{}{
+			"scheduled_at": scheduledAt,
+			"comment_mode": d.CommentMode,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &DTO.AccountDeletionDTO{ScheduledAt: scheduledAt.Format("2006-01-02 15:04:05"), CommentMode: d.CommentMode}, nil
+}
+
+// CancelDeletion 冷静期内撤销注销申请
+func (as *AccountService) CancelDeletion(ctx context.Context, userID uint, ip string) error {
+	user, err := as.userRepo.FindById(ctx, userID)
+	if err != nil {
+		logger.Error("用户查询失败", zap.Error(err))
+		return err
+	}
+	if user.DeletionScheduledAt == nil {
+		return ErrDeletionNotScheduled
+	}
+	return as.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		if err := repos.Users.ScheduleDeletion(ctx, user.ID, nil, ""); err != nil {
+			logger.Error("撤销注销申请失败", zap.Error(err))
+			return err
+		}
+		return audit(ctx, repos.Audit, model.AuditAccountDeletionCancel, user.ID, user.ID, ip, nil)
+	})
+}
+
+// UpdateLocale 设置接口提示信息的语言偏好，locale 为空表示清除偏好（按 Accept-Language 协商）；返回规范化后的语言
+func (as *AccountService) UpdateLocale(ctx context.Context, userID uint, locale string) (string, error) {
+	if locale != "" {
+		matched, ok := i18n.Match(locale)
+		if !ok {
+			return "", ErrLocaleUnsupported.
+				WithMessage("不支持的语言：" + locale).
+				WithDetails(map[string]interface{}{"locale": locale, "supported": i18n.Supported})
+		}
+		locale = matched
+	}
+	if err := as.userRepo.UpdateLocale(ctx, userID, locale); err != nil {
+		logger.Error("语言偏好保存失败", zap.Error(err))
+		return "", err
+	}
+	return locale, nil
+}
+
+// StartPurgeWorker 启动后台任务：定期删除冷静期已过的账号和过期的个人数据归档，ctx 取消时退出
+func (as *AccountService) StartPurgeWorker(ctx context.Context) {
+	interval := time.Duration(config.Conf.Account.PurgeIntervalM) * time.Minute
+	go func() {
+		for {
+			if err := as.PurgeDue(ctx); err != nil {
+				logger.Error("账号清理任务执行失败", zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+// PurgeDue 删除冷静期已过的账号和过期的归档；单个账号删除失败不影响其他账号
+func (as *AccountService) PurgeDue(ctx context.Context) error {
+	now := time.Now()
+	users, err := as.userRepo.ListDueDeletions(ctx, now)
+	if err != nil {
+		logger.Error("待删除账号查询失败", zap.Error(err))
+		return err
+	}
+	var failed int
+	for _, user := range users {
+		if err := as.purgeUser(ctx, &user); err != nil {
+			failed++
+		}
+	}
+
+	exports, err := as.dataExportRepo.ListExpired(ctx, now)
+	if err != nil {
+		logger.Error("过期归档查询失败", zap.Error(err))
+		return err
+	}
+	for _, export := range exports {
+		as.removeExportFile(&export)
+		if err := as.dataExportRepo.Delete(ctx, export.ID); err != nil {
+			logger.Error("过期导出任务删除失败", zap.Uint("export_id", export.ID), zap.Error(err))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d 个账号删除失败", failed)
+	}
+	return nil
+}
+
+// purgeUser 永久删除账号，删除与审计记录在同一事务中提交，提交后再删除媒体文件和归档文件
+func (as *AccountService) purgeUser(ctx context.Context, user *model.User) error {
+	var media []model.Media
+	var exports []model.DataExport
+	err := as.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		var err error
+		media, exports, err = repos.Users.Purge(ctx, user.ID, user.DeletionCommentMode, config.Conf.Account.AnonymousName)
+		if err != nil {
+			return err
+		}
+		return audit(ctx, repos.Audit, model.AuditAccountDeleted, 0, user.ID, "", map[string]interface{}{
+			"comment_mode": user.DeletionCommentMode,
+			"media":        len(media),
+		})
+	})
+	if err != nil {
+		logger.Error("账号删除失败", zap.Uint("user_id", user.ID), zap.Error(err))
+		return err
+	}
+	for i := range media {
+		keys := []string{media[i].StorageKey}
+		for _, thumbnail := range thumbnailsOf(&media[i]) {
+			keys = append(keys, thumbnail.Key)
+		}
+		for _, key := range keys {
+			if err := as.storage.Delete(key); err != nil {
+				logger.Warn("媒体文件删除失败", zap.String("key", key), zap.Error(err))
+			}
+		}
+	}
+	for i := range exports {
+		as.removeExportFile(&exports[i])
+	}
+	logger.Info("账号已删除", zap.Uint("user_id", user.ID))
+	return nil
+}
+
+// buildExport 生成个人数据归档：资料、文章（含草稿）和评论，先写临时文件，完成后改名
+func (as *AccountService) buildExport(ctx context.Context, exportID uint, user *model.User) {
+	fileName := ""
+	err := func() error {
+		dir := config.Conf.Account.ExportDir
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+		uid, err := newMediaUID()
+		if err != nil {
+			return err
+		}
+		tmp, err := os.CreateTemp(dir, ".export-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		if err := as.writePersonalData(ctx, tmp, user); err != nil {
+			tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		fileName = fmt.Sprintf("user-%d-%s.tar.gz", user.ID, uid)
+		return os.Rename(tmp.Name(), filepath.Join(dir, fileName))
+	}()
+
+	if err != nil {
+		logger.Error("个人数据归档生成失败", zap.Uint("export_id", exportID), zap.Error(err))
+		if err := as.dataExportRepo.Updates(ctx, exportID, map[string]interface{}{
+			"status": model.DataExportFailed,
+			"error":  truncateRunes(err.Error(), 500),
+		}); err != nil {
+			logger.Error("导出任务更新失败", zap.Error(err))
+		}
+		return
+	}
+
+	var size int64
+	if info, err := os.Stat(filepath.Join(config.Conf.Account.ExportDir, fileName)); err == nil {
+		size = info.Size()
+	}
+	expiresAt := time.Now().Add(config.Conf.Account.GetExportTTL())
+	if err := as.dataExportRepo.Updates(ctx, exportID, map[string]interface{}{
+		"status":     model.DataExportReady,
+		"file_name":  fileName,
+		"size":       size,
+		"expires_at": expiresAt,
+	}); err != nil {
+		logger.Error("导出任务更新失败", zap.Error(err))
+	}
+}
+
+func (as *AccountService) writePersonalData(ctx context.Context, file *os.File, user *model.User) error {
+	aw := archive.NewWriter(file)
+	manifest := DTO.BackupManifestDTO{Format: DTO.PersonalDataFormat, Version: 1, CreatedAt: time.Now()}
+	if err := aw.WriteJSON("manifest.json", manifest); err != nil {
+		return err
+	}
+	profile := DTO.AccountProfileDTO{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		Locale:    user.Locale,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+	if err := aw.WriteJSON("profile.json", profile); err != nil {
+		return err
+	}
+
+	posts, err := as.postRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	lw, err := aw.Lines("posts.jsonl")
+	if err != nil {
+		return err
+	}
+	for _, post := range posts {
+		tags := make([]string, 0, len(post.Tags))
+		for _, tag := range post.Tags {
+			tags = append(tags, tag.Name)
+		}
+		record := DTO.AccountPostDTO{
+			ID:            post.ID,
+			Title:         post.Title,
+			Content:       post.Content,
+			ContentFormat: post.ContentFormat,
+			Status:        post.Status,
+			Tags:          tags,
+			CreatedAt:     post.CreatedAt,
+			UpdatedAt:     post.UpdatedAt,
+		}
+		if post.Status == model.PostStatusPublished {
+			record.URL = config.Conf.Site.PostURL(post.ID)
+		}
+		if err := lw.Write(record); err != nil {
+			lw.Close()
+			return err
+		}
+	}
+	if err := lw.Close(); err != nil {
+		return err
+	}
+
+	comments, err := as.commentRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	lw, err = aw.Lines("comments.jsonl")
+	if err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		if err := lw.Write(DTO.AccountCommentDTO{
+			ID:            comment.ID,
+			PostID:        comment.PostID,
+			ParentID:      comment.ParentID,
+			Content:       comment.Content,
+			ContentFormat: comment.ContentFormat,
+			Status:        comment.Status,
+			IP:            comment.IP,
+			EditedAt:      comment.EditedAt,
+			CreatedAt:     comment.CreatedAt,
+		}); err != nil {
+			lw.Close()
+			return err
+		}
+	}
+	if err := lw.Close(); err != nil {
+		return err
+	}
+	return aw.Close()
+}
+
+// getOwnedExport 查询导出任务，只能查看本人的任务；他人的任务按不存在处理
+func (as *AccountService) getOwnedExport(ctx context.Context, id uint, userID uint) (*model.DataExport, error) {
+	export, err := as.dataExportRepo.GetById(ctx, id)
+	if err != nil {
+		logger.Error("导出任务查询失败", zap.Error(err))
+		return nil, notFound(err, ErrExportNotFound)
+	}
+	if export.UserID != userID {
+		logger.Warn("无权查看他人的导出任务", zap.Uint("user_id", userID), zap.Uint("export_id", id))
+		return nil, ErrExportNotFound
+	}
+	return export, nil
+}
+
+func (as *AccountService) removeExportFile(export *model.DataExport) {
+	if export.FileName == "" {
+		return
+	}
+	path := filepath.Join(config.Conf.Account.ExportDir, export.FileName)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		logger.Warn("归档文件删除失败", zap.String("path", path), zap.Error(err))
+	}
+}
+
+// audit 在被审计操作的事务中写入审计记录，写入失败时整个操作回滚
+func audit(ctx context.Context, auditRepo *repo.AuditRepository, action string, actorID uint, subjectID uint, ip string, detail map[string]interface{}) error {
+	log := &model.AuditLog{Action: action, ActorID: actorID, SubjectID: subjectID, IP: ip}
+	if detail != nil {
+		if data, err := json.Marshal(detail); err == nil {
+			log.Detail = string(data)
+		}
+	}
+	if err := auditRepo.Create(ctx, log); err != nil {
+		logger.Error("审计记录写入失败", zap.String("action", action), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func toDataExportDTO(export *model.DataExport) DTO.DataExportDTO {
+	exportDTO := DTO.DataExportDTO{
+		ID:        export.ID,
+		Status:    export.Status,
+		Size:      export.Size,
+		Error:     export.Error,
+		CreatedAt: export.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if export.ExpiresAt != nil {
+		exportDTO.ExpiresAt = export.ExpiresAt.Format("2006-01-02 15:04:05")
+	}
+	return exportDTO
+}
@@ -0,0 +1,801 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"go-my-blog/internal/repo"
+	"go-my-blog/pkg/archive"
+	"go-my-blog/pkg/imaging"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/render"
+	"go-my-blog/pkg/storage"
+	"io"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 归档中的文件，按依赖顺序排列：被引用的实体总在引用方之前
+const (
+	backupManifestFile     = "manifest.json"
+	backupUsersFile        = "users.jsonl"
+	backupMediaFile        = "media.jsonl"
+	backupTagsFile         = "tags.jsonl"
+	backupPostsFile        = "posts.jsonl"
+	backupPostTagsFile     = "post_tags.jsonl"
+	backupPostMediaFile    = "post_media.jsonl"
+	backupCommentsFile     = "comments.jsonl"
+	backupRevisionsFile    = "comment_revisions.jsonl"
+	backupRedirectsFile    = "redirects.jsonl"
+	backupSpamTokensFile   = "spam_tokens.jsonl"
+	backupMediaFilesPrefix = "media/"
+)
+
+type BackupService struct {
+	backupRepo *repo.BackupRepository
+	userRepo   *repo.UserRepository
+	storage    storage.Storage
+	uow        *repo.UnitOfWork
+}
+
+func NewBackupService(backupRepo *repo.BackupRepository, userRepo *repo.UserRepository, storage storage.Storage, uow *repo.UnitOfWork) *BackupService {
+	return &BackupService{
+		backupRepo: backupRepo,
+		userRepo:   userRepo,
+		storage:    storage,
+		uow:        uow,
+	}
+}
+
+// CheckAdmin 整站导出只允许管理员操作
+func (bs *BackupService) CheckAdmin(ctx context.Context, userID uint) error {
+	user, err := bs.userRepo.FindById(ctx, userID)
+	if err != nil {
+		logger.Error("用户查询失败", zap.Error(err))
+		return err
+	}
+	if !user.IsAdmin() {
+		logger.Error("非管理员无权导出数据", zap.Uint("user_id", userID))
+		return ErrBackupForbidden
+	}
+	return nil
+}
+
+// Export 导出整站数据（含软删除的记录和媒体文件）为 tar.gz 归档：
+// manifest.json 在最前，之后按依赖顺序写出各实体的 JSON Lines 文件，媒体文件放在最后
+func (bs *BackupService) Export(ctx context.Context, w io.Writer) (*DTO.BackupReportDTO, error) {
+	aw := archive.NewWriter(w)
+	report := &DTO.BackupReportDTO{Counts: make(map[string]int)}
+
+	manifest := DTO.BackupManifestDTO{Format: DTO.BackupFormat, Version: DTO.BackupVersion, CreatedAt: time.Now()}
+	if err := aw.WriteJSON(backupManifestFile, manifest); err != nil {
+		logger.Error("归档清单写入失败", zap.Error(err))
+		return nil, err
+	}
+
+	var mediaKeys []string
+	steps := []struct {
+		name  string
+		write func(lw *archive.LineWriter) error
+	}{
+		{backupUsersFile, func(lw *archive.LineWriter) error {
+			return bs.backupRepo.EachUser(ctx, func(users []model.User) error {
+				for _, user := range users {
+					if err := lw.Write(DTO.BackupUserDTO{
+						ID:                    user.ID,
+						Username:              user.Username,
+						Password:              user.Password,
+						Email:                 user.Email,
+						Role:                  user.Role,
+						Locale:                user.Locale,
+						PasswordResetRequired: user.PasswordResetRequired,
+						ImportSource:          user.ImportSource,
+						DeletionScheduledAt:   user.DeletionScheduledAt,
+						DeletionCommentMode:   user.DeletionCommentMode,
+						CreatedAt:             user.CreatedAt,
+						UpdatedAt:             user.UpdatedAt,
+						DeletedAt:             deletedAtOf(user.DeletedAt),
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}},
+		{backupMediaFile, func(lw *archive.LineWriter) error {
+			return bs.backupRepo.EachMedia(ctx, func(media []model.Media) error {
+				for i := range media {
+					mediaKeys = append(mediaKeys, media[i].StorageKey)
+					for _, thumbnail := range thumbnailsOf(&media[i]) {
+						mediaKeys = append(mediaKeys, thumbnail.Key)
+					}
+					if err := lw.Write(DTO.BackupMediaDTO{
+						ID:         media[i].ID,
+						UID:        media[i].UID,
+						UserID:     media[i].UserID,
+						FileName:   media[i].FileName,
+						MimeType:   media[i].MimeType,
+						Size:       media[i].Size,
+						Width:      media[i].Width,
+						Height:     media[i].Height,
+						StorageKey: media[i].StorageKey,
+						Thumbnails: media[i].Thumbnails,
+						CreatedAt:  media[i].CreatedAt,
+						UpdatedAt:  media[i].UpdatedAt,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}},
+		{backupTagsFile, func(lw *archive.LineWriter) error {
+			return bs.backupRepo.EachTag(ctx, func(tags []model.Tag) error {
+				for _, tag := range tags {
+					if err := lw.Write(DTO.BackupTagDTO{ID: tag.ID, Name: tag.Name, CreatedAt: tag.CreatedAt}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}},
+		{backupPostsFile, func(lw *archive.LineWriter) error {
+			return bs.backupRepo.EachPost(ctx, func(posts []model.Post) error {
+				for _, post := range posts {
+					if err := lw.Write(DTO.BackupPostDTO{
+						ID:                   post.ID,
+						Title:                post.Title,
+						Content:              post.Content,
+						ContentFormat:        post.ContentFormat,
+						UserID:               post.UserID,
+						Status:               post.Status,
+						ViewCount:            post.ViewCount,
+						CommentPolicy:        post.CommentPolicy,
+						CommentAutoCloseDays: post.CommentAutoCloseDays,
+						CoverMediaID:         post.CoverMediaID,
+						SEOTitle:             post.SEOTitle,
+						SEODescription:       post.SEODescription,
+						CanonicalURL:         post.CanonicalURL,
+						NoIndex:              post.NoIndex,
+						Slug:                 post.Slug,
+						SourcePath:           post.SourcePath,
+						CreatedAt:            post.CreatedAt,
+						UpdatedAt:            post.UpdatedAt,
+						DeletedAt:            deletedAtOf(post.DeletedAt),
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}},
+		{backupPostTagsFile, func(lw *archive.LineWriter) error {
+			return bs.backupRepo.EachPostTag(ctx, func(relations []repo.PostRelation) error {
+				for _, relation := range relations {
+					if err := lw.Write(DTO.BackupPostTagDTO{PostID: relation.PostID, TagID: relation.TargetID}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}},
+		{backupPostMediaFile, func(lw *archive.LineWriter) error {
+			return bs.backupRepo.EachPostMedia(ctx, func(relations []repo.PostRelation) error {
+				for _, relation := range relations {
+					if err := lw.Write(DTO.BackupPostMediaDTO{PostID: relation.PostID, MediaID: relation.TargetID}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}},
+		{backupCommentsFile, func(lw *archive.LineWriter) error {
+			return bs.backupRepo.EachComment(ctx, func(comments []model.Comment) error {
+				for _, comment := range comments {
+					if err := lw.Write(DTO.BackupCommentDTO{
+						ID:            comment.ID,
+						PostID:        comment.PostID,
+						UserID:        comment.UserID,
+						ParentID:      comment.ParentID,
+						Content:       comment.Content,
+						ContentFormat: comment.ContentFormat,
+						ReplyCount:    comment.ReplyCount,
+						IsDeleted:     comment.IsDeleted,
+						Status:        comment.Status,
+						SpamScore:     comment.SpamScore,
+						SpamLabel:     comment.SpamLabel,
+						IP:            comment.IP,
+						GuestName:     comment.GuestName,
+						GuestEmail:    comment.GuestEmail,
+						GuestWebsite:  comment.GuestWebsite,
+						SourceID:      comment.SourceID,
+						EditedAt:      comment.EditedAt,
+						CreatedAt:     comment.CreatedAt,
+						UpdatedAt:     comment.UpdatedAt,
+						DeletedAt:     deletedAtOf(comment.DeletedAt),
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}},
+		{backupRevisionsFile, func(lw *archive.LineWriter) error {
+			return bs.backupRepo.EachCommentRevision(ctx, func(revisions []model.CommentRevision) error {
+				for _, revision := range revisions {
+					if err := lw.Write(DTO.BackupCommentRevisionDTO{
+						ID:            revision.ID,
+						CommentID:     revision.CommentID,
+						Content:       revision.Content,
+						ContentFormat: revision.ContentFormat,
+						EditorID:      revision.EditorID,
+						CreatedAt:     revision.CreatedAt,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}},
+		{backupRedirectsFile, func(lw *archive.LineWriter) error {
+			return bs.backupRepo.EachRedirect(ctx, func(redirects []model.Redirect) error {
+				for _, redirect := range redirects {
+					if err := lw.Write(DTO.BackupRedirectDTO{
+						FromPath:  redirect.FromPath,
+						PostID:    redirect.PostID,
+						CreatedAt: redirect.CreatedAt,
+						UpdatedAt: redirect.UpdatedAt,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}},
+		{backupSpamTokensFile, func(lw *archive.LineWriter) error {
+			return bs.backupRepo.EachSpamToken(ctx, func(tokens []model.SpamToken) error {
+				for _, token := range tokens {
+					if err := lw.Write(DTO.BackupSpamTokenDTO{
+						Token:     token.Token,
+						SpamCount: token.SpamCount,
+						HamCount:  token.HamCount,
+						UpdatedAt: token.UpdatedAt,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}},
+	}
+	for _, step := range steps {
+		lw, err := aw.Lines(step.name)
+		if err != nil {
+			logger.Error("归档临时文件创建失败", zap.Error(err))
+			return nil, err
+		}
+		if err := step.write(lw); err != nil {
+			lw.Close()
+			logger.Error("归档数据导出失败", zap.String("file", step.name), zap.Error(err))
+			return nil, err
+		}
+		if err := lw.Close(); err != nil {
+			logger.Error("归档数据写入失败", zap.String("file", step.name), zap.Error(err))
+			return nil, err
+		}
+		report.Counts[step.name] = lw.Count
+	}
+
+	// 媒体文件：存储中已丢失的文件跳过并记录日志，不影响其余数据的导出
+	for _, key := range mediaKeys {
+		data, err := bs.readMediaFile(key)
+		if err != nil {
+			logger.Warn("媒体文件读取失败，已跳过", zap.String("key", key), zap.Error(err))
+			report.Counts["media_missing"]++
+			continue
+		}
+		if err := aw.WriteFile(backupMediaFilesPrefix+key, data); err != nil {
+			logger.Error("媒体文件写入归档失败", zap.String("key", key), zap.Error(err))
+			return nil, err
+		}
+		report.Counts["media"]++
+	}
+
+	if err := aw.Close(); err != nil {
+		logger.Error("归档写入失败", zap.Error(err))
+		return nil, err
+	}
+	return report, nil
+}
+
+// Restore 从归档恢复整站数据：只允许恢复到空数据库，全部数据在一个事务中写入，
+// ID 由数据库重新分配并按新旧ID映射改写引用；引用的记录不存在时视为数据不完整，整体回滚
+func (bs *BackupService) Restore(ctx context.Context, r io.Reader) (*DTO.BackupReportDTO, error) {
+	empty, err := bs.backupRepo.IsEmpty(ctx)
+	if err != nil {
+		logger.Error("数据库状态查询失败", zap.Error(err))
+		return nil, err
+	}
+	if !empty {
+		return nil, errors.New("只能恢复到空数据库，当前数据库中已有数据")
+	}
+
+	ar, err := archive.NewReader(r)
+	if err != nil {
+		logger.Error("归档读取失败", zap.Error(err))
+		return nil, fmt.Errorf("归档读取失败：%w", err)
+	}
+	defer ar.Close()
+
+	if err := readBackupManifest(ar); err != nil {
+		return nil, err
+	}
+
+	restore := newBackupRestore(bs.storage)
+	started := false
+	err = bs.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		// 归档是只能顺序读取一次的流，事务冲突后不能原样重试
+		if started {
+			return errors.New("数据恢复时发生事务冲突，请重新执行恢复")
+		}
+		started = true
+		restore.repo = repos.Backup
+		return restore.load(ctx, ar)
+	})
+	if err != nil {
+		logger.Error("数据恢复失败", zap.Error(err))
+		restore.cleanup()
+		return nil, err
+	}
+	return restore.report, nil
+}
+
+// readBackupManifest 读取并校验归档开头的清单
+func readBackupManifest(ar *archive.Reader) error {
+	name, err := ar.Next()
+	if err != nil || name != backupManifestFile {
+		return errors.New("归档格式错误：缺少 manifest.json")
+	}
+	var manifest DTO.BackupManifestDTO
+	if err := ar.DecodeJSON(&manifest); err != nil {
+		return fmt.Errorf("归档清单解析失败：%w", err)
+	}
+	if manifest.Format != DTO.BackupFormat {
+		return fmt.Errorf("不支持的归档格式 %q", manifest.Format)
+	}
+	if manifest.Version < 1 || manifest.Version > DTO.BackupVersion {
+		return fmt.Errorf("不支持的归档版本 %d（当前支持 %d）", manifest.Version, DTO.BackupVersion)
+	}
+	return nil
+}
+
+func (bs *BackupService) readMediaFile(key string) ([]byte, error) {
+	file, err := bs.storage.Open(key)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// backupStore 恢复时写入数据库的操作，由 repo.BackupRepository 实现
+type backupStore interface {
+	Create(ctx context.Context, value interface{}) error
+	UpdateCommentPath(ctx context.Context, id uint, path string) error
+	CreatePostTag(ctx context.Context, postID uint, tagID uint) error
+	CreatePostMedia(ctx context.Context, postID uint, mediaID uint) error
+}
+
+// backupRestore 一次恢复过程的状态：各实体的旧ID -> 新ID 映射
+type backupRestore struct {
+	repo    backupStore
+	storage storage.Storage
+	report  *DTO.BackupReportDTO
+
+	users    map[uint]uint
+	media    map[uint]uint
+	tags     map[uint]uint
+	posts    map[uint]uint
+	comments map[uint]uint
+	// 评论新ID -> 物化路径/深度，子评论据此计算自己的路径
+	commentPaths map[uint]string
+	commentDepth map[uint]int
+	// media.jsonl 中声明的文件 key，只恢复这些文件
+	mediaKeys  map[string]bool
+	storedKeys []string
+}
+
+func newBackupRestore(storage storage.Storage) *backupRestore {
+	return &backupRestore{
+		storage:      storage,
+		report:       &DTO.BackupReportDTO{Counts: make(map[string]int)},
+		users:        make(map[uint]uint),
+		media:        make(map[uint]uint),
+		tags:         make(map[uint]uint),
+		posts:        make(map[uint]uint),
+		comments:     make(map[uint]uint),
+		commentPaths: make(map[uint]string),
+		commentDepth: make(map[uint]int),
+		mediaKeys:    make(map[string]bool),
+	}
+}
+
+// load 按归档顺序写入清单之后的全部文件，遇到错误立即返回，由调用方回滚事务
+func (br *backupRestore) load(ctx context.Context, ar *archive.Reader) error {
+	loaders := map[string]func(ctx context.Context, line []byte) error{
+		backupUsersFile:      br.user,
+		backupMediaFile:      br.mediaRecord,
+		backupTagsFile:       br.tag,
+		backupPostsFile:      br.post,
+		backupPostTagsFile:   br.postTag,
+		backupPostMediaFile:  br.postMedia,
+		backupCommentsFile:   br.comment,
+		backupRevisionsFile:  br.revision,
+		backupRedirectsFile:  br.redirect,
+		backupSpamTokensFile: br.spamToken,
+	}
+	for {
+		name, err := ar.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("归档读取失败：%w", err)
+		}
+		if strings.HasPrefix(name, backupMediaFilesPrefix) {
+			if err := br.mediaFile(strings.TrimPrefix(name, backupMediaFilesPrefix), ar.Body()); err != nil {
+				return err
+			}
+			continue
+		}
+		loader, ok := loaders[name]
+		if !ok {
+			logger.Warn("归档中有无法识别的文件，已跳过", zap.String("file", name))
+			continue
+		}
+		if err := ar.EachLine(func(line []byte) error { return loader(ctx, line) }); err != nil {
+			return fmt.Errorf("%s：%w", name, err)
+		}
+	}
+}
+
+func (br *backupRestore) user(ctx context.Context, line []byte) error {
+	var record DTO.BackupUserDTO
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	user := model.User{
+		Username:              record.Username,
+		Password:              record.Password,
+		Email:                 record.Email,
+		Role:                  record.Role,
+		Locale:                record.Locale,
+		PasswordResetRequired: record.PasswordResetRequired,
+		ImportSource:          record.ImportSource,
+		DeletionScheduledAt:   record.DeletionScheduledAt,
+		DeletionCommentMode:   record.DeletionCommentMode,
+		CreatedAt:             record.CreatedAt,
+		UpdatedAt:             record.UpdatedAt,
+		DeletedAt:             gormDeletedAt(record.DeletedAt),
+	}
+	if err := br.create(ctx, br.users, "用户", record.ID, &user); err != nil {
+		return err
+	}
+	br.users[record.ID] = user.ID
+	br.report.Counts[backupUsersFile]++
+	return nil
+}
+
+func (br *backupRestore) mediaRecord(ctx context.Context, line []byte) error {
+	var record DTO.BackupMediaDTO
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	userID, err := br.lookup(br.users, "用户", record.UserID, fmt.Sprintf("媒体 %d", record.ID))
+	if err != nil {
+		return err
+	}
+	media := model.Media{
+		UID:        record.UID,
+		UserID:     userID,
+		FileName:   record.FileName,
+		MimeType:   record.MimeType,
+		Size:       record.Size,
+		Width:      record.Width,
+		Height:     record.Height,
+		Storage:    config.Conf.Media.Storage, // 文件恢复到当前配置的存储中
+		StorageKey: record.StorageKey,
+		Thumbnails: record.Thumbnails,
+		CreatedAt:  record.CreatedAt,
+		UpdatedAt:  record.UpdatedAt,
+	}
+	if err := br.create(ctx, br.media, "媒体", record.ID, &media); err != nil {
+		return err
+	}
+	br.media[record.ID] = media.ID
+	br.mediaKeys[media.StorageKey] = true
+	for _, thumbnail := range thumbnailsOf(&media) {
+		br.mediaKeys[thumbnail.Key] = true
+	}
+	br.report.Counts[backupMediaFile]++
+	return nil
+}
+
+func (br *backupRestore) tag(ctx context.Context, line []byte) error {
+	var record DTO.BackupTagDTO
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	tag := model.Tag{Name: record.Name, CreatedAt: record.CreatedAt}
+	if err := br.create(ctx, br.tags, "标签", record.ID, &tag); err != nil {
+		return err
+	}
+	br.tags[record.ID] = tag.ID
+	br.report.Counts[backupTagsFile]++
+	return nil
+}
+
+func (br *backupRestore) post(ctx context.Context, line []byte) error {
+	var record DTO.BackupPostDTO
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	userID, err := br.lookup(br.users, "用户", record.UserID, fmt.Sprintf("文章 %d", record.ID))
+	if err != nil {
+		return err
+	}
+	post := model.Post{
+		Title:                record.Title,
+		Content:              record.Content,
+		ContentFormat:        record.ContentFormat,
+		UserID:               userID,
+		Status:               record.Status,
+		ViewCount:            record.ViewCount,
+		CommentPolicy:        record.CommentPolicy,
+		CommentAutoCloseDays: record.CommentAutoCloseDays,
+		SEOTitle:             record.SEOTitle,
+		SEODescription:       record.SEODescription,
+		CanonicalURL:         record.CanonicalURL,
+		NoIndex:              record.NoIndex,
+		Slug:                 record.Slug,
+		SourcePath:           record.SourcePath,
+		CreatedAt:            record.CreatedAt,
+		UpdatedAt:            record.UpdatedAt,
+		DeletedAt:            gormDeletedAt(record.DeletedAt),
+	}
+	if record.CoverMediaID != nil {
+		coverID, err := br.lookup(br.media, "媒体", *record.CoverMediaID, fmt.Sprintf("文章 %d", record.ID))
+		if err != nil {
+			return err
+		}
+		post.CoverMediaID = &coverID
+	}
+	// HTML 缓存、摘要、字数和目录由当前渲染器重新生成
+	applyRendered(&post)
+	if err := br.create(ctx, br.posts, "文章", record.ID, &post); err != nil {
+		return err
+	}
+	br.posts[record.ID] = post.ID
+	br.report.Counts[backupPostsFile]++
+	return nil
+}
+
+func (br *backupRestore) postTag(ctx context.Context, line []byte) error {
+	var record DTO.BackupPostTagDTO
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	postID, err := br.lookup(br.posts, "文章", record.PostID, fmt.Sprintf("文章 %d 的标签关联", record.PostID))
+	if err != nil {
+		return err
+	}
+	tagID, err := br.lookup(br.tags, "标签", record.TagID, fmt.Sprintf("文章 %d 的标签关联", record.PostID))
+	if err != nil {
+		return err
+	}
+	if err := br.repo.CreatePostTag(ctx, postID, tagID); err != nil {
+		return err
+	}
+	br.report.Counts[backupPostTagsFile]++
+	return nil
+}
+
+func (br *backupRestore) postMedia(ctx context.Context, line []byte) error {
+	var record DTO.BackupPostMediaDTO
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	postID, err := br.lookup(br.posts, "文章", record.PostID, fmt.Sprintf("文章 %d 的媒体引用", record.PostID))
+	if err != nil {
+		return err
+	}
+	mediaID, err := br.lookup(br.media, "媒体", record.MediaID, fmt.Sprintf("文章 %d 的媒体引用", record.PostID))
+	if err != nil {
+		return err
+	}
+	if err := br.repo.CreatePostMedia(ctx, postID, mediaID); err != nil {
+		return err
+	}
+	br.report.Counts[backupPostMediaFile]++
+	return nil
+}
+
+func (br *backupRestore) comment(ctx context.Context, line []byte) error {
+	var record DTO.BackupCommentDTO
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	postID, err := br.lookup(br.posts, "文章", record.PostID, fmt.Sprintf("评论 %d", record.ID))
+	if err != nil {
+		return err
+	}
+	comment := model.Comment{
+		Content:       record.Content,
+		ContentFormat: record.ContentFormat,
+		PostID:        postID,
+		ReplyCount:    record.ReplyCount,
+		IsDeleted:     record.IsDeleted,
+		Status:        record.Status,
+		SpamScore:     record.SpamScore,
+		SpamLabel:     record.SpamLabel,
+		IP:            record.IP,
+		GuestName:     record.GuestName,
+		GuestEmail:    record.GuestEmail,
+		GuestWebsite:  record.GuestWebsite,
+		SourceID:      record.SourceID,
+		EditedAt:      record.EditedAt,
+		CreatedAt:     record.CreatedAt,
+		UpdatedAt:     record.UpdatedAt,
+		DeletedAt:     gormDeletedAt(record.DeletedAt),
+	}
+	comment.ContentHTML = renderContent(comment.Content, comment.ContentFormat)
+	comment.RenderVersion = render.Version
+	if record.UserID != nil {
+		userID, err := br.lookup(br.users, "用户", *record.UserID, fmt.Sprintf("评论 %d", record.ID))
+		if err != nil {
+			return err
+		}
+		comment.UserID = &userID
+	}
+	parentPath := ""
+	if record.ParentID != nil {
+		parentID, err := br.lookup(br.comments, "父评论", *record.ParentID, fmt.Sprintf("评论 %d", record.ID))
+		if err != nil {
+			return err
+		}
+		comment.ParentID = &parentID
+		comment.Depth = br.commentDepth[parentID] + 1
+		parentPath = br.commentPaths[parentID]
+	}
+	if err := br.create(ctx, br.comments, "评论", record.ID, &comment); err != nil {
+		return err
+	}
+	path := parentPath + model.CommentPathSegment(comment.ID)
+	if err := br.repo.UpdateCommentPath(ctx, comment.ID, path); err != nil {
+		return err
+	}
+	br.comments[record.ID] = comment.ID
+	br.commentPaths[comment.ID] = path
+	br.commentDepth[comment.ID] = comment.Depth
+	br.report.Counts[backupCommentsFile]++
+	return nil
+}
+
+func (br *backupRestore) revision(ctx context.Context, line []byte) error {
+	var record DTO.BackupCommentRevisionDTO
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	commentID, err := br.lookup(br.comments, "评论", record.CommentID, fmt.Sprintf("评论历史版本 %d", record.ID))
+	if err != nil {
+		return err
+	}
+	editorID, err := br.lookup(br.users, "用户", record.EditorID, fmt.Sprintf("评论历史版本 %d", record.ID))
+	if err != nil {
+		return err
+	}
+	// 旧版本备份中没有内容格式，写入时使用数据库默认值
+	revision := model.CommentRevision{CommentID: commentID, Content: record.Content, ContentFormat: record.ContentFormat, EditorID: editorID, CreatedAt: record.CreatedAt}
+	if err := br.repo.Create(ctx, &revision); err != nil {
+		return err
+	}
+	br.report.Counts[backupRevisionsFile]++
+	return nil
+}
+
+func (br *backupRestore) redirect(ctx context.Context, line []byte) error {
+	var record DTO.BackupRedirectDTO
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	postID, err := br.lookup(br.posts, "文章", record.PostID, "重定向 "+record.FromPath)
+	if err != nil {
+		return err
+	}
+	redirect := model.Redirect{FromPath: record.FromPath, PostID: postID, CreatedAt: record.CreatedAt, UpdatedAt: record.UpdatedAt}
+	if err := br.repo.Create(ctx, &redirect); err != nil {
+		return err
+	}
+	br.report.Counts[backupRedirectsFile]++
+	return nil
+}
+
+func (br *backupRestore) spamToken(ctx context.Context, line []byte) error {
+	var record DTO.BackupSpamTokenDTO
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	token := model.SpamToken{Token: record.Token, SpamCount: record.SpamCount, HamCount: record.HamCount, UpdatedAt: record.UpdatedAt}
+	if err := br.repo.Create(ctx, &token); err != nil {
+		return err
+	}
+	br.report.Counts[backupSpamTokensFile]++
+	return nil
+}
+
+// mediaFile 把归档中的媒体文件写入存储，只接受 media.jsonl 中声明过的 key
+func (br *backupRestore) mediaFile(key string, body io.Reader) error {
+	if !br.mediaKeys[key] {
+		return fmt.Errorf("数据完整性错误：归档中的文件 %s 没有对应的媒体记录", key)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if err := br.storage.Put(key, bytes.NewReader(data), int64(len(data)), imaging.Sniff(data)); err != nil {
+		logger.Error("媒体文件写入存储失败", zap.String("key", key), zap.Error(err))
+		return err
+	}
+	br.storedKeys = append(br.storedKeys, key)
+	br.report.Counts["media"]++
+	return nil
+}
+
+// cleanup 恢复失败时删除已写入存储的媒体文件（数据库部分由事务回滚）
+func (br *backupRestore) cleanup() {
+	for _, key := range br.storedKeys {
+		if err := br.storage.Delete(key); err != nil {
+			logger.Warn("清理媒体文件失败", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// create 写入一条记录，同一实体的旧ID重复出现时视为归档损坏
+func (br *backupRestore) create(ctx context.Context, ids map[uint]uint, kind string, oldID uint, value interface{}) error {
+	if _, ok := ids[oldID]; ok {
+		return fmt.Errorf("数据完整性错误：%s %d 重复", kind, oldID)
+	}
+	return br.repo.Create(ctx, value)
+}
+
+// lookup 把旧ID映射为新ID，引用的记录不存在（或出现在引用方之后）时返回完整性错误
+func (br *backupRestore) lookup(ids map[uint]uint, kind string, oldID uint, owner string) (uint, error) {
+	newID, ok := ids[oldID]
+	if !ok {
+		return 0, fmt.Errorf("数据完整性错误：%s 引用的%s %d 不存在", owner, kind, oldID)
+	}
+	return newID, nil
+}
+
+func deletedAtOf(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
+	}
+	t := deletedAt.Time
+	return &t
+}
+
+func gormDeletedAt(t *time.Time) gorm.DeletedAt {
+	if t == nil {
+		return gorm.DeletedAt{}
+	}
+	return gorm.DeletedAt{Time: *t, Valid: true}
+}
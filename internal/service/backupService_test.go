@@ -0,0 +1,262 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/archive"
+	"go-my-blog/pkg/storage"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBackupStore 按写入顺序从 100 开始分配新ID，便于区分新旧ID
+type fakeBackupStore struct {
+	nextID    uint
+	created   []interface{}
+	paths     map[uint]string
+	postTags  [][2]uint
+	postMedia [][2]uint
+}
+
+func newFakeBackupStore() *fakeBackupStore {
+	return &fakeBackupStore{nextID: 100, paths: make(map[uint]string)}
+}
+
+func (fs *fakeBackupStore) Create(ctx context.Context, value interface{}) error {
+	if id := reflect.ValueOf(value).Elem().FieldByName("ID"); id.IsValid() {
+		id.SetUint(uint64(fs.nextID))
+		fs.nextID++
+	}
+	fs.created = append(fs.created, value)
+	return nil
+}
+
+func (fs *fakeBackupStore) UpdateCommentPath(ctx context.Context, id uint, path string) error {
+	fs.paths[id] = path
+	return nil
+}
+
+func (fs *fakeBackupStore) CreatePostTag(ctx context.Context, postID uint, tagID uint) error {
+	fs.postTags = append(fs.postTags, [2]uint{postID, tagID})
+	return nil
+}
+
+func (fs *fakeBackupStore) CreatePostMedia(ctx context.Context, postID uint, mediaID uint) error {
+	fs.postMedia = append(fs.postMedia, [2]uint{postID, mediaID})
+	return nil
+}
+
+// backupFile 归档中的一个 JSON Lines 文件
+type backupFile struct {
+	name    string
+	records []interface{}
+}
+
+// buildBackup 按导出顺序生成内存中的归档，媒体文件（key 和内容）写在最后
+func buildBackup(t *testing.T, files []backupFile, media ...[2]string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	aw := archive.NewWriter(&buf)
+	if err := aw.WriteJSON(backupManifestFile, DTO.BackupManifestDTO{Format: DTO.BackupFormat, Version: DTO.BackupVersion, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		lw, err := aw.Lines(file.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, record := range file.records {
+			if err := lw.Write(record); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := lw.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range media {
+		if err := aw.WriteFile(backupMediaFilesPrefix+file[0], []byte(file[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := aw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+// loadBackup 读取清单后把归档写入 fake 存储，返回恢复过程和写入的媒体目录
+func loadBackup(t *testing.T, buf *bytes.Buffer) (*backupRestore, *fakeBackupStore, string, error) {
+	t.Helper()
+	setPostConfig(t)
+	config.Conf.Media.Storage = storage.DriverLocal
+	root := t.TempDir()
+
+	ar, err := archive.NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ar.Close()
+	if err := readBackupManifest(ar); err != nil {
+		t.Fatal(err)
+	}
+	store := newFakeBackupStore()
+	restore := newBackupRestore(storage.NewLocalStorage(root, ""))
+	restore.repo = store
+	return restore, store, root, restore.load(context.Background(), ar)
+}
+
+func createdOf[T any](store *fakeBackupStore) []*T {
+	var values []*T
+	for _, value := range store.created {
+		if v, ok := value.(*T); ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func TestBackupRestoreRemapsIDs(t *testing.T) {
+	// 旧ID故意不连续，恢复后全部引用改写为新ID
+	buf := buildBackup(t, []backupFile{
+		{backupUsersFile, []interface{}{
+			DTO.BackupUserDTO{ID: 7, Username: "alice", DeletionScheduledAt: &time.Time{}, DeletionCommentMode: "anonymize"},
+			DTO.BackupUserDTO{ID: 3, Username: "bob"},
+		}},
+		{backupMediaFile, []interface{}{DTO.BackupMediaDTO{ID: 40, UserID: 7, StorageKey: "a/b.png"}}},
+		{backupTagsFile, []interface{}{DTO.BackupTagDTO{ID: 9, Name: "go"}}},
+		{backupPostsFile, []interface{}{DTO.BackupPostDTO{ID: 20, UserID: 3, Title: "t", Content: "c", ContentFormat: "plain", CoverMediaID: uintPtr(40)}}},
+		{backupPostTagsFile, []interface{}{DTO.BackupPostTagDTO{PostID: 20, TagID: 9}}},
+		{backupPostMediaFile, []interface{}{DTO.BackupPostMediaDTO{PostID: 20, MediaID: 40}}},
+		{backupCommentsFile, []interface{}{
+			DTO.BackupCommentDTO{ID: 50, PostID: 20, UserID: uintPtr(7), Content: "root", ContentFormat: "plain"},
+			DTO.BackupCommentDTO{ID: 51, PostID: 20, ParentID: uintPtr(50), Content: "reply", ContentFormat: "plain", SpamLabel: "ham"},
+			DTO.BackupCommentDTO{ID: 52, PostID: 20, ParentID: uintPtr(51), Content: "nested", ContentFormat: "plain"},
+		}},
+		{backupRevisionsFile, []interface{}{DTO.BackupCommentRevisionDTO{ID: 1, CommentID: 51, EditorID: 3, Content: "old", ContentFormat: "markdown"}}},
+		{backupRedirectsFile, []interface{}{DTO.BackupRedirectDTO{FromPath: "/old", PostID: 20}}},
+		{backupSpamTokensFile, []interface{}{DTO.BackupSpamTokenDTO{Token: "__doc_count__", SpamCount: 2, HamCount: 5}}},
+	}, [2]string{"a/b.png", "png"})
+
+	restore, store, root, err := loadBackup(t, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 新ID按写入顺序分配：用户 100、101，媒体 102，标签 103，文章 104，评论 105~107
+	users := createdOf[model.User](store)
+	if len(users) != 2 || restore.users[7] != 100 || restore.users[3] != 101 {
+		t.Fatalf("用户映射 = %v", restore.users)
+	}
+	if users[0].DeletionScheduledAt == nil || users[0].DeletionCommentMode != "anonymize" {
+		t.Error("注销计划应随用户恢复")
+	}
+	posts := createdOf[model.Post](store)
+	if len(posts) != 1 || posts[0].UserID != 101 || posts[0].CoverMediaID == nil || *posts[0].CoverMediaID != 102 {
+		t.Fatalf("文章 = %+v", posts)
+	}
+	if !reflect.DeepEqual(store.postTags, [][2]uint{{104, 103}}) || !reflect.DeepEqual(store.postMedia, [][2]uint{{104, 102}}) {
+		t.Errorf("关联 = %v %v", store.postTags, store.postMedia)
+	}
+
+	comments := createdOf[model.Comment](store)
+	if len(comments) != 3 {
+		t.Fatalf("评论数 = %d", len(comments))
+	}
+	if *comments[0].UserID != 100 || *comments[1].ParentID != 105 || *comments[2].ParentID != 106 || comments[1].SpamLabel != "ham" {
+		t.Errorf("评论引用未改写: %+v", comments)
+	}
+	// 物化路径和深度按新ID重建
+	wantPaths := map[uint]string{
+		105: model.CommentPathSegment(105),
+		106: model.CommentPathSegment(105) + model.CommentPathSegment(106),
+		107: model.CommentPathSegment(105) + model.CommentPathSegment(106) + model.CommentPathSegment(107),
+	}
+	if !reflect.DeepEqual(store.paths, wantPaths) {
+		t.Errorf("物化路径 = %v", store.paths)
+	}
+	for depth, comment := range comments {
+		if comment.Depth != depth {
+			t.Errorf("评论 %d 深度 = %d, want %d", comment.ID, comment.Depth, depth)
+		}
+	}
+
+	revisions := createdOf[model.CommentRevision](store)
+	if len(revisions) != 1 || revisions[0].CommentID != 106 || revisions[0].EditorID != 101 || revisions[0].ContentFormat != "markdown" {
+		t.Errorf("历史版本 = %+v", revisions)
+	}
+	redirects := createdOf[model.Redirect](store)
+	if len(redirects) != 1 || redirects[0].PostID != 104 {
+		t.Errorf("重定向 = %+v", redirects)
+	}
+	tokens := createdOf[model.SpamToken](store)
+	if len(tokens) != 1 || tokens[0].SpamCount != 2 || tokens[0].HamCount != 5 {
+		t.Errorf("分类器词频 = %+v", tokens)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "a/b.png")); err != nil || string(data) != "png" {
+		t.Errorf("媒体文件未恢复: %v", err)
+	}
+	if restore.report.Counts[backupCommentsFile] != 3 || restore.report.Counts["media"] != 1 {
+		t.Errorf("统计 = %v", restore.report.Counts)
+	}
+}
+
+func TestBackupRestoreIntegrityErrors(t *testing.T) {
+	user := DTO.BackupUserDTO{ID: 1, Username: "alice"}
+	post := DTO.BackupPostDTO{ID: 2, UserID: 1, Title: "t", Content: "c", ContentFormat: "plain"}
+	tests := []struct {
+		name  string
+		files []backupFile
+		want  string
+	}{
+		{"引用不存在的用户", []backupFile{
+			{backupPostsFile, []interface{}{post}},
+		}, "用户 1 不存在"},
+		{"重复的ID", []backupFile{
+			{backupUsersFile, []interface{}{user, user}},
+		}, "用户 1 重复"},
+		{"子评论在父评论之前", []backupFile{
+			{backupUsersFile, []interface{}{user}},
+			{backupPostsFile, []interface{}{post}},
+			{backupCommentsFile, []interface{}{
+				DTO.BackupCommentDTO{ID: 6, PostID: 2, ParentID: uintPtr(5), Content: "reply", ContentFormat: "plain"},
+				DTO.BackupCommentDTO{ID: 5, PostID: 2, Content: "root", ContentFormat: "plain"},
+			}},
+		}, "父评论 5 不存在"},
+	}
+	for _, tt := range tests {
+		_, _, _, err := loadBackup(t, buildBackup(t, tt.files))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestBackupRestoreCleansUpMedia(t *testing.T) {
+	// a.png 已写入存储后遇到没有媒体记录的文件，恢复失败，已写入的文件应被删除（数据库部分由事务回滚）
+	buf := buildBackup(t, []backupFile{
+		{backupUsersFile, []interface{}{DTO.BackupUserDTO{ID: 1, Username: "alice"}}},
+		{backupMediaFile, []interface{}{DTO.BackupMediaDTO{ID: 1, UserID: 1, StorageKey: "a.png"}}},
+	}, [2]string{"a.png", "png"}, [2]string{"unknown.png", "png"})
+
+	restore, _, root, err := loadBackup(t, buf)
+	if err == nil || !strings.Contains(err.Error(), "unknown.png") {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "a.png")); err != nil {
+		t.Fatalf("a.png 应已写入存储: %v", err)
+	}
+	restore.cleanup()
+	for _, key := range []string{"a.png", "unknown.png"} {
+		if _, err := os.Stat(filepath.Join(root, key)); !os.IsNotExist(err) {
+			t.Errorf("恢复失败后存储中不应留下 %s", key)
+		}
+	}
+}
@@ -1,12 +1,19 @@
 package service
 
 import (
-	"database/sql"
+	"context"
 	"errors"
+	"fmt"
+	"go-my-blog/config"
 	"go-my-blog/internal/DTO"
 	"go-my-blog/internal/model"
 	"go-my-blog/internal/repo"
 	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/pow"
+	"go-my-blog/pkg/render"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -15,90 +22,707 @@ type CommentService struct {
 	commentRepo *repo.CommentRepository
 	userRepo    *repo.UserRepository
 	postRepo    *repo.PostRepository
+	spamService *SpamService
+	uow         *repo.UnitOfWork
+	// 已使用的游客评论题目，防止一次求解重复提交
+	challengeGuard *pow.Guard
 }
 
 func (s CommentService) GetCommentRepo() *repo.CommentRepository {
 	return s.commentRepo
 }
 
-func NewCommentService(commentRepo *repo.CommentRepository, userRepo *repo.UserRepository, postRepo *repo.PostRepository) *CommentService {
+func NewCommentService(commentRepo *repo.CommentRepository, userRepo *repo.UserRepository, postRepo *repo.PostRepository, spamService *SpamService, uow *repo.UnitOfWork) *CommentService {
 	return &CommentService{
-		commentRepo: commentRepo,
-		userRepo:    userRepo,
-		postRepo:    postRepo,
+		commentRepo:    commentRepo,
+		userRepo:       userRepo,
+		postRepo:       postRepo,
+		spamService:    spamService,
+		uow:            uow,
+		challengeGuard: pow.NewGuard(),
 	}
 }
 
-func (cs CommentService) DeleteComment(commentId uint, userId uint) error {
+func (cs CommentService) DeleteComment(ctx context.Context, commentId uint, userId uint) error {
 	// 根据ID从数据库中获取文章信息
-	comment, err := cs.commentRepo.GetById(commentId)
+	comment, err := cs.commentRepo.GetById(ctx, commentId)
 	if err != nil {
-		// 检查错误是否为"未找到行"的错误
-		if errors.Is(err, sql.ErrNoRows) {
-			logger.Error("评论不存在", zap.Error(err))
-			return err
-		}
-		// 其他数据库错误处理
 		logger.Error("评论查询失败", zap.Error(err))
-		return err
+		return notFound(err, ErrCommentNotFound)
+	}
+	if comment.IsDeleted {
+		logger.Error("评论已删除", zap.Uint("comment_id", commentId))
+		return ErrCommentDeleted
 	}
 
 	// 验证请求删除的用户是否为评论作者
-	if userId != comment.UserID {
+	if !comment.IsAuthoredBy(userId) {
 		logger.Error("登录用户非评论作者，不允许删除评论")
-		return errors.New("登录用户非评论作者，不允许删除评论")
+		return ErrCommentDeleteForbidden
 	}
 
-	err = cs.commentRepo.DeleteById(commentId)
-	if err != nil {
-		logger.Error("删除评论异常", zap.Error(err))
-		return err
+	// 有回复的评论只清空内容保留占位，避免回复树断裂
+	if comment.ReplyCount > 0 {
+		if err = cs.commentRepo.MarkDeleted(ctx, commentId); err != nil {
+			logger.Error("删除评论异常", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+
+	// 删除评论与扣减、清理父评论在同一事务中完成，避免回复数与回复树不一致
+	return cs.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		if err := repos.Comments.DeleteById(ctx, commentId); err != nil {
+			logger.Error("删除评论异常", zap.Error(err))
+			return err
+		}
+		// 父评论的回复数只统计审核通过的回复
+		if comment.Status != model.CommentStatusApproved {
+			return nil
+		}
+		return detachFromParent(ctx, repos.Comments, comment)
+	})
+}
+
+// detachFromParent 评论被真正删除后，扣减父评论的回复数；
+// 若父评论是已删除占位且不再有回复，则一并删除，逐级向上清理
+func detachFromParent(ctx context.Context, commentRepo *repo.CommentRepository, comment *model.Comment) error {
+	for comment.ParentID != nil {
+		parentID := *comment.ParentID
+		if err := commentRepo.IncrReplyCount(ctx, parentID, -1); err != nil {
+			logger.Error("扣减父评论回复数失败", zap.Error(err))
+			return err
+		}
+		parent, err := commentRepo.GetById(ctx, parentID)
+		if err != nil {
+			logger.Error("父评论查询失败", zap.Error(err))
+			return err
+		}
+		if !parent.IsDeleted || parent.ReplyCount > 0 {
+			return nil
+		}
+		if err := commentRepo.DeleteById(ctx, parentID); err != nil {
+			logger.Error("清理已删除占位评论失败", zap.Error(err))
+			return err
+		}
+		comment = parent
 	}
 	return nil
 }
 
-func (cs CommentService) CreateComment(userID uint, d *DTO.CreateCommentDTO) (*DTO.CreateCommentDTO, error) {
-	_, err := cs.postRepo.GetById(d.PostID)
+// UpdateComment 编辑评论：作者只能在编辑窗口内修改自己的评论，版主不受时间和作者限制；
+// 每次编辑前保存旧内容作为历史版本。作者修改内容后重新检测垃圾评论并按文章评论策略重新审核
+func (cs CommentService) UpdateComment(ctx context.Context, d *DTO.UpdateCommentDTO) (*DTO.CommentDetailDTO, error) {
+	comment, err := cs.commentRepo.GetById(ctx, d.ID)
+	if err != nil {
+		logger.Error("评论不存在", zap.Error(err))
+		return nil, notFound(err, ErrCommentNotFound)
+	}
+	if comment.IsDeleted {
+		logger.Error("评论已删除，不允许编辑", zap.Uint("comment_id", d.ID))
+		return nil, ErrCommentEditDeleted
+	}
+
+	editor, err := cs.userRepo.FindById(ctx, d.UserID)
+	if err != nil {
+		logger.Error("用户查询失败", zap.Error(err))
+		return nil, notFound(err, ErrUserNotFound)
+	}
+	if !editor.IsModerator() {
+		if !comment.IsAuthoredBy(editor.ID) {
+			logger.Error("登录用户非评论作者，不允许编辑评论")
+			return nil, ErrCommentEditForbidden
+		}
+		if time.Since(comment.CreatedAt) > config.Conf.Comment.GetEditWindow() {
+			logger.Error("已超过评论可编辑时间", zap.Uint("comment_id", d.ID))
+			minutes := config.Conf.Comment.EditWindowMinutes
+			return nil, ErrCommentEditExpired.
+				WithMessage(fmt.Sprintf("评论发表超过%d分钟，不允许编辑", minutes)).
+				WithDetails(map[string]interface{}{"edit_window_minutes": minutes})
+		}
+	}
+
+	contentFormat := comment.ContentFormat
+	if d.ContentFormat != "" {
+		contentFormat = d.ContentFormat
+	}
+	if comment.Content == d.Content && comment.ContentFormat == contentFormat {
+		commentDetailDTO := toCommentDetailDTO(*comment)
+		return &commentDetailDTO, nil
+	}
+
+	// 与发表评论一致，版主编辑不做检测；否则编辑可以把已通过的评论改成垃圾内容
+	previousStatus := comment.Status
+	if !editor.IsModerator() && comment.Content != d.Content {
+		post, err := cs.postRepo.GetById(ctx, comment.PostID)
+		if err != nil {
+			logger.Error("文章不存在", zap.Error(err))
+			return nil, notFound(err, ErrPostNotFound)
+		}
+		verdict := cs.spamService.Check(ctx, &DTO.SpamCheckDTO{UserID: editor.ID, Email: editor.Email, IP: d.IP, Content: d.Content})
+		comment.SpamScore = verdict.Score
+		comment.Status = editedCommentStatus(comment.Status, effectiveCommentPolicy(post), verdict)
+	}
+
+	// 历史版本与新内容一起提交，不会出现只有其中之一的编辑
+	revision := model.CommentRevision{CommentID: comment.ID, Content: comment.Content, ContentFormat: comment.ContentFormat, EditorID: editor.ID}
+	now := time.Now()
+	comment.Content = d.Content
+	comment.ContentFormat = contentFormat
+	comment.ContentHTML = renderContent(comment.Content, comment.ContentFormat)
+	comment.RenderVersion = render.Version
+	err = cs.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		// 事务重试时从未写入的副本重新开始
+		created := revision
+		if err := repos.Comments.CreateRevision(ctx, &created); err != nil {
+			logger.Error("评论历史版本保存失败", zap.Error(err))
+			return err
+		}
+		if err := repos.Comments.UpdateContent(ctx, comment, now); err != nil {
+			logger.Error("评论更新失败", zap.Error(err))
+			return err
+		}
+		if comment.ParentID != nil {
+			return syncParentReplyCount(ctx, repos.Comments, *comment.ParentID, previousStatus, comment.Status)
+		}
+		return nil
+	})
 	if err != nil {
-		logger.Error("文章不存在", zap.Error(err))
 		return nil, err
 	}
-	var comment = &model.Comment{PostID: d.PostID, UserID: userID, Content: d.Content}
-	commentResult, err := cs.commentRepo.Create(comment)
+	comment.EditedAt = &now
+	commentDetailDTO := toCommentDetailDTO(*comment)
+	return &commentDetailDTO, nil
+}
+
+// editedCommentStatus 作者编辑后的审核状态：需要审核的文章中已通过的评论重新进入待审核，
+// 检测结果为待审核或垃圾评论时相应降级；编辑不会让未通过审核的评论变为公开
+func editedCommentStatus(current string, policy string, verdict *DTO.SpamVerdictDTO) string {
+	status := current
+	if status == model.CommentStatusApproved && policy == model.CommentPolicyModerated {
+		status = model.CommentStatusPending
+	}
+	switch verdict.Status {
+	case model.CommentStatusSpam:
+		status = model.CommentStatusSpam
+	case model.CommentStatusPending:
+		if status == model.CommentStatusApproved {
+			status = model.CommentStatusPending
+		}
+	}
+	return status
+}
+
+func (cs CommentService) CreateComment(ctx context.Context, userID uint, d *DTO.CreateCommentDTO) (*DTO.CreateCommentDTO, error) {
+	post, err := cs.postRepo.GetById(ctx, d.PostID)
+	if err != nil {
+		logger.Error("文章不存在", zap.Error(err))
+		return nil, notFound(err, ErrPostNotFound)
+	}
+
+	user, err := cs.userRepo.FindById(ctx, userID)
+	if err != nil {
+		logger.Error("用户查询失败", zap.Error(err))
+		return nil, notFound(err, ErrUserNotFound)
+	}
+
+	// 按文章实际生效的评论策略决定能否评论及初始审核状态
+	status := model.CommentStatusApproved
+	switch effectiveCommentPolicy(post) {
+	case model.CommentPolicyClosed:
+		logger.Error("文章已关闭评论", zap.Uint("post_id", d.PostID))
+		return nil, ErrCommentsClosed
+	case model.CommentPolicyModerated:
+		status = model.CommentStatusPending
+	}
+
+	// 垃圾过滤：得分过高直接判为垃圾，得分偏高进入审核队列；版主的评论无需审核也不做过滤
+	var spamScore float64
+	if user.IsModerator() {
+		status = model.CommentStatusApproved
+	} else {
+		verdict := cs.spamService.Check(ctx, &DTO.SpamCheckDTO{UserID: userID, Email: user.Email, IP: d.IP, Content: d.Content})
+		spamScore = verdict.Score
+		if verdict.Status != "" {
+			status = verdict.Status
+		}
+	}
+	var comment = &model.Comment{PostID: d.PostID, UserID: &userID, Content: d.Content, ContentFormat: d.ContentFormat, Status: status, SpamScore: spamScore, IP: d.IP}
+
+	commentResult, err := cs.saveComment(ctx, comment, d.ParentID)
 	if err != nil {
-		logger.Error("评论创建失败", zap.Error(err))
 		return nil, err
 	}
 
-	var resultDTO = &DTO.CreateCommentDTO{PostID: commentResult.PostID, Content: commentResult.Content}
+	var resultDTO = &DTO.CreateCommentDTO{
+		ID:            commentResult.ID,
+		PostID:        commentResult.PostID,
+		Content:       commentResult.Content,
+		ContentFormat: commentResult.ContentFormat,
+		ContentHTML:   commentResult.ContentHTML,
+		ParentID:      d.ParentID,
+		Depth:         commentResult.Depth,
+		Status:        commentResult.Status,
+	}
 
 	return resultDTO, nil
 
 }
 
-func (cs CommentService) CommentList(postId uint) (*[]DTO.CommentDetailDTO, error) {
-	_, err := cs.postRepo.GetById(postId)
+// IssueGuestChallenge 签发游客评论的工作量证明题目
+func (cs CommentService) IssueGuestChallenge() (*DTO.GuestChallengeDTO, error) {
+	guestConf := config.Conf.Guest
+	if !guestConf.Enabled {
+		logger.Warn("游客评论未开启")
+		return nil, ErrGuestCommentDisabled
+	}
+
+	challenge, err := pow.Issue([]byte(guestConf.Secret), guestConf.Difficulty, time.Duration(guestConf.ChallengeTTLMinutes)*time.Minute)
+	if err != nil {
+		logger.Error("工作量证明题目签发失败", zap.Error(err))
+		return nil, err
+	}
+	return &DTO.GuestChallengeDTO{Token: challenge.Token, Difficulty: challenge.Difficulty, ExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// CreateGuestComment 游客评论：校验工作量证明后保存，不关联用户，且一律进入审核队列（垃圾评论除外）
+func (cs CommentService) CreateGuestComment(ctx context.Context, d *DTO.CreateGuestCommentDTO) (*DTO.CreateCommentDTO, error) {
+	guestConf := config.Conf.Guest
+	if !guestConf.Enabled {
+		logger.Warn("游客评论未开启")
+		return nil, ErrGuestCommentDisabled
+	}
+	// 先校验文章再消耗题目：文章不存在或已关闭评论时，客户端可以用同一道题改投其他文章
+	post, err := cs.postRepo.GetById(ctx, d.PostID)
 	if err != nil {
 		logger.Error("文章不存在", zap.Error(err))
+		return nil, notFound(err, ErrPostNotFound)
+	}
+	if post.Status != model.PostStatusPublished || effectiveCommentPolicy(post) == model.CommentPolicyClosed {
+		logger.Error("文章已关闭评论", zap.Uint("post_id", d.PostID))
+		return nil, ErrCommentsClosed
+	}
+
+	if err := cs.challengeGuard.Verify([]byte(guestConf.Secret), d.Challenge, d.Solution); err != nil {
+		logger.Warn("游客评论工作量证明校验失败", zap.Error(err), zap.String("ip", d.IP))
+		return nil, ErrChallengeFailed.
+			WithMessage("工作量证明校验失败：" + err.Error()).
+			WithDetails(map[string]interface{}{"reason": err.Error()})
+	}
+
+	status := model.CommentStatusPending
+	verdict := cs.spamService.Check(ctx, &DTO.SpamCheckDTO{Email: d.Email, IP: d.IP, Content: d.Content})
+	if verdict.Status == model.CommentStatusSpam {
+		status = model.CommentStatusSpam
+	}
+	var comment = &model.Comment{
+		PostID:        d.PostID,
+		Content:       d.Content,
+		ContentFormat: d.ContentFormat,
+		Status:        status,
+		SpamScore:     verdict.Score,
+		IP:            d.IP,
+		GuestName:     d.Name,
+		GuestEmail:    d.Email,
+		GuestWebsite:  d.Website,
+	}
+
+	commentResult, err := cs.saveComment(ctx, comment, d.ParentID)
+	if err != nil {
 		return nil, err
 	}
 
-	comments, _, err := cs.commentRepo.ListComments(DTO.ListCommentDTO{PostId: postId})
+	return &DTO.CreateCommentDTO{
+		ID:            commentResult.ID,
+		PostID:        commentResult.PostID,
+		Content:       commentResult.Content,
+		ContentFormat: commentResult.ContentFormat,
+		ContentHTML:   commentResult.ContentHTML,
+		ParentID:      d.ParentID,
+		Depth:         commentResult.Depth,
+		Status:        commentResult.Status,
+	}, nil
+}
+
+// saveComment 保存评论：回复时校验父评论并计算深度，创建后回填物化路径、更新父评论回复数
+func (cs CommentService) saveComment(ctx context.Context, comment *model.Comment, parentID uint) (*model.Comment, error) {
+	if comment.ContentFormat == "" {
+		comment.ContentFormat = render.FormatPlain
+	}
+	comment.ContentHTML = renderContent(comment.Content, comment.ContentFormat)
+	comment.RenderVersion = render.Version
+
+	var parent *model.Comment
+	if parentID != 0 {
+		var err error
+		parent, err = cs.commentRepo.GetById(ctx, parentID)
+		if err != nil {
+			logger.Error("父评论不存在", zap.Error(err))
+			return nil, notFound(err, ErrCommentNotFound)
+		}
+		if parent.PostID != comment.PostID {
+			logger.Error("父评论不属于该文章", zap.Uint("parent_id", parentID), zap.Uint("post_id", comment.PostID))
+			return nil, ErrParentMismatch
+		}
+		if parent.IsDeleted || parent.Status != model.CommentStatusApproved {
+			logger.Error("不能回复已删除或未公开的评论", zap.Uint("parent_id", parentID))
+			return nil, ErrParentUnavailable
+		}
+		if parent.Depth+1 >= config.Conf.Comment.MaxDepth {
+			maxDepth := config.Conf.Comment.MaxDepth
+			logger.Error("回复层级超过上限", zap.Int("max_depth", maxDepth))
+			return nil, ErrReplyTooDeep.
+				WithMessage(fmt.Sprintf("回复层级不能超过%d层", maxDepth)).
+				WithDetails(map[string]interface{}{"max_depth": maxDepth})
+		}
+		comment.ParentID = &parent.ID
+		comment.Depth = parent.Depth + 1
+	}
+
+	var commentResult *model.Comment
+	err := cs.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		// 事务重试时从未写入的副本重新开始
+		created := *comment
+		if _, err := repos.Comments.Create(ctx, &created); err != nil {
+			logger.Error("评论创建失败", zap.Error(err))
+			return err
+		}
+
+		// 物化路径包含自身ID，创建后回填
+		created.Path = model.CommentPathSegment(created.ID)
+		if parent != nil {
+			created.Path = parent.Path + created.Path
+		}
+		if err := repos.Comments.UpdatePath(ctx, created.ID, created.Path); err != nil {
+			logger.Error("评论路径回填失败", zap.Error(err))
+			return err
+		}
+		if parent != nil && created.Status == model.CommentStatusApproved {
+			if err := repos.Comments.IncrReplyCount(ctx, parent.ID, 1); err != nil {
+				logger.Error("父评论回复数更新失败", zap.Error(err))
+				return err
+			}
+		}
+		commentResult = &created
+		return nil
+	})
 	if err != nil {
-		logger.Error("评论列表查询失败", zap.Error(err))
 		return nil, err
 	}
+	return commentResult, nil
+}
 
-	var commentDetailsDTO []DTO.CommentDetailDTO
-	for _, comment := range *comments {
-		var commentDetailDTO DTO.CommentDetailDTO
-		commentDetailDTO.ID = comment.ID
-		commentDetailDTO.Content = comment.Content
-		commentDetailDTO.UserID = comment.UserID
-		commentDetailDTO.CreatedAt = comment.CreatedAt.Format("2006-01-02 15:04:05")
-		commentDetailDTO.UpdatedAt = comment.UpdatedAt.Format("2006-01-02 15:04:05")
-		commentDetailsDTO = append(commentDetailsDTO, commentDetailDTO)
+// CommentList 文章下审核通过的评论，按回复关系组织成树；草稿的评论只有作者本人可见
+func (cs CommentService) CommentList(ctx context.Context, postId uint, userID uint) (*[]DTO.CommentDetailDTO, error) {
+	if err := cs.checkPostVisible(ctx, postId, userID); err != nil {
+		return nil, err
 	}
 
+	comments, err := cs.commentRepo.ListByPostId(ctx, postId)
+	if err != nil {
+		logger.Error("评论列表查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	commentDetailsDTO := buildCommentTree(comments)
 	return &commentDetailsDTO, nil
 }
+
+// CommentThread 获取评论所在的整棵回复树（从根评论开始）；根评论未审核通过或所属文章对当前用户不可见时按不存在处理
+func (cs CommentService) CommentThread(ctx context.Context, commentId uint, userID uint) (*DTO.CommentDetailDTO, error) {
+	comment, err := cs.commentRepo.GetById(ctx, commentId)
+	if err != nil {
+		logger.Error("评论不存在", zap.Error(err))
+		return nil, notFound(err, ErrCommentNotFound)
+	}
+	if err := cs.checkPostVisible(ctx, comment.PostID, userID); err != nil {
+		if errors.Is(err, ErrPostNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	// 物化路径的第一段即根评论
+	rootPath := model.CommentPathSegment(comment.ID)
+	if comment.Path != "" {
+		rootPath = comment.Path[:strings.Index(comment.Path, "/")+1]
+	}
+	rootID, err := strconv.ParseUint(strings.TrimSuffix(rootPath, "/"), 10, 0)
+	if err != nil {
+		logger.Error("评论物化路径格式错误", zap.Uint("comment_id", commentId), zap.String("path", comment.Path))
+		return nil, err
+	}
+	comments, err := cs.commentRepo.ListByPathPrefix(ctx, rootPath)
+	if err != nil {
+		logger.Error("评论回复树查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	// 根评论未通过审核时，buildCommentTree 会把它的回复提升为根，不能当作这棵树返回
+	tree := buildCommentTree(comments)
+	for i := range tree {
+		if tree[i].ID == uint(rootID) {
+			return &tree[i], nil
+		}
+	}
+	logger.Warn("根评论不可见", zap.Uint("comment_id", commentId), zap.Uint64("root_id", rootID))
+	return nil, ErrCommentNotFound
+}
+
+// checkPostVisible 文章不存在，或是其他用户的草稿时返回 ErrPostNotFound
+func (cs CommentService) checkPostVisible(ctx context.Context, postId uint, userID uint) error {
+	post, err := cs.postRepo.GetById(ctx, postId)
+	if err != nil {
+		logger.Error("文章不存在", zap.Error(err))
+		return notFound(err, ErrPostNotFound)
+	}
+	if !postVisibleTo(post, userID) {
+		logger.Warn("非作者本人查看草稿的评论", zap.Uint("user_id", userID), zap.Uint("post_id", postId))
+		return ErrPostNotFound
+	}
+	return nil
+}
+
+// ModerationQueue 审核队列：按状态分页查询评论，仅版主可用
+func (cs CommentService) ModerationQueue(ctx context.Context, moderatorID uint, d *DTO.ModerationQueueDTO) (*DTO.ModerationCommentListDTO, error) {
+	if err := cs.requireModerator(ctx, moderatorID); err != nil {
+		return nil, err
+	}
+
+	comments, total, err := cs.commentRepo.ListByStatus(ctx, d.Status, d.PageNum, d.PageSize)
+	if err != nil {
+		logger.Error("审核队列查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	moderationComments := make([]DTO.ModerationCommentDTO, 0, len(comments))
+	for _, comment := range comments {
+		moderationComment := DTO.ModerationCommentDTO{
+			ID:        comment.ID,
+			PostID:    comment.PostID,
+			UserID:    userIDOf(comment),
+			GuestName: comment.GuestName,
+			Content:   comment.Content,
+			Status:    comment.Status,
+			CreatedAt: comment.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+		if comment.ParentID != nil {
+			moderationComment.ParentID = *comment.ParentID
+		}
+		moderationComments = append(moderationComments, moderationComment)
+	}
+
+	return &DTO.ModerationCommentListDTO{
+		Comments: moderationComments,
+		Total:    total,
+		PageNum:  d.PageNum,
+		PageSize: d.PageSize,
+	}, nil
+}
+
+// moderateActionStatus 审核动作到目标状态的映射
+// spam/ham 在更新状态的同时训练垃圾评论分类器
+var moderateActionStatus = map[string]string{
+	"approve": model.CommentStatusApproved,
+	"reject":  model.CommentStatusRejected,
+	"spam":    model.CommentStatusSpam,
+	"ham":     model.CommentStatusApproved,
+}
+
+// ModerateComments 批量审核评论，仅版主可用；公开状态变化时同步父评论的回复数
+func (cs CommentService) ModerateComments(ctx context.Context, d *DTO.ModerateCommentsDTO) (*DTO.ModerateResultDTO, error) {
+	if err := cs.requireModerator(ctx, d.ModeratorID); err != nil {
+		return nil, err
+	}
+	target, ok := moderateActionStatus[d.Action]
+	if !ok {
+		logger.Error("不支持的审核动作", zap.String("action", d.Action))
+		return nil, ErrModerateActionInvalid.
+			WithMessage("不支持的审核动作：" + d.Action).
+			WithDetails(map[string]interface{}{"action": d.Action})
+	}
+
+	training := d.Action == model.SpamLabelSpam || d.Action == model.SpamLabelHam
+
+	comments, err := cs.commentRepo.GetByIds(ctx, d.IDs)
+	if err != nil {
+		logger.Error("待审核评论查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	// 一批评论的状态与父评论回复数在同一事务中更新，要么全部生效要么全部不生效
+	var result *DTO.ModerateResultDTO
+	var updated []model.Comment
+	err = cs.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		result = &DTO.ModerateResultDTO{Updated: []uint{}, Skipped: []uint{}}
+		updated = updated[:0]
+		for _, comment := range comments {
+			// spam/ham 会训练分类器：状态未变但训练标签不同时（如把误判的评论标记为正常）仍需处理
+			if comment.IsDeleted || (comment.Status == target && (!training || comment.SpamLabel == d.Action)) {
+				result.Skipped = append(result.Skipped, comment.ID)
+				continue
+			}
+			if err := repos.Comments.UpdateStatus(ctx, comment.ID, target); err != nil {
+				logger.Error("评论审核状态更新失败", zap.Error(err))
+				return err
+			}
+			if comment.ParentID != nil {
+				if err := syncParentReplyCount(ctx, repos.Comments, *comment.ParentID, comment.Status, target); err != nil {
+					return err
+				}
+			}
+			result.Updated = append(result.Updated, comment.ID)
+			updated = append(updated, comment)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 训练在审核提交后进行，失败不影响审核结果；记录训练标签，改判时撤销上一次训练，避免重复计数
+	if training {
+		for _, comment := range updated {
+			if comment.SpamLabel == d.Action {
+				continue
+			}
+			if err := cs.spamService.Train(ctx, comment.Content, comment.SpamLabel, d.Action); err != nil {
+				logger.Warn("垃圾评论分类器训练失败", zap.Error(err))
+				continue
+			}
+			if err := cs.commentRepo.UpdateSpamLabel(ctx, comment.ID, d.Action); err != nil {
+				logger.Warn("评论训练标签记录失败", zap.Error(err))
+			}
+		}
+	}
+	found := make(map[uint]bool, len(comments))
+	for _, comment := range comments {
+		found[comment.ID] = true
+	}
+	for _, id := range d.IDs {
+		if !found[id] {
+			result.Skipped = append(result.Skipped, id)
+		}
+	}
+	logger.Info("评论批量审核完成", zap.Uint("moderator_id", d.ModeratorID), zap.String("action", d.Action), zap.Uints("updated", result.Updated))
+	return result, nil
+}
+
+// syncParentReplyCount 评论在公开与非公开之间切换时，调整父评论的回复数
+func syncParentReplyCount(ctx context.Context, commentRepo *repo.CommentRepository, parentID uint, from string, to string) error {
+	delta := 0
+	if from != model.CommentStatusApproved && to == model.CommentStatusApproved {
+		delta = 1
+	}
+	if from == model.CommentStatusApproved && to != model.CommentStatusApproved {
+		delta = -1
+	}
+	if delta == 0 {
+		return nil
+	}
+	if err := commentRepo.IncrReplyCount(ctx, parentID, delta); err != nil {
+		logger.Error("父评论回复数更新失败", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// requireModerator 校验用户是否为版主或管理员
+func (cs CommentService) requireModerator(ctx context.Context, userID uint) error {
+	user, err := cs.userRepo.FindById(ctx, userID)
+	if err != nil {
+		logger.Error("用户查询失败", zap.Error(err))
+		return notFound(err, ErrUserNotFound)
+	}
+	if !user.IsModerator() {
+		logger.Error("非版主用户无审核权限", zap.Uint("user_id", userID))
+		return ErrModeratorRequired
+	}
+	return nil
+}
+
+// effectiveCommentPolicy 计算文章实际生效的评论策略：
+// 文章未设置时继承全局配置；超过自动关闭天数后一律视为关闭
+func effectiveCommentPolicy(post *model.Post) string {
+	policy := post.CommentPolicy
+	if policy == model.CommentPolicyInherit {
+		policy = config.Conf.Comment.Policy
+	}
+	autoCloseDays := post.CommentAutoCloseDays
+	if autoCloseDays == 0 {
+		autoCloseDays = config.Conf.Comment.AutoCloseDays
+	}
+	if autoCloseDays > 0 && time.Since(post.CreatedAt) > time.Duration(autoCloseDays)*24*time.Hour {
+		return model.CommentPolicyClosed
+	}
+	return policy
+}
+
+// toCommentDetailDTO 评论模型转为详情DTO，已删除占位隐藏内容和作者
+func toCommentDetailDTO(comment model.Comment) DTO.CommentDetailDTO {
+	var commentDetailDTO DTO.CommentDetailDTO
+	commentDetailDTO.ID = comment.ID
+	commentDetailDTO.Content = comment.Content
+	commentDetailDTO.ContentFormat = comment.ContentFormat
+	commentDetailDTO.ContentHTML, _ = cachedHTML(comment.Content, comment.ContentFormat, comment.ContentHTML, comment.RenderVersion)
+	commentDetailDTO.UserID = userIDOf(comment)
+	commentDetailDTO.GuestName = comment.GuestName
+	commentDetailDTO.GuestWebsite = comment.GuestWebsite
+	if comment.ParentID != nil {
+		commentDetailDTO.ParentID = *comment.ParentID
+	}
+	commentDetailDTO.Depth = comment.Depth
+	commentDetailDTO.ReplyCount = comment.ReplyCount
+	commentDetailDTO.CreatedAt = comment.CreatedAt.Format("2006-01-02 15:04:05")
+	commentDetailDTO.UpdatedAt = comment.UpdatedAt.Format("2006-01-02 15:04:05")
+	if comment.EditedAt != nil {
+		commentDetailDTO.EditedAt = comment.EditedAt.Format("2006-01-02 15:04:05")
+	}
+	if comment.IsDeleted {
+		commentDetailDTO.Deleted = true
+		commentDetailDTO.Content = "该评论已删除"
+		commentDetailDTO.ContentHTML = ""
+		commentDetailDTO.UserID = 0
+		commentDetailDTO.GuestName = ""
+		commentDetailDTO.GuestWebsite = ""
+	}
+	return commentDetailDTO
+}
+
+// userIDOf 评论者ID，游客评论返回 0
+func userIDOf(comment model.Comment) uint {
+	if comment.UserID == nil {
+		return 0
+	}
+	return *comment.UserID
+}
+
+// buildCommentTree 将平铺的评论组装成回复树，父评论不在结果集中的评论作为根节点返回
+func buildCommentTree(comments []model.Comment) []DTO.CommentDetailDTO {
+	exists := make(map[uint]bool, len(comments))
+	for _, comment := range comments {
+		exists[comment.ID] = true
+	}
+
+	var roots []model.Comment
+	children := make(map[uint][]model.Comment)
+	for _, comment := range comments {
+		if comment.ParentID != nil && exists[*comment.ParentID] {
+			children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+			continue
+		}
+		roots = append(roots, comment)
+	}
+
+	var build func(nodes []model.Comment) []DTO.CommentDetailDTO
+	build = func(nodes []model.Comment) []DTO.CommentDetailDTO {
+		result := make([]DTO.CommentDetailDTO, 0, len(nodes))
+		for _, node := range nodes {
+			commentDetailDTO := toCommentDetailDTO(node)
+			commentDetailDTO.Replies = build(children[node.ID])
+			result = append(result, commentDetailDTO)
+		}
+		return result
+	}
+	return build(roots)
+}
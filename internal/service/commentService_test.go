@@ -0,0 +1,96 @@
+package service
+
+import (
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/render"
+	"testing"
+)
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+func testComment(id uint, parentID *uint) model.Comment {
+	return model.Comment{ID: id, ParentID: parentID, Content: "c", ContentFormat: render.FormatPlain, RenderVersion: render.Version}
+}
+
+func TestBuildCommentTree(t *testing.T) {
+	// 按物化路径排序：1 → 2 → 3，另有根评论 4
+	tree := buildCommentTree([]model.Comment{
+		testComment(1, nil),
+		testComment(2, uintPtr(1)),
+		testComment(3, uintPtr(2)),
+		testComment(4, nil),
+	})
+	if len(tree) != 2 || tree[0].ID != 1 || tree[1].ID != 4 {
+		t.Fatalf("根评论 = %+v", tree)
+	}
+	if len(tree[0].Replies) != 1 || tree[0].Replies[0].ID != 2 || len(tree[0].Replies[0].Replies) != 1 || tree[0].Replies[0].Replies[0].ID != 3 {
+		t.Errorf("回复树结构错误: %+v", tree[0])
+	}
+}
+
+func TestBuildCommentTreePromotesOrphans(t *testing.T) {
+	// 根评论 1 未审核通过时不在结果中，它的回复被提升为根；CommentThread 据此不能直接取 tree[0]
+	tree := buildCommentTree([]model.Comment{
+		testComment(2, uintPtr(1)),
+		testComment(3, uintPtr(2)),
+	})
+	if len(tree) != 1 || tree[0].ID != 2 {
+		t.Fatalf("tree = %+v", tree)
+	}
+	for _, node := range tree {
+		if node.ID == 1 {
+			t.Error("未审核通过的根评论不应出现")
+		}
+	}
+}
+
+func TestPostVisibleTo(t *testing.T) {
+	published := &model.Post{UserID: 1, Status: model.PostStatusPublished}
+	draft := &model.Post{UserID: 1, Status: model.PostStatusDraft}
+	tests := []struct {
+		post   *model.Post
+		userID uint
+		want   bool
+	}{
+		{published, 0, true},
+		{published, 2, true},
+		{draft, 1, true},
+		{draft, 2, false},
+		{draft, 0, false},
+	}
+	for _, tt := range tests {
+		if got := postVisibleTo(tt.post, tt.userID); got != tt.want {
+			t.Errorf("postVisibleTo(%s, %d) = %t, want %t", tt.post.Status, tt.userID, got, tt.want)
+		}
+	}
+}
+
+func TestEditedCommentStatus(t *testing.T) {
+	clean := &DTO.SpamVerdictDTO{}
+	suspicious := &DTO.SpamVerdictDTO{Status: model.CommentStatusPending}
+	spam := &DTO.SpamVerdictDTO{Status: model.CommentStatusSpam}
+	tests := []struct {
+		current string
+		policy  string
+		verdict *DTO.SpamVerdictDTO
+		want    string
+	}{
+		{model.CommentStatusApproved, model.CommentPolicyOpen, clean, model.CommentStatusApproved},
+		{model.CommentStatusApproved, model.CommentPolicyOpen, suspicious, model.CommentStatusPending},
+		{model.CommentStatusApproved, model.CommentPolicyOpen, spam, model.CommentStatusSpam},
+		// 需要审核的文章中，编辑过的评论重新审核
+		{model.CommentStatusApproved, model.CommentPolicyModerated, clean, model.CommentStatusPending},
+		{model.CommentStatusPending, model.CommentPolicyOpen, spam, model.CommentStatusSpam},
+		// 编辑不会让未通过审核的评论公开
+		{model.CommentStatusPending, model.CommentPolicyOpen, clean, model.CommentStatusPending},
+		{model.CommentStatusRejected, model.CommentPolicyOpen, suspicious, model.CommentStatusRejected},
+	}
+	for _, tt := range tests {
+		if got := editedCommentStatus(tt.current, tt.policy, tt.verdict); got != tt.want {
+			t.Errorf("editedCommentStatus(%s, %s, %q) = %s, want %s", tt.current, tt.policy, tt.verdict.Status, got, tt.want)
+		}
+	}
+}
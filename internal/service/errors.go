@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-my-blog/config"
+	"go-my-blog/pkg/apperr"
+
+	"gorm.io/gorm"
+)
+
+// 服务层返回的业务错误，错误码稳定不变，客户端据此区分失败原因
+var (
+	// 用户
+	ErrUserNotFound       = apperr.NotFound("user_not_found", "用户不存在")
+	ErrUsernameTaken      = apperr.Conflict("username_taken", "用户名已被注册")
+	ErrPasswordRequired   = apperr.Validation("password_required", "密码不能为空")
+	ErrInvalidCredentials = apperr.Unauthorized("invalid_credentials", "用户名或密码错误！")
+	ErrTokenIssueFailed   = apperr.Internal("token_issue_failed", "生成令牌失败！")
+
+	// 文章
+	ErrPostNotFound        = apperr.NotFound("post_not_found", "文章不存在")
+	ErrPostUpdateForbidden = apperr.Forbidden("post_update_forbidden", "登录用户非文章作者，不允许更新文章")
+	ErrPostDeleteForbidden = apperr.Forbidden("post_delete_forbidden", "登录用户非文章作者，不允许删除文章")
+	ErrDraftForbidden      = apperr.Forbidden("draft_forbidden", "只能查看自己的草稿，请使用 author=me")
+	ErrCoverNotFound       = apperr.Validation("cover_not_found", "封面图片不存在")
+	ErrCoverForbidden      = apperr.Forbidden("cover_forbidden", "只能使用自己上传的图片作为封面")
+
+	// 评论
+	ErrCommentNotFound        = apperr.NotFound("comment_not_found", "评论不存在")
+	ErrCommentDeleted         = apperr.Conflict("comment_deleted", "评论已删除")
+	ErrCommentEditDeleted     = apperr.Conflict("comment_edit_deleted", "评论已删除，不允许编辑")
+	ErrCommentDeleteForbidden = apperr.Forbidden("comment_delete_forbidden", "登录用户非评论作者，不允许删除评论")
+	ErrCommentEditForbidden   = apperr.Forbidden("comment_edit_forbidden", "登录用户非评论作者，不允许编辑评论")
+	ErrCommentEditExpired     = apperr.Forbidden("comment_edit_expired", "评论已超过可编辑时间，不允许编辑")
+	ErrCommentsClosed         = apperr.Forbidden("comments_closed", "文章已关闭评论")
+	ErrGuestCommentDisabled   = apperr.Forbidden("guest_comment_disabled", "游客评论未开启")
+	ErrChallengeFailed        = apperr.Validation("challenge_failed", "工作量证明校验失败")
+	ErrParentMismatch         = apperr.Validation("parent_comment_mismatch", "父评论不属于该文章")
+	ErrParentUnavailable      = apperr.Conflict("parent_comment_unavailable", "不能回复已删除或未公开的评论")
+	ErrReplyTooDeep           = apperr.Validation("reply_too_deep", "回复层级超过上限")
+	ErrModerateActionInvalid  = apperr.Validation("moderate_action_invalid", "不支持的审核动作")
+	ErrModeratorRequired      = apperr.Forbidden("moderator_required", "无评论审核权限")
+
+	// 媒体
+	ErrMediaNotFound    = apperr.NotFound("media_not_found", "媒体不存在")
+	ErrMediaEmpty       = apperr.Validation("media_empty", "文件不能为空")
+	ErrMediaTooLarge    = apperr.TooLarge("media_too_large", "文件过大")
+	ErrMediaUnsupported = apperr.Validation("media_type_unsupported", "不支持的文件类型")
+	ErrMediaInUse       = apperr.Conflict("media_in_use", "媒体仍被文章引用，如需删除请传 force=true")
+	ErrMediaForbidden   = apperr.Forbidden("media_forbidden", "无权操作该媒体")
+
+	// 个人数据与账号注销
+	ErrExportNotFound       = apperr.NotFound("export_not_found", "导出任务不存在")
+	ErrExportPending        = apperr.Conflict("export_pending", "已有正在生成的导出任务，请稍后再试")
+	ErrExportNotReady       = apperr.Conflict("export_not_ready", "归档尚未生成完成")
+	ErrExportExpired        = apperr.Conflict("export_expired", "归档已过期，请重新申请导出")
+	ErrCommentModeInvalid   = apperr.Validation("comment_mode_invalid", "评论处理方式只能是 anonymize 或 delete")
+	ErrDeletionScheduled    = apperr.Conflict("deletion_already_scheduled", "已申请注销，如需修改请先撤销")
+	ErrDeletionNotScheduled = apperr.Conflict("deletion_not_scheduled", "没有待处理的注销申请")
+	ErrPasswordIncorrect    = apperr.Forbidden("password_incorrect", "密码错误")
+	ErrLocaleUnsupported    = apperr.Validation("locale_unsupported", "不支持的语言")
+
+	// 管理与回收站
+	ErrBackupForbidden  = apperr.Forbidden("backup_forbidden", "只有管理员可以导出数据")
+	ErrTrashForbidden   = apperr.Forbidden("trash_forbidden", "无权操作该内容")
+	ErrTrashPostDeleted = apperr.Conflict("trash_post_deleted", "评论所属文章已删除，请先恢复文章")
+)
+
+// notFound 记录不存在时转换为对应的业务错误，其他错误原样返回
+func notFound(err error, e *apperr.Error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.Wrap(err)
+	}
+	return err
+}
+
+// MediaTooLarge 文件超过配置的大小上限，说明中带上上限值
+func MediaTooLarge() *apperr.Error {
+	maxSizeMB := config.Conf.Media.MaxSizeMB
+	return ErrMediaTooLarge.
+		WithMessage(fmt.Sprintf("文件大小不能超过 %dMB", maxSizeMB)).
+		WithDetails(map[string]interface{}{"max_size_mb": maxSizeMB})
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/repo"
+	"go-my-blog/pkg/feed"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/render"
+	"net/url"
+
+	"go.uber.org/zap"
+)
+
+type FeedService struct {
+	postRepo     *repo.PostRepository
+	userRepo     *repo.UserRepository
+	mediaService *MediaService
+}
+
+func NewFeedService(postRepo *repo.PostRepository, userRepo *repo.UserRepository, mediaService *MediaService) *FeedService {
+	return &FeedService{
+		postRepo:     postRepo,
+		userRepo:     userRepo,
+		mediaService: mediaService,
+	}
+}
+
+// BuildFeed 根据查询条件生成与格式无关的订阅源；作者不存在时返回 gorm.ErrRecordNotFound
+func (fs *FeedService) BuildFeed(ctx context.Context, d *DTO.FeedQueryDTO) (*feed.Feed, error) {
+	site := config.Conf.Site
+	result := &feed.Feed{
+		Title:       site.Name,
+		Link:        site.AbsoluteURL("/"),
+		FeedURL:     site.AbsoluteURL(d.Path),
+		Description: site.Description,
+		Language:    site.Language,
+	}
+
+	var authorID uint
+	if d.Author != "" {
+		author, err := fs.userRepo.FindByUserName(ctx, d.Author)
+		if err != nil {
+			logger.Warn("订阅源作者不存在", zap.String("author", d.Author), zap.Error(err))
+			return nil, err
+		}
+		authorID = author.ID
+		result.Title = site.Name + " - " + author.Username
+		result.Link = site.AbsoluteURL("/authors/" + url.PathEscape(author.Username))
+	}
+	if d.Tag != "" {
+		result.Title = site.Name + " - #" + d.Tag
+		result.Link = site.AbsoluteURL("/tags/" + url.PathEscape(d.Tag))
+	}
+
+	posts, err := fs.postRepo.ListRecentPublished(ctx, authorID, d.Tag, config.Conf.Feed.Limit)
+	if err != nil {
+		logger.Error("订阅源文章查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range posts {
+		post := &posts[i]
+		// 历史文章可能尚未生成摘要和 HTML 缓存，这里临时计算，不回写
+		if post.RenderVersion != render.Version {
+			applyRendered(post)
+		}
+		link := site.PostURL(post.ID)
+		item := feed.Item{
+			ID:         link,
+			Title:      post.Title,
+			Link:       link,
+			Summary:    post.Excerpt,
+			AuthorName: post.User.Username,
+			Published:  post.CreatedAt,
+			Updated:    post.UpdatedAt,
+			Tags:       tagNamesOf(post.Tags),
+		}
+		if d.Mode == DTO.FeedModeFull {
+			item.ContentHTML = post.ContentHTML
+		}
+		if post.CoverMedia != nil {
+			item.ImageURL = site.AbsoluteURL(fs.mediaService.URLOf(post.CoverMedia.StorageKey))
+		}
+		if post.UpdatedAt.After(result.Updated) {
+			result.Updated = post.UpdatedAt
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
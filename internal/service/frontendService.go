@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"go-my-blog/internal/repo"
+	"go-my-blog/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// FrontendService HTML 前台使用的查询，只返回已发布文章
+type FrontendService struct {
+	postService *PostService
+	postRepo    *repo.PostRepository
+	userRepo    *repo.UserRepository
+}
+
+func NewFrontendService(postService *PostService, postRepo *repo.PostRepository, userRepo *repo.UserRepository) *FrontendService {
+	return &FrontendService{
+		postService: postService,
+		postRepo:    postRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// ListPosts 已发布文章列表，可按标签筛选
+func (fs *FrontendService) ListPosts(ctx context.Context, tag string, page int) (*DTO.PostListDTO, error) {
+	return fs.postService.PostList(ctx, &DTO.ListPostDTO{
+		PageNum:  page,
+		PageSize: config.Conf.Frontend.PageSize,
+		Status:   model.PostStatusPublished,
+		Tag:      tag,
+		Sort:     DTO.PostSortNewest,
+	})
+}
+
+// ListAuthorPosts 作者的已发布文章列表；作者不存在时返回 gorm.ErrRecordNotFound
+func (fs *FrontendService) ListAuthorPosts(ctx context.Context, username string, page int) (*DTO.PostListDTO, error) {
+	author, err := fs.userRepo.FindByUserName(ctx, username)
+	if err != nil {
+		logger.Warn("作者不存在", zap.String("username", username), zap.Error(err))
+		return nil, err
+	}
+	return fs.postService.PostList(ctx, &DTO.ListPostDTO{
+		PageNum:  page,
+		PageSize: config.Conf.Frontend.PageSize,
+		Status:   model.PostStatusPublished,
+		AuthorID: author.ID,
+		Sort:     DTO.PostSortNewest,
+	})
+}
+
+// PostDetail 文章详情；未发布的文章按不存在处理，返回 gorm.ErrRecordNotFound
+func (fs *FrontendService) PostDetail(ctx context.Context, id uint) (*DTO.PostDetailDTO, error) {
+	post, err := fs.postRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status != model.PostStatusPublished {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return fs.postService.PostDetail(ctx, id, 0)
+}
+
+// Archive 按月分组的全部已发布文章
+func (fs *FrontendService) Archive(ctx context.Context) ([]DTO.ArchiveMonthDTO, error) {
+	posts, err := fs.postRepo.ListArchive(ctx)
+	if err != nil {
+		logger.Error("归档文章查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	var months []DTO.ArchiveMonthDTO
+	for _, post := range posts {
+		month := post.CreatedAt.Format("2006-01")
+		if len(months) == 0 || months[len(months)-1].Month != month {
+			months = append(months, DTO.ArchiveMonthDTO{Month: month})
+		}
+		last := &months[len(months)-1]
+		last.Posts = append(last.Posts, DTO.ArchivePostDTO{ID: post.ID, Title: post.Title, CreatedAt: post.CreatedAt})
+	}
+	return months, nil
+}
@@ -0,0 +1,547 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"go-my-blog/internal/repo"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/render"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ImportService 从外部来源导入文章、用户和评论；按来源或 slug 匹配已导入的文章，重复执行不会产生重复数据
+type ImportService struct {
+	postService *PostService
+	userRepo    *repo.UserRepository
+	uow         *repo.UnitOfWork
+}
+
+func NewImportService(postService *PostService, userRepo *repo.UserRepository, uow *repo.UnitOfWork) *ImportService {
+	return &ImportService{
+		postService: postService,
+		userRepo:    userRepo,
+		uow:         uow,
+	}
+}
+
+// ImportSession 一次导入：来源逐条提交用户和文章，不需要一次性加载全部内容
+type ImportSession struct {
+	service         *ImportService
+	dryRun          bool
+	defaultAuthorID uint
+	authors         map[string]uint // 用户名 -> 用户ID（预览模式下待创建的用户为 0）
+	report          *DTO.ImportReportDTO
+}
+
+// Begin 开始一次导入；defaultAuthor 为没有指定作者的文章使用的用户名，可为空
+func (is *ImportService) Begin(ctx context.Context, defaultAuthor string, dryRun bool) (*ImportSession, error) {
+	session := &ImportSession{
+		service: is,
+		dryRun:  dryRun,
+		authors: make(map[string]uint),
+		report:  &DTO.ImportReportDTO{DryRun: dryRun, Results: []DTO.ImportResultDTO{}, UsersRenamed: []string{}, UserErrors: []string{}},
+	}
+	if defaultAuthor != "" {
+		author, err := is.userRepo.FindByUserName(ctx, defaultAuthor)
+		if err != nil {
+			logger.Error("导入作者不存在", zap.String("author", defaultAuthor), zap.Error(err))
+			return nil, errors.New("导入作者不存在：" + defaultAuthor)
+		}
+		session.defaultAuthorID = author.ID
+		session.authors[author.Username] = author.ID
+	}
+	return session, nil
+}
+
+// Report 导入报告
+func (s *ImportSession) Report() *DTO.ImportReportDTO {
+	return s.report
+}
+
+// ImportUser 导入用户：同一来源导入时创建的同名用户直接复用；用户名被注册用户或其他来源占用时改名为
+// “用户名_来源”导入，避免把来源中的作者对应到无关的账号。新用户使用不可登录的随机密码并标记需要重置密码
+func (s *ImportSession) ImportUser(ctx context.Context, d *DTO.ImportUserDTO) {
+	username := strings.TrimSpace(d.Username)
+	if username == "" || utf8.RuneCountInString(username) > 50 {
+		s.report.UserErrors = append(s.report.UserErrors, fmt.Sprintf("用户名无效：%q", d.Username))
+		return
+	}
+	if d.Source == "" {
+		s.report.UserErrors = append(s.report.UserErrors, username+"：缺少导入来源")
+		return
+	}
+	if _, ok := s.authors[username]; ok {
+		return
+	}
+
+	name := ""
+	for _, candidate := range importUsernameCandidates(username, d.Source) {
+		user, err := s.service.userRepo.FindByUserName(ctx, candidate)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			name = candidate
+			break
+		}
+		if err != nil {
+			s.report.UserErrors = append(s.report.UserErrors, username+"："+err.Error())
+			return
+		}
+		if user.ImportSource == d.Source {
+			s.authors[username] = user.ID
+			s.report.UsersExisting++
+			return
+		}
+	}
+	if name == "" {
+		s.report.UserErrors = append(s.report.UserErrors, fmt.Sprintf("%s：用户名已被其他账号占用", username))
+		return
+	}
+	if name != username {
+		s.report.UsersRenamed = append(s.report.UsersRenamed, username+" → "+name)
+	}
+
+	if s.dryRun {
+		s.authors[username] = 0
+		s.report.UsersCreated++
+		return
+	}
+	email := strings.TrimSpace(d.Email)
+	if email == "" {
+		// 邮箱唯一且必填，来源中没有邮箱时使用保留域名占位
+		email = name + "@import.invalid"
+	}
+	password, err := unusablePassword()
+	if err != nil {
+		s.report.UserErrors = append(s.report.UserErrors, username+"："+err.Error())
+		return
+	}
+	user, err := s.service.userRepo.UserRegister(ctx, &model.User{
+		Username:              name,
+		Email:                 email,
+		Password:              password,
+		Role:                  model.UserRoleUser,
+		PasswordResetRequired: true,
+		ImportSource:          d.Source,
+	})
+	if err != nil {
+		logger.Warn("导入用户创建失败", zap.String("username", username), zap.Error(err))
+		s.report.UserErrors = append(s.report.UserErrors, username+"："+err.Error())
+		return
+	}
+	s.authors[username] = user.ID
+	s.report.UsersCreated++
+}
+
+// ImportPost 比较并导入一篇文章及其评论；失败记录在报告中，不影响其余文章
+func (s *ImportSession) ImportPost(ctx context.Context, item *DTO.ImportPostDTO) {
+	result := s.importPost(ctx, item)
+	switch result.Action {
+	case DTO.ImportActionCreate:
+		s.report.Created++
+	case DTO.ImportActionUpdate:
+		s.report.Updated++
+	case DTO.ImportActionUnchanged:
+		s.report.Unchanged++
+	default:
+		s.report.Failed++
+	}
+	s.report.Results = append(s.report.Results, result)
+}
+
+func (s *ImportSession) importPost(ctx context.Context, item *DTO.ImportPostDTO) DTO.ImportResultDTO {
+	ps := s.service.postService
+	result := DTO.ImportResultDTO{SourcePath: item.SourcePath, Title: item.Title}
+	fail := func(err error) DTO.ImportResultDTO {
+		logger.Warn("文章导入失败", zap.String("source", item.SourcePath), zap.Error(err))
+		result.Action = DTO.ImportActionFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	item.Title = strings.TrimSpace(item.Title)
+	if item.Title == "" {
+		return fail(errors.New("标题不能为空"))
+	}
+	if utf8.RuneCountInString(item.Title) > 200 {
+		return fail(errors.New("标题超过 200 字"))
+	}
+	if item.ContentFormat == "" {
+		item.ContentFormat = render.FormatMarkdown
+	}
+	item.Tags = normalizeTagNames(item.Tags)
+
+	authorID := s.defaultAuthorID
+	if item.Author != "" {
+		id, ok := s.authors[item.Author]
+		if !ok {
+			return fail(errors.New("作者未导入：" + item.Author))
+		}
+		authorID = id
+	} else if authorID == 0 {
+		return fail(errors.New("未指定作者"))
+	}
+	if authorID == 0 && !s.dryRun {
+		return fail(errors.New("作者创建失败：" + item.Author))
+	}
+
+	existing, err := ps.PostRepo.FindImported(ctx, authorID, item.SourcePath, item.Slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fail(err)
+	}
+
+	if existing == nil {
+		result.Action = DTO.ImportActionCreate
+		result.Changes = []string{"新建文章"}
+	} else {
+		result.Changes = diffImportedPost(existing, item)
+		result.Action = DTO.ImportActionUnchanged
+		if len(result.Changes) > 0 {
+			result.Action = DTO.ImportActionUpdate
+		}
+	}
+
+	// 文章、评论和旧链接跳转在同一事务中写入，失败的文章不会留下一半数据
+	var post *model.Post
+	var created int
+	err = s.service.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		post = existing
+		if !s.dryRun {
+			var err error
+			switch result.Action {
+			case DTO.ImportActionCreate:
+				post, err = s.createPost(ctx, repos, authorID, item)
+			case DTO.ImportActionUpdate:
+				err = s.updatePost(ctx, repos, existing, item)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if created, err = s.importComments(ctx, repos, post, item.Comments); err != nil {
+			return err
+		}
+		if post == nil || s.dryRun {
+			return nil
+		}
+		for _, original := range item.OriginalURLs {
+			from := RedirectPath(original)
+			if from == "" || from == "/" {
+				continue
+			}
+			if err := repos.Redirects.Save(ctx, from, post.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+	if post != nil {
+		result.PostID = post.ID
+	}
+	if created > 0 {
+		result.Changes = append(result.Changes, fmt.Sprintf("新增评论 %d 条", created))
+		if result.Action == DTO.ImportActionUnchanged {
+			result.Action = DTO.ImportActionUpdate
+		}
+		s.report.CommentsCreated += created
+	}
+	return result
+}
+
+func (s *ImportSession) createPost(ctx context.Context, repos *repo.Repositories, authorID uint, item *DTO.ImportPostDTO) (*model.Post, error) {
+	ps := s.service.postService
+	post := model.Post{
+		UserID:         authorID,
+		Title:          item.Title,
+		Content:        item.Content,
+		ContentFormat:  item.ContentFormat,
+		Status:         importStatus(item),
+		Slug:           item.Slug,
+		SourcePath:     item.SourcePath,
+		SEODescription: item.Description,
+	}
+	applyRendered(&post)
+	if item.Date != nil {
+		post.CreatedAt = *item.Date
+		post.UpdatedAt = *item.Date
+	}
+	if item.Modified != nil {
+		post.UpdatedAt = *item.Modified
+	}
+
+	tags, err := repos.Tags.FindOrCreateByNames(ctx, item.Tags)
+	if err != nil {
+		return nil, err
+	}
+	post.Tags = tags
+	media, err := ps.postMediaRefs(ctx, post.Content, nil)
+	if err != nil {
+		return nil, err
+	}
+	post.Media = media
+	return repos.Posts.Create(ctx, &post)
+}
+
+func (s *ImportSession) updatePost(ctx context.Context, repos *repo.Repositories, post *model.Post, item *DTO.ImportPostDTO) error {
+	ps := s.service.postService
+	post.Content = item.Content
+	post.ContentFormat = item.ContentFormat
+	applyRendered(post)
+
+	updateMap := map[string]interface{}{
+		"title":           item.Title,
+		"content":         post.Content,
+		"content_format":  post.ContentFormat,
+		"content_html":    post.ContentHTML,
+		"render_version":  post.RenderVersion,
+		"excerpt":         post.Excerpt,
+		"word_count":      post.WordCount,
+		"reading_minutes": post.ReadingMinutes,
+		"toc":             post.TOC,
+		"status":          importStatus(item),
+		"slug":            item.Slug,
+		"source_path":     item.SourcePath,
+		"seo_description": item.Description,
+		"updated_at":      time.Now(),
+	}
+	if item.Date != nil {
+		updateMap["created_at"] = *item.Date
+	}
+	if item.Modified != nil {
+		updateMap["updated_at"] = *item.Modified
+	}
+	if err := repos.Posts.Updates(ctx, post.ID, &updateMap); err != nil {
+		return err
+	}
+
+	tags, err := repos.Tags.FindOrCreateByNames(ctx, item.Tags)
+	if err != nil {
+		return err
+	}
+	if err := repos.Tags.ReplacePostTags(ctx, post.ID, tags); err != nil {
+		return err
+	}
+	media, err := ps.postMediaRefs(ctx, post.Content, post.CoverMediaID)
+	if err != nil {
+		return err
+	}
+	return repos.Media.ReplacePostMedia(ctx, post.ID, media)
+}
+
+// importComments 导入尚未导入过的评论并恢复回复关系；父评论缺失时作为根评论，超过最大层级时挂到允许的最深祖先下。
+// post 为 nil（预览模式下的新文章）时全部视为新增
+func (s *ImportSession) importComments(ctx context.Context, repos *repo.Repositories, post *model.Post, comments []DTO.ImportCommentDTO) (int, error) {
+	if len(comments) == 0 {
+		return 0, nil
+	}
+	commentRepo := repos.Comments
+
+	imported := make(map[string]*model.Comment)
+	if post != nil {
+		existing, err := commentRepo.ListImported(ctx, post.ID)
+		if err != nil {
+			return 0, err
+		}
+		for i := range existing {
+			imported[existing[i].SourceID] = &existing[i]
+		}
+	}
+
+	// 按时间排序，保证父评论先于回复创建
+	sorted := make([]DTO.ImportCommentDTO, len(comments))
+	copy(sorted, comments)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	created := 0
+	for _, item := range sorted {
+		if _, ok := imported[item.SourceID]; ok {
+			continue
+		}
+		created++
+		if s.dryRun {
+			continue
+		}
+
+		comment := &model.Comment{
+			PostID:        post.ID,
+			Content:       item.Content,
+			ContentFormat: item.ContentFormat,
+			Status:        item.Status,
+			IP:            item.IP,
+			GuestName:     truncateRunes(item.GuestName, 50),
+			GuestEmail:    truncateRunes(item.GuestEmail, 100),
+			GuestWebsite:  truncateRunes(item.GuestWebsite, 200),
+			SourceID:      item.SourceID,
+			CreatedAt:     item.CreatedAt,
+			UpdatedAt:     item.CreatedAt,
+		}
+		if comment.ContentFormat == "" {
+			comment.ContentFormat = render.FormatPlain
+		}
+		if comment.Status == "" {
+			comment.Status = model.CommentStatusApproved
+		}
+		if userID, ok := s.authors[item.Author]; ok && item.Author != "" && userID != 0 {
+			comment.UserID = &userID
+			comment.GuestName, comment.GuestEmail, comment.GuestWebsite = "", "", ""
+		}
+		comment.ContentHTML = renderContent(comment.Content, comment.ContentFormat)
+		comment.RenderVersion = render.Version
+
+		parent := imported[item.ParentSourceID]
+		for parent != nil && parent.Depth+1 >= config.Conf.Comment.MaxDepth {
+			parent = parentOf(parent, imported)
+		}
+		if parent != nil {
+			comment.ParentID = &parent.ID
+			comment.Depth = parent.Depth + 1
+		}
+		if _, err := commentRepo.Create(ctx, comment); err != nil {
+			return created, err
+		}
+		comment.Path = model.CommentPathSegment(comment.ID)
+		if parent != nil {
+			comment.Path = parent.Path + comment.Path
+		}
+		if err := commentRepo.UpdatePath(ctx, comment.ID, comment.Path); err != nil {
+			return created, err
+		}
+		if parent != nil && comment.Status == model.CommentStatusApproved {
+			if err := commentRepo.IncrReplyCount(ctx, parent.ID, 1); err != nil {
+				return created, err
+			}
+		}
+		imported[item.SourceID] = comment
+	}
+	return created, nil
+}
+
+// parentOf 在已导入的评论中查找父评论
+func parentOf(comment *model.Comment, imported map[string]*model.Comment) *model.Comment {
+	if comment.ParentID == nil {
+		return nil
+	}
+	for _, candidate := range imported {
+		if candidate.ID == *comment.ParentID {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// RedirectPath 将原站点地址规整为重定向匹配用的路径：去掉协议和域名、去掉末尾斜杠，保留查询参数（如 /?p=12）
+func RedirectPath(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	path := u.EscapedPath()
+	if path != "/" {
+		path = strings.TrimSuffix(path, "/")
+	}
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	if len(path) > 255 {
+		return ""
+	}
+	return path
+}
+
+// diffImportedPost 比较已导入文章与本次内容，返回可读的差异列表
+func diffImportedPost(post *model.Post, item *DTO.ImportPostDTO) []string {
+	var changes []string
+	if post.Title != item.Title {
+		changes = append(changes, fmt.Sprintf("标题：%q → %q", post.Title, item.Title))
+	}
+	if post.Content != item.Content || post.ContentFormat != item.ContentFormat {
+		changes = append(changes, fmt.Sprintf("正文：%d 字符 → %d 字符", utf8.RuneCountInString(post.Content), utf8.RuneCountInString(item.Content)))
+	}
+	if status := importStatus(item); post.Status != status {
+		changes = append(changes, fmt.Sprintf("状态：%s → %s", post.Status, status))
+	}
+	if oldTags := tagNamesOf(post.Tags); !sameTagSet(oldTags, item.Tags) {
+		changes = append(changes, fmt.Sprintf("标签：%v → %v", oldTags, item.Tags))
+	}
+	if post.Slug != item.Slug {
+		changes = append(changes, fmt.Sprintf("别名：%q → %q", post.Slug, item.Slug))
+	}
+	if post.SourcePath != item.SourcePath {
+		changes = append(changes, fmt.Sprintf("来源：%q → %q", post.SourcePath, item.SourcePath))
+	}
+	if post.SEODescription != item.Description {
+		changes = append(changes, "描述已变化")
+	}
+	if item.Date != nil && post.CreatedAt.Unix() != item.Date.Unix() {
+		changes = append(changes, fmt.Sprintf("发布时间：%s → %s", post.CreatedAt.Format(time.DateTime), item.Date.Format(time.DateTime)))
+	}
+	return changes
+}
+
+// sameTagSet 两组标签是否相同（不考虑顺序）
+func sameTagSet(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, name := range a {
+		seen[name] = true
+	}
+	for _, name := range b {
+		if !seen[name] {
+			return false
+		}
+	}
+	return true
+}
+
+func importStatus(item *DTO.ImportPostDTO) string {
+	if item.Draft {
+		return model.PostStatusDraft
+	}
+	return model.PostStatusPublished
+}
+
+// importUsernameCandidates 导入用户依次尝试的用户名：原用户名，被占用时改用“用户名_来源”；超过长度限制时不改名
+func importUsernameCandidates(username string, source string) []string {
+	candidates := []string{username}
+	if renamed := username + "_" + source; utf8.RuneCountInString(renamed) <= 50 {
+		candidates = append(candidates, renamed)
+	}
+	return candidates
+}
+
+// unusablePassword 不是合法 bcrypt 哈希的随机值，任何密码都无法通过校验
+func unusablePassword() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "!" + hex.EncodeToString(buf), nil
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportUsernameCandidates(t *testing.T) {
+	got := importUsernameCandidates("admin", "wordpress")
+	if want := []string{"admin", "admin_wordpress"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("importUsernameCandidates = %v, want %v", got, want)
+	}
+
+	// 改名后超过用户名长度限制时只尝试原用户名，被占用则导入失败
+	long := strings.Repeat("a", 45)
+	if got := importUsernameCandidates(long, "wordpress"); !reflect.DeepEqual(got, []string{long}) {
+		t.Errorf("importUsernameCandidates = %v, want 只有原用户名", got)
+	}
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"go-my-blog/internal/repo"
+	"go-my-blog/pkg/imaging"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/storage"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// mediaKeyPattern 匹配内容中的媒体地址（原图或缩略图），提取媒体随机标识；
+// 只依赖 key 的格式，与存储类型和访问域名无关
+var mediaKeyPattern = regexp.MustCompile(`media/\d{4}/\d{2}/([0-9a-f]{32})(?:_w\d+)?\.[a-z]+`)
+
+type MediaService struct {
+	mediaRepo *repo.MediaRepository
+	userRepo  *repo.UserRepository
+	storage   storage.Storage
+}
+
+func NewMediaService(mediaRepo *repo.MediaRepository, userRepo *repo.UserRepository, storage storage.Storage) *MediaService {
+	return &MediaService{
+		mediaRepo: mediaRepo,
+		userRepo:  userRepo,
+		storage:   storage,
+	}
+}
+
+// Upload 上传图片：按内容识别类型、校验大小和像素，去除 EXIF 后保存原图并生成缩略图
+func (ms *MediaService) Upload(ctx context.Context, d *DTO.UploadMediaDTO) (*DTO.MediaDTO, error) {
+	mediaConf := config.Conf.Media
+	if len(d.Data) == 0 {
+		return nil, ErrMediaEmpty
+	}
+	if int64(len(d.Data)) > mediaConf.GetMaxSize() {
+		logger.Warn("上传文件过大", zap.Uint("user_id", d.UserID), zap.Int("size", len(d.Data)))
+		return nil, MediaTooLarge()
+	}
+
+	mimeType := imaging.Sniff(d.Data)
+	if !isAllowedMediaType(mimeType) {
+		logger.Warn("不允许上传的文件类型", zap.Uint("user_id", d.UserID), zap.String("mime_type", mimeType))
+		return nil, ErrMediaUnsupported.
+			WithMessage("不支持的文件类型：" + mimeType).
+			WithDetails(map[string]interface{}{"mime_type": mimeType})
+	}
+
+	result, err := imaging.Process(d.Data, mimeType, mediaConf.MaxPixels, mediaConf.ThumbnailWidths, mediaConf.JPEGQuality)
+	if err != nil {
+		logger.Warn("图片处理失败", zap.Uint("user_id", d.UserID), zap.Error(err))
+		return nil, err
+	}
+
+	uid, err := newMediaUID()
+	if err != nil {
+		logger.Error("生成媒体标识失败", zap.Error(err))
+		return nil, err
+	}
+	prefix := fmt.Sprintf("media/%s/%s", time.Now().Format("2006/01"), uid)
+
+	// 任一文件写入失败或入库失败时，清理已写入的文件
+	var storedKeys []string
+	cleanup := func() {
+		for _, key := range storedKeys {
+			if err := ms.storage.Delete(key); err != nil {
+				logger.Warn("清理媒体文件失败", zap.String("key", key), zap.Error(err))
+			}
+		}
+	}
+	put := func(key string, variant imaging.Variant) error {
+		if err := ms.storage.Put(key, bytes.NewReader(variant.Data), int64(len(variant.Data)), variant.MimeType); err != nil {
+			logger.Error("媒体文件写入存储失败", zap.String("key", key), zap.Error(err))
+			return err
+		}
+		storedKeys = append(storedKeys, key)
+		return nil
+	}
+
+	originalKey := prefix + imaging.Extensions[mimeType]
+	if err := put(originalKey, result.Original); err != nil {
+		cleanup()
+		return nil, err
+	}
+	thumbnails := make([]model.MediaThumbnail, 0, len(result.Thumbnails))
+	for _, thumbnail := range result.Thumbnails {
+		key := fmt.Sprintf("%s_w%d%s", prefix, thumbnail.Width, imaging.Extensions[thumbnail.MimeType])
+		if err := put(key, thumbnail); err != nil {
+			cleanup()
+			return nil, err
+		}
+		thumbnails = append(thumbnails, model.MediaThumbnail{Width: thumbnail.Width, Height: thumbnail.Height, Key: key})
+	}
+	thumbnailsJSON, err := json.Marshal(thumbnails)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	media := &model.Media{
+		UID:        uid,
+		UserID:     d.UserID,
+		FileName:   cleanFileName(d.FileName),
+		MimeType:   mimeType,
+		Size:       int64(len(result.Original.Data)),
+		Width:      result.Original.Width,
+		Height:     result.Original.Height,
+		Storage:    mediaConf.Storage,
+		StorageKey: originalKey,
+		Thumbnails: string(thumbnailsJSON),
+	}
+	if err := ms.mediaRepo.Create(ctx, media); err != nil {
+		logger.Error("媒体入库失败", zap.Error(err))
+		cleanup()
+		return nil, err
+	}
+
+	mediaDTO := ms.toMediaDTO(media)
+	return &mediaDTO, nil
+}
+
+// List 当前用户上传的媒体列表
+func (ms *MediaService) List(ctx context.Context, d *DTO.ListMediaDTO) (*DTO.MediaListDTO, error) {
+	media, total, err := ms.mediaRepo.ListByUser(ctx, d.UserID, d.PageNum, d.PageSize)
+	if err != nil {
+		logger.Error("媒体列表查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(media))
+	for _, item := range media {
+		ids = append(ids, item.ID)
+	}
+	references, err := ms.mediaRepo.CountReferences(ctx, ids)
+	if err != nil {
+		logger.Error("媒体引用数查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	mediaDTOs := make([]DTO.MediaDTO, 0, len(media))
+	for i := range media {
+		mediaDTO := ms.toMediaDTO(&media[i])
+		mediaDTO.References = references[media[i].ID]
+		mediaDTOs = append(mediaDTOs, mediaDTO)
+	}
+	return &DTO.MediaListDTO{Media: mediaDTOs, Total: total, PageNum: d.PageNum, PageSize: d.PageSize}, nil
+}
+
+// Detail 媒体详情，包含引用该媒体的文章；只有上传者和管理员可以查看
+func (ms *MediaService) Detail(ctx context.Context, id uint, userID uint) (*DTO.MediaDTO, error) {
+	media, err := ms.getOwnedMedia(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := ms.mediaRepo.ListReferencingPosts(ctx, media.ID)
+	if err != nil {
+		logger.Error("媒体引用文章查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	mediaDTO := ms.toMediaDTO(media)
+	mediaDTO.Posts = make([]DTO.MediaPostDTO, 0, len(posts))
+	for _, post := range posts {
+		mediaDTO.Posts = append(mediaDTO.Posts, DTO.MediaPostDTO{ID: post.ID, Title: post.Title})
+	}
+	mediaDTO.References = int64(len(posts))
+	return &mediaDTO, nil
+}
+
+// Delete 删除媒体：仍被文章引用时需 force，先删记录再删文件，文件删除失败只记录日志
+func (ms *MediaService) Delete(ctx context.Context, d *DTO.DeleteMediaDTO) error {
+	media, err := ms.getOwnedMedia(ctx, d.ID, d.UserID)
+	if err != nil {
+		return err
+	}
+	if !d.Force {
+		references, err := ms.mediaRepo.CountReferences(ctx, []uint{media.ID})
+		if err != nil {
+			logger.Error("媒体引用数查询失败", zap.Error(err))
+			return err
+		}
+		if references[media.ID] > 0 {
+			logger.Warn("媒体仍被文章引用，拒绝删除", zap.Uint("media_id", media.ID), zap.Int64("references", references[media.ID]))
+			return ErrMediaInUse.
+				WithMessage(fmt.Sprintf("媒体仍被 %d 篇文章引用，如需删除请传 force=true", references[media.ID])).
+				WithDetails(map[string]interface{}{"references": references[media.ID]})
+		}
+	}
+
+	if err := ms.mediaRepo.Delete(ctx, media); err != nil {
+		logger.Error("媒体删除失败", zap.Error(err))
+		return err
+	}
+
+	keys := []string{media.StorageKey}
+	for _, thumbnail := range thumbnailsOf(media) {
+		keys = append(keys, thumbnail.Key)
+	}
+	for _, key := range keys {
+		if err := ms.storage.Delete(key); err != nil {
+			logger.Warn("媒体文件删除失败", zap.String("key", key), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// URLOf 存储 key 对应的访问地址
+func (ms *MediaService) URLOf(key string) string {
+	return ms.storage.URL(key)
+}
+
+// getOwnedMedia 查询媒体并校验权限：上传者本人或管理员
+func (ms *MediaService) getOwnedMedia(ctx context.Context, id uint, userID uint) (*model.Media, error) {
+	media, err := ms.mediaRepo.GetById(ctx, id)
+	if err != nil {
+		logger.Error("媒体查询失败", zap.Error(err))
+		return nil, notFound(err, ErrMediaNotFound)
+	}
+	if media.UserID == userID {
+		return media, nil
+	}
+	user, err := ms.userRepo.FindById(ctx, userID)
+	if err != nil {
+		logger.Error("用户查询失败", zap.Error(err))
+		return nil, err
+	}
+	if !user.IsAdmin() {
+		logger.Error("非上传者无权操作媒体", zap.Uint("user_id", userID), zap.Uint("media_id", id))
+		return nil, ErrMediaForbidden
+	}
+	return media, nil
+}
+
+func (ms *MediaService) toMediaDTO(media *model.Media) DTO.MediaDTO {
+	mediaDTO := DTO.MediaDTO{
+		ID:         media.ID,
+		UID:        media.UID,
+		UserID:     media.UserID,
+		FileName:   media.FileName,
+		MimeType:   media.MimeType,
+		Size:       media.Size,
+		Width:      media.Width,
+		Height:     media.Height,
+		URL:        ms.storage.URL(media.StorageKey),
+		Thumbnails: []DTO.MediaThumbnailDTO{},
+		CreatedAt:  media.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	for _, thumbnail := range thumbnailsOf(media) {
+		mediaDTO.Thumbnails = append(mediaDTO.Thumbnails, DTO.MediaThumbnailDTO{
+			Width:  thumbnail.Width,
+			Height: thumbnail.Height,
+			URL:    ms.storage.URL(thumbnail.Key),
+		})
+	}
+	return mediaDTO
+}
+
+// thumbnailsOf 解析库中存储的缩略图 JSON
+func thumbnailsOf(media *model.Media) []model.MediaThumbnail {
+	var thumbnails []model.MediaThumbnail
+	if media.Thumbnails == "" {
+		return thumbnails
+	}
+	if err := json.Unmarshal([]byte(media.Thumbnails), &thumbnails); err != nil {
+		logger.Warn("媒体缩略图解析失败", zap.Uint("media_id", media.ID), zap.Error(err))
+	}
+	return thumbnails
+}
+
+// mediaUIDsIn 提取内容中引用的媒体标识（去重）
+func mediaUIDsIn(content string) []string {
+	seen := make(map[string]bool)
+	var uids []string
+	for _, match := range mediaKeyPattern.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			uids = append(uids, match[1])
+		}
+	}
+	return uids
+}
+
+func isAllowedMediaType(mimeType string) bool {
+	if _, ok := imaging.Extensions[mimeType]; !ok {
+		return false
+	}
+	for _, allowed := range config.Conf.Media.AllowedTypes {
+		if allowed == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+func newMediaUID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// cleanFileName 只保留文件名部分（兼容 Windows 路径）并限制长度，仅用于展示
+func cleanFileName(name string) string {
+	name = strings.ToValidUTF8(name, "")
+	name = name[strings.LastIndexAny(name, `/\`)+1:]
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
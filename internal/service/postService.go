@@ -1,29 +1,46 @@
 package service
 
 import (
-	"database/sql"
+	"context"
+	"encoding/json"
 	"errors"
+	"go-my-blog/config"
 	"go-my-blog/internal/DTO"
 	"go-my-blog/internal/model"
 	"go-my-blog/internal/repo"
 	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/render"
+	"go-my-blog/pkg/seo"
+	"go-my-blog/pkg/storage"
+	"strings"
 	"time"
 
 	"github.com/jinzhu/copier"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type PostService struct {
-	PostRepo    *repo.PostRepository
-	UserRepo    *repo.UserRepository
-	CommentRepo *repo.CommentRepository
+	PostRepo     *repo.PostRepository
+	UserRepo     *repo.UserRepository
+	CommentRepo  *repo.CommentRepository
+	TagRepo      *repo.TagRepository
+	MediaRepo    *repo.MediaRepository
+	MediaStorage storage.Storage
+
+	uow          *repo.UnitOfWork
+	publishHooks []func(post *model.Post)
 }
 
-func NewPostService(postRepo *repo.PostRepository, userRepo *repo.UserRepository, commentRepo *repo.CommentRepository) *PostService {
+func NewPostService(postRepo *repo.PostRepository, userRepo *repo.UserRepository, commentRepo *repo.CommentRepository, tagRepo *repo.TagRepository, mediaRepo *repo.MediaRepository, mediaStorage storage.Storage, uow *repo.UnitOfWork) *PostService {
 	return &PostService{
-		PostRepo:    postRepo,
-		UserRepo:    userRepo,
-		CommentRepo: commentRepo,
+		PostRepo:     postRepo,
+		UserRepo:     userRepo,
+		CommentRepo:  commentRepo,
+		TagRepo:      tagRepo,
+		MediaRepo:    mediaRepo,
+		MediaStorage: mediaStorage,
+		uow:          uow,
 	}
 }
 
@@ -31,119 +48,276 @@ func (ps *PostService) GetPostRepo() *repo.PostRepository {
 	return ps.PostRepo
 }
 
-func (ps *PostService) CreatePost(userID uint, createPostDTO *DTO.CreatePostDTO) (*DTO.CreatePostDTO, error) {
+// OnPublish 注册文章发布（新建即发布或草稿改为发布）后的回调，如通知搜索引擎站点地图已更新；回调不应阻塞
+func (ps *PostService) OnPublish(hook func(post *model.Post)) {
+	ps.publishHooks = append(ps.publishHooks, hook)
+}
+
+// firePublished 文章处于发布状态时依次调用发布回调
+func (ps *PostService) firePublished(post *model.Post) {
+	if post == nil || post.Status != model.PostStatusPublished {
+		return
+	}
+	for _, hook := range ps.publishHooks {
+		hook(post)
+	}
+}
+
+func (ps *PostService) CreatePost(ctx context.Context, userID uint, createPostDTO *DTO.CreatePostDTO) (*DTO.CreatePostDTO, error) {
 	var post model.Post
 	if err := copier.Copy(&post, createPostDTO); err != nil {
 		logger.Error("PostService.CreatePost copier.Copy is error!", zap.Error(err))
 		return nil, err
 	}
 	post.UserID = userID
+	if post.Status == "" {
+		post.Status = model.PostStatusPublished
+	}
+	post.CommentPolicy = normalizeCommentPolicy(post.CommentPolicy)
+	if post.ContentFormat == "" {
+		post.ContentFormat = render.FormatMarkdown
+	}
+	applyRendered(&post)
+
+	if post.CoverMediaID != nil && *post.CoverMediaID == 0 {
+		post.CoverMediaID = nil
+	}
+	if post.CoverMediaID != nil {
+		if _, err := ps.resolveCover(ctx, userID, *post.CoverMediaID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 记录正文和封面引用的媒体，供媒体库展示引用关系
+	media, err := ps.postMediaRefs(ctx, post.Content, post.CoverMediaID)
+	if err != nil {
+		logger.Error("PostService.CreatePost postMediaRefs is error!", zap.Error(err))
+		return nil, err
+	}
+	post.Media = media
 
-	postResp, err := ps.PostRepo.Create(&post)
+	// 新标签与文章在同一事务中创建，文章写入失败时不留下孤立标签
+	tagNames := normalizeTagNames(createPostDTO.TagNames)
+	var postResp *model.Post
+	err = ps.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		tags, err := repos.Tags.FindOrCreateByNames(ctx, tagNames)
+		if err != nil {
+			logger.Error("PostService.CreatePost TagRepo.FindOrCreateByNames is error!", zap.Error(err))
+			return err
+		}
+		// 事务重试时从未写入的副本重新开始
+		created := post
+		created.Tags = tags
+		postResp, err = repos.Posts.Create(ctx, &created)
+		if err != nil {
+			logger.Error("PostService.CreatePost PostRepo.Create is error!", zap.Error(err))
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		logger.Error("PostService.CreatePost PostRepo.Create is error!", zap.Error(err))
 		return nil, err
 	}
+	ps.firePublished(postResp)
 
 	var postResult DTO.CreatePostDTO
 	if err := copier.Copy(&postResult, &postResp); err != nil {
 		logger.Error("PostService.CreatePost copier.Copy is error!", zap.Error(err))
 		return nil, err
 	}
+	postResult.TagNames = tagNames
 	return &postResult, nil
 }
 
-func (ps *PostService) UpdatePost(updatePostDTO *DTO.UpdatePostDTO) (*DTO.UpdatePostDTO, error) {
+func (ps *PostService) UpdatePost(ctx context.Context, updatePostDTO *DTO.UpdatePostDTO) (*DTO.UpdatePostDTO, error) {
 	id := updatePostDTO.ID
-	post, err := ps.PostRepo.GetById(id)
+	post, err := ps.PostRepo.GetById(ctx, id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			logger.Error("文章不存在", zap.Error(err))
-			return nil, err
-		}
 		logger.Error("文章查询失败", zap.Error(err))
-		return nil, err
+		return nil, notFound(err, ErrPostNotFound)
 	}
-	user, err := ps.UserRepo.FindById(updatePostDTO.UserID)
+	user, err := ps.UserRepo.FindById(ctx, updatePostDTO.UserID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			logger.Error("用户不存在", zap.Error(err))
-			return nil, err
-		}
 		logger.Error("用户查询失败", zap.Error(err))
+		return nil, notFound(err, ErrUserNotFound)
 	}
 
 	if post.UserID != user.ID {
 		logger.Error("登录用户非文章作者，不允许更新文章")
-		return nil, errors.New("登录用户非文章作者，不允许更新文章")
+		return nil, ErrPostUpdateForbidden
 	}
 
-	updateMap := make(map[string]interface{})
-	updateMap["title"] = updatePostDTO.Title
-	updateMap["content"] = updatePostDTO.Content
-	updateMap["updated_at"] = time.Now()
-	if ps.PostRepo.Updates(id, &updateMap) != nil {
-		logger.Error("文章更新失败", zap.Error(err))
+	wasPublished := post.Status == model.PostStatusPublished
+	updateMap := postUpdateFields(post, updatePostDTO)
+	// 封面：不传保持不变，传 0 移除
+	if updatePostDTO.CoverMediaID != nil {
+		if *updatePostDTO.CoverMediaID == 0 {
+			post.CoverMediaID = nil
+		} else {
+			if _, err := ps.resolveCover(ctx, post.UserID, *updatePostDTO.CoverMediaID); err != nil {
+				return nil, err
+			}
+			post.CoverMediaID = updatePostDTO.CoverMediaID
+		}
+		updateMap["cover_media_id"] = post.CoverMediaID
+	}
+
+	// 正文或封面变化时才重新计算媒体引用，否则保持原有引用
+	replaceMedia := updatePostDTO.Content != nil || updatePostDTO.CoverMediaID != nil
+	var media []model.Media
+	if replaceMedia {
+		media, err = ps.postMediaRefs(ctx, post.Content, post.CoverMediaID)
+		if err != nil {
+			logger.Error("PostService.UpdatePost postMediaRefs is error!", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// 文章字段、媒体引用和标签在同一事务中更新
+	err = ps.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		if err := repos.Posts.Updates(ctx, id, &updateMap); err != nil {
+			logger.Error("文章更新失败", zap.Error(err))
+			return err
+		}
+		if replaceMedia {
+			if err := repos.Media.ReplacePostMedia(ctx, id, media); err != nil {
+				logger.Error("PostService.UpdatePost MediaRepo.ReplacePostMedia is error!", zap.Error(err))
+				return err
+			}
+		}
+		// 传了 tags（包括空数组）才整体替换标签，不传保持原样
+		if updatePostDTO.TagNames == nil {
+			return nil
+		}
+		tags, err := repos.Tags.FindOrCreateByNames(ctx, normalizeTagNames(updatePostDTO.TagNames))
+		if err != nil {
+			logger.Error("PostService.UpdatePost TagRepo.FindOrCreateByNames is error!", zap.Error(err))
+			return err
+		}
+		if err := repos.Tags.ReplacePostTags(ctx, id, tags); err != nil {
+			logger.Error("PostService.UpdatePost TagRepo.ReplacePostTags is error!", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	var updateAffectedPostDTO DTO.UpdatePostDTO
-	updateAffectedPost, _ := ps.PostRepo.GetById(id)
+	updateAffectedPost, _ := ps.PostRepo.GetDetailById(ctx, id)
+	// 只在草稿发布时通知，已发布文章的修改不重复通知
+	if !wasPublished {
+		ps.firePublished(updateAffectedPost)
+	}
 	if copier.Copy(&updateAffectedPostDTO, &updateAffectedPost) != nil {
 		logger.Error("PostService.UpdatePost copier.Copy is error!", zap.Error(err))
 		return nil, err
 	}
+	updateAffectedPostDTO.TagNames = tagNamesOf(updateAffectedPost.Tags)
 
 	return &updateAffectedPostDTO, nil
 }
 
+// postUpdateFields 根据更新请求生成要写入的字段（封面除外），未传的字段保持不变；
+// 正文或格式变化时重新渲染，刷新 HTML 缓存及摘要、字数和目录，post 同步为更新后的内容
+func postUpdateFields(post *model.Post, updatePostDTO *DTO.UpdatePostDTO) map[string]interface{} {
+	updateMap := make(map[string]interface{})
+	if updatePostDTO.Title != nil {
+		updateMap["title"] = *updatePostDTO.Title
+	}
+	formatChanged := updatePostDTO.ContentFormat != "" && updatePostDTO.ContentFormat != post.ContentFormat
+	if updatePostDTO.Content != nil || formatChanged {
+		if updatePostDTO.Content != nil {
+			post.Content = *updatePostDTO.Content
+			updateMap["content"] = post.Content
+		}
+		if formatChanged {
+			post.ContentFormat = updatePostDTO.ContentFormat
+		}
+		applyRendered(post)
+		updateMap["content_format"] = post.ContentFormat
+		updateMap["content_html"] = post.ContentHTML
+		updateMap["render_version"] = post.RenderVersion
+		updateMap["excerpt"] = post.Excerpt
+		updateMap["word_count"] = post.WordCount
+		updateMap["reading_minutes"] = post.ReadingMinutes
+		updateMap["toc"] = post.TOC
+	}
+	if updatePostDTO.Status != "" {
+		updateMap["status"] = updatePostDTO.Status
+	}
+	if updatePostDTO.CommentPolicy != "" {
+		updateMap["comment_policy"] = normalizeCommentPolicy(updatePostDTO.CommentPolicy)
+	}
+	if updatePostDTO.CommentAutoCloseDays != nil {
+		updateMap["comment_auto_close_days"] = *updatePostDTO.CommentAutoCloseDays
+	}
+	if updatePostDTO.SEOTitle != nil {
+		updateMap["seo_title"] = strings.TrimSpace(*updatePostDTO.SEOTitle)
+	}
+	if updatePostDTO.SEODescription != nil {
+		updateMap["seo_description"] = strings.TrimSpace(*updatePostDTO.SEODescription)
+	}
+	if updatePostDTO.CanonicalURL != nil {
+		updateMap["canonical_url"] = strings.TrimSpace(*updatePostDTO.CanonicalURL)
+	}
+	if updatePostDTO.NoIndex != nil {
+		updateMap["no_index"] = *updatePostDTO.NoIndex
+	}
+	updateMap["updated_at"] = time.Now()
+	return updateMap
+}
+
 // DeletePost 删除文章的方法
 // 参数:
 //
+//	ctx: 请求上下文
 //	id: 要删除的文章ID
 //	userId: 请求删除文章的用户ID
 //
 // 返回值:
 //
 //	error: 操作过程中遇到的错误，如果删除成功则返回nil
-func (ps *PostService) DeletePost(id uint, userId uint) error {
+func (ps *PostService) DeletePost(ctx context.Context, id uint, userId uint) error {
 	// 根据ID从数据库中获取文章信息
-	post, err := ps.PostRepo.GetById(id)
+	post, err := ps.PostRepo.GetById(ctx, id)
 	if err != nil {
-		// 检查错误是否为"未找到行"的错误
-		if errors.Is(err, sql.ErrNoRows) {
-			logger.Error("文章不存在", zap.Error(err))
-			return err
-		}
-		// 其他数据库错误处理
 		logger.Error("文章查询失败", zap.Error(err))
-		return err
+		return notFound(err, ErrPostNotFound)
 	}
 
 	// 验证请求删除的用户是否为文章作者
 	if userId != post.UserID {
 		logger.Error("登录用户非文章作者，不允许删除文章")
-		return errors.New("登录用户非文章作者，不允许删除文章")
+		return ErrPostDeleteForbidden
 	}
 
-	// 调用仓储层执行删除操作
-	err = ps.PostRepo.Delete(id)
+	// 文章与其下评论在同一事务中删除，避免只删掉其中一部分
+	return ps.uow.Do(ctx, func(ctx context.Context, repos *repo.Repositories) error {
+		if err := repos.Posts.Delete(ctx, id); err != nil {
+			logger.Error("文章删除失败", zap.Error(err))
+			return err
+		}
+		if err := repos.Comments.DeleteByPostId(ctx, post.ID); err != nil {
+			logger.Error("文章删除失败", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+}
 
-	if err != nil {
-		logger.Error("文章删除失败", zap.Error(err))
-		return err
+func (ps *PostService) PostList(ctx context.Context, listPostDTO *DTO.ListPostDTO) (*DTO.PostListDTO, error) {
+	// 未指定状态时只看已发布文章；草稿只允许作者本人查看
+	if listPostDTO.Status == "" {
+		listPostDTO.Status = model.PostStatusPublished
 	}
-
-	err = ps.CommentRepo.DeleteByPostId(post.ID)
-	if err != nil {
-		logger.Error("文章删除失败", zap.Error(err))
-		return err
+	if listPostDTO.Status == model.PostStatusDraft && listPostDTO.AuthorID != listPostDTO.UserID {
+		logger.Warn("PostService.PostList 非作者本人查询草稿", zap.Uint("user_id", listPostDTO.UserID), zap.Uint("author_id", listPostDTO.AuthorID))
+		return nil, ErrDraftForbidden
 	}
-	return nil
-}
 
-func (ps *PostService) PostList(listPostDTO *DTO.ListPostDTO) (*DTO.PostListDTO, error) {
-	posts, total, err := ps.PostRepo.ListPosts(listPostDTO)
+	posts, total, err := ps.PostRepo.ListPosts(ctx, listPostDTO)
 	if err != nil {
 		logger.Error("PostService.PostList PostRepo.ListPosts is error!", zap.Error(err))
 		return nil, err
@@ -154,59 +328,330 @@ func (ps *PostService) PostList(listPostDTO *DTO.ListPostDTO) (*DTO.PostListDTO,
 		logger.Error("PostService.PostList copier.Copy is error!", zap.Error(err))
 		return nil, err
 	}
+	for i, post := range *posts {
+		postDTO[i].TagNames = tagNamesOf(post.Tags)
+		if post.CoverMedia != nil {
+			postDTO[i].CoverURL = ps.MediaStorage.URL(post.CoverMedia.StorageKey)
+		}
+		if post.RenderVersion != render.Version {
+			// 历史文章尚未生成摘要，列表中临时计算，详情访问时再回写
+			applyRendered(&post)
+			postDTO[i].Excerpt = post.Excerpt
+			postDTO[i].WordCount = post.WordCount
+			postDTO[i].ReadingMinutes = post.ReadingMinutes
+		}
+	}
+
 	var postListDTO DTO.PostListDTO
 	postListDTO.Posts = postDTO
 	postListDTO.Total = total
 	postListDTO.PageNum = listPostDTO.PageNum
 	postListDTO.PageSize = listPostDTO.PageSize
+	postListDTO.Filters = DTO.PostListFilterDTO{
+		Keyword:  listPostDTO.Keyword,
+		AuthorID: listPostDTO.AuthorID,
+		Status:   listPostDTO.Status,
+		Tag:      listPostDTO.Tag,
+		Sort:     listPostDTO.Sort,
+	}
+	if listPostDTO.StartTime != nil {
+		postListDTO.Filters.StartDate = listPostDTO.StartTime.Format("2006-01-02")
+	}
+	if listPostDTO.EndTime != nil {
+		// EndTime 为结束日期次日零点，回显时还原为用户传入的结束日期
+		postListDTO.Filters.EndDate = listPostDTO.EndTime.AddDate(0, 0, -1).Format("2006-01-02")
+	}
 	return &postListDTO, nil
 
 }
 
-func (ps *PostService) PostDetail(postId uint) (*DTO.PostDetailDTO, error) {
-	post, err := ps.PostRepo.GetById(postId)
+// postVisibleTo 已发布的文章所有人可见，草稿只有作者本人可见
+func postVisibleTo(post *model.Post, userID uint) bool {
+	return post.Status == model.PostStatusPublished || post.UserID == userID
+}
+
+type staticRenderKey struct{}
+
+// WithStaticRender 标记请求用于生成静态页面：文章详情不计浏览数、不回写渲染缓存，也不输出浏览数
+func WithStaticRender(ctx context.Context) context.Context {
+	return context.WithValue(ctx, staticRenderKey{}, true)
+}
+
+func isStaticRender(ctx context.Context) bool {
+	static, _ := ctx.Value(staticRenderKey{}).(bool)
+	return static
+}
+
+// PostDetail 文章详情；草稿只允许作者本人查看，其他用户（userID 为 0 表示游客）按不存在处理
+func (ps *PostService) PostDetail(ctx context.Context, postId uint, userID uint) (*DTO.PostDetailDTO, error) {
+	post, err := ps.PostRepo.GetDetailById(ctx, postId)
 	if err != nil {
-		logger.Error("PostService.PostDetail PostRepo.GetById is error!", zap.Error(err))
-		return nil, err
+		logger.Error("PostService.PostDetail PostRepo.GetDetailById is error!", zap.Error(err))
+		return nil, notFound(err, ErrPostNotFound)
+	}
+	if !postVisibleTo(post, userID) {
+		logger.Warn("PostService.PostDetail 非作者本人查看草稿", zap.Uint("user_id", userID), zap.Uint("post_id", postId))
+		return nil, ErrPostNotFound
+	}
+	published := post.Status == model.PostStatusPublished
+
+	// 只统计已发布文章的浏览数，失败不影响详情返回；静态导出不计数
+	static := isStaticRender(ctx)
+	if published && !static {
+		if err := ps.PostRepo.IncrViewCount(ctx, postId); err != nil {
+			logger.Warn("PostService.PostDetail PostRepo.IncrViewCount is error!", zap.Error(err))
+		}
+		post.ViewCount++
 	}
 
-	user, err := ps.UserRepo.FindById(post.UserID)
+	user, err := ps.UserRepo.FindById(ctx, post.UserID)
 	if err != nil {
 		logger.Error("PostService.PostDetail UserRepo.FindById is error!", zap.Error(err))
 		return nil, err
 	}
 
-	var listCommentDTO DTO.ListCommentDTO
-	listCommentDTO.PostId = postId
-	listCommentDTO.PageNum = 1
-	listCommentDTO.PageSize = 10
-	comments, _, err := ps.CommentRepo.ListComments(listCommentDTO)
+	comments, err := ps.CommentRepo.ListByPostId(ctx, postId)
 	if err != nil {
-		logger.Error("PostService.PostDetail CommentRepo.ListComments is error!", zap.Error(err))
+		logger.Error("PostService.PostDetail CommentRepo.ListByPostId is error!", zap.Error(err))
 		return nil, err
 	}
-
-	var commentDetailsDTO []DTO.CommentDetailDTO
-	for _, comment := range *comments {
-		var commentDetailDTO DTO.CommentDetailDTO
-		commentDetailDTO.ID = comment.ID
-		commentDetailDTO.Content = comment.Content
-		commentDetailDTO.UserID = comment.UserID
-		commentDetailDTO.CreatedAt = comment.CreatedAt.Format("2006-01-02 15:04:05")
-		commentDetailDTO.UpdatedAt = comment.UpdatedAt.Format("2006-01-02 15:04:05")
-		commentDetailsDTO = append(commentDetailsDTO, commentDetailDTO)
-	}
+	commentDetailsDTO := buildCommentTree(comments)
 
 	var postDetailDTO DTO.PostDetailDTO
 	postDetailDTO.ID = post.ID
 	postDetailDTO.Title = post.Title
 	postDetailDTO.Content = post.Content
+	postDetailDTO.ContentFormat = post.ContentFormat
+	if post.RenderVersion != render.Version {
+		// 渲染器升级后首次访问时回写缓存，失败不影响本次返回；静态导出只在内存中重新渲染
+		applyRendered(post)
+		if !static {
+			if err := ps.PostRepo.UpdateRendered(ctx, post); err != nil {
+				logger.Warn("PostService.PostDetail PostRepo.UpdateRendered is error!", zap.Error(err))
+			}
+		}
+	}
+	postDetailDTO.ContentHTML = post.ContentHTML
+	postDetailDTO.WordCount = post.WordCount
+	postDetailDTO.ReadingMinutes = post.ReadingMinutes
+	postDetailDTO.TOC = tocOf(post.TOC)
 	postDetailDTO.CreatedAt = post.CreatedAt.Format("2006-01-02 15:04:05")
 	postDetailDTO.UpdatedAt = post.UpdatedAt.Format("2006-01-02 15:04:05")
 	postDetailDTO.UserID = post.UserID
+	postDetailDTO.Status = post.Status
+	// 静态页面不会随访问更新，不输出浏览数
+	if !static {
+		postDetailDTO.ViewCount = post.ViewCount
+	}
+	postDetailDTO.TagNames = tagNamesOf(post.Tags)
 	postDetailDTO.Username = user.Username
 	postDetailDTO.Comments = commentDetailsDTO
+	postDetailDTO.CommentPolicy = effectiveCommentPolicy(post)
+	postDetailDTO.Cover = ps.coverOf(post)
+	postDetailDTO.SEOTitle = post.SEOTitle
+	postDetailDTO.SEODescription = post.SEODescription
+	postDetailDTO.CanonicalURL = post.CanonicalURL
+	postDetailDTO.NoIndex = post.NoIndex
+	postDetailDTO.Meta = buildPostMeta(post, user.Username, postDetailDTO.Cover)
 
 	return &postDetailDTO, nil
 
 }
+
+// renderContent 将内容渲染为安全 HTML；格式无法识别时按纯文本渲染，保证不会输出未清洗的内容
+func renderContent(content string, format string) string {
+	html, err := render.Render(content, format)
+	if err != nil {
+		logger.Warn("内容渲染失败，按纯文本渲染", zap.String("format", format), zap.Error(err))
+		html, _ = render.Render(content, render.FormatPlain)
+	}
+	return html
+}
+
+// resolveCover 校验封面图片：必须是作者本人上传的媒体
+func (ps *PostService) resolveCover(ctx context.Context, authorID uint, mediaID uint) (*model.Media, error) {
+	media, err := ps.MediaRepo.GetById(ctx, mediaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCoverNotFound
+		}
+		logger.Error("PostService.resolveCover MediaRepo.GetById is error!", zap.Error(err))
+		return nil, err
+	}
+	if media.UserID != authorID {
+		logger.Warn("封面图片不属于文章作者", zap.Uint("media_id", mediaID), zap.Uint("author_id", authorID))
+		return nil, ErrCoverForbidden
+	}
+	return media, nil
+}
+
+// postMediaRefs 文章引用的媒体：正文中出现的媒体加上封面
+func (ps *PostService) postMediaRefs(ctx context.Context, content string, coverMediaID *uint) ([]model.Media, error) {
+	media, err := ps.MediaRepo.FindByUIDs(ctx, mediaUIDsIn(content))
+	if err != nil {
+		return nil, err
+	}
+	if coverMediaID == nil {
+		return media, nil
+	}
+	for _, item := range media {
+		if item.ID == *coverMediaID {
+			return media, nil
+		}
+	}
+	cover, err := ps.MediaRepo.GetById(ctx, *coverMediaID)
+	if err != nil {
+		return nil, err
+	}
+	return append(media, *cover), nil
+}
+
+// coverOf 文章封面，未设置时返回 nil
+func (ps *PostService) coverOf(post *model.Post) *DTO.PostCoverDTO {
+	if post.CoverMedia == nil {
+		return nil
+	}
+	cover := &DTO.PostCoverDTO{
+		MediaID:    post.CoverMedia.ID,
+		URL:        ps.MediaStorage.URL(post.CoverMedia.StorageKey),
+		Width:      post.CoverMedia.Width,
+		Height:     post.CoverMedia.Height,
+		Thumbnails: []DTO.MediaThumbnailDTO{},
+	}
+	for _, thumbnail := range thumbnailsOf(post.CoverMedia) {
+		cover.Thumbnails = append(cover.Thumbnails, DTO.MediaThumbnailDTO{
+			Width:  thumbnail.Width,
+			Height: thumbnail.Height,
+			URL:    ps.MediaStorage.URL(thumbnail.Key),
+		})
+	}
+	return cover
+}
+
+// buildPostMeta 生成文章页元数据：SEO 字段为空时依次回退到文章标题、摘要、本站文章地址和站点默认分享图
+func buildPostMeta(post *model.Post, authorName string, cover *DTO.PostCoverDTO) DTO.PostMetaDTO {
+	site := config.Conf.Site
+	article := seo.Article{
+		Title:        post.SEOTitle,
+		Description:  post.SEODescription,
+		URL:          post.CanonicalURL,
+		AuthorName:   authorName,
+		PublishedAt:  post.CreatedAt,
+		ModifiedAt:   post.UpdatedAt,
+		Tags:         tagNamesOf(post.Tags),
+		WordCount:    post.WordCount,
+		NoIndex:      post.NoIndex || post.Status != model.PostStatusPublished,
+		SiteName:     site.Name,
+		Locale:       site.Language,
+		TwitterSite:  site.TwitterSite,
+		PublisherURL: site.AbsoluteURL("/"),
+	}
+	if article.Title == "" {
+		article.Title = post.Title
+	}
+	if article.Description == "" {
+		// 分享卡片描述一般只展示 160 字以内
+		article.Description = render.Truncate(post.Excerpt, 160)
+	}
+	if article.URL == "" {
+		article.URL = site.PostURL(post.ID)
+	}
+	if cover != nil {
+		article.Image = &seo.Image{URL: site.AbsoluteURL(cover.URL), Width: cover.Width, Height: cover.Height, Alt: post.Title}
+	} else if site.DefaultImage != "" {
+		article.Image = &seo.Image{URL: site.AbsoluteURL(site.DefaultImage), Alt: site.Name}
+	}
+
+	meta := seo.BuildArticle(article)
+	metaDTO := DTO.PostMetaDTO{
+		Title:        meta.Title,
+		Description:  meta.Description,
+		CanonicalURL: meta.CanonicalURL,
+		Robots:       meta.Robots,
+		Tags:         make([]DTO.MetaTagDTO, 0, len(meta.Tags)),
+		JSONLD:       meta.JSONLD,
+	}
+	for _, tag := range meta.Tags {
+		metaDTO.Tags = append(metaDTO.Tags, DTO.MetaTagDTO{Attr: tag.Attr, Key: tag.Key, Content: tag.Content})
+	}
+	return metaDTO
+}
+
+// applyRendered 渲染文章内容，并据此生成摘要、字数、阅读时长和目录
+func applyRendered(post *model.Post) {
+	post.ContentHTML = renderContent(post.Content, post.ContentFormat)
+	post.RenderVersion = render.Version
+
+	// 有 <!--more--> 时以标记之前的内容作为摘要，不再截断；否则取正文前 N 个字
+	if head, ok := render.SplitMore(post.Content); ok {
+		post.Excerpt = render.PlainText(renderContent(head, post.ContentFormat))
+	} else {
+		post.Excerpt = render.Truncate(render.PlainText(post.ContentHTML), config.Conf.Post.ExcerptLength)
+	}
+
+	cjk, words := render.CountWords(render.PlainText(post.ContentHTML))
+	post.WordCount = cjk + words
+	post.ReadingMinutes = render.ReadingMinutes(cjk, words, config.Conf.Post.CJKCharsPerMinute, config.Conf.Post.WordsPerMinute)
+
+	post.TOC = ""
+	if toc := render.TOC(post.ContentHTML); len(toc) > 0 {
+		if data, err := json.Marshal(toc); err == nil {
+			post.TOC = string(data)
+		}
+	}
+}
+
+// tocOf 解析库中存储的目录 JSON
+func tocOf(toc string) []DTO.TOCItemDTO {
+	items := []DTO.TOCItemDTO{}
+	if toc == "" {
+		return items
+	}
+	if err := json.Unmarshal([]byte(toc), &items); err != nil {
+		logger.Warn("文章目录解析失败", zap.Error(err))
+	}
+	return items
+}
+
+// cachedHTML 返回内容的 HTML：缓存与当前渲染器版本一致时直接使用，否则重新渲染并返回 stale=true
+func cachedHTML(content string, format string, html string, renderVersion int) (string, bool) {
+	if renderVersion == render.Version {
+		return html, false
+	}
+	return renderContent(content, format), true
+}
+
+// normalizeCommentPolicy 请求中的 inherit 对应库中的空串（继承全局配置）
+func normalizeCommentPolicy(policy string) string {
+	if policy == "inherit" {
+		return model.CommentPolicyInherit
+	}
+	return policy
+}
+
+// normalizeTagNames 标签名去首尾空格、去空、去重，保持原有顺序
+func normalizeTagNames(names []string) []string {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		result = append(result, name)
+	}
+	return result
+}
+
+// tagNamesOf 提取标签名列表
+func tagNamesOf(tags []model.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
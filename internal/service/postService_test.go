@@ -0,0 +1,92 @@
+package service
+
+import (
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/render"
+	"testing"
+)
+
+func setPostConfig(t *testing.T) {
+	t.Helper()
+	previous := config.Conf
+	conf := &config.AppConfig{}
+	conf.Post.ExcerptLength = 200
+	conf.Post.CJKCharsPerMinute = 400
+	conf.Post.WordsPerMinute = 200
+	config.Conf = conf
+	t.Cleanup(func() { config.Conf = previous })
+}
+
+func renderedPost() *model.Post {
+	post := &model.Post{Title: "原标题", Content: "# 第一节\n\n正文 ![](/uploads/a.png)", ContentFormat: render.FormatMarkdown}
+	applyRendered(post)
+	return post
+}
+
+func TestPostUpdateFieldsPartial(t *testing.T) {
+	setPostConfig(t)
+	post := renderedPost()
+	before := *post
+
+	status := "draft"
+	noIndex := true
+	updateMap := postUpdateFields(post, &DTO.UpdatePostDTO{Status: status, NoIndex: &noIndex})
+
+	// 只传状态和 SEO 字段时，标题、正文及渲染结果都不写入
+	for _, column := range []string{"title", "content", "content_format", "content_html", "excerpt", "toc", "word_count"} {
+		if _, ok := updateMap[column]; ok {
+			t.Errorf("部分更新不应写入 %s", column)
+		}
+	}
+	if updateMap["status"] != status || updateMap["no_index"] != true {
+		t.Errorf("updateMap = %v", updateMap)
+	}
+	if post.Content != before.Content || post.ContentHTML != before.ContentHTML || post.TOC != before.TOC {
+		t.Error("部分更新不应改变文章正文及渲染结果")
+	}
+}
+
+func TestPostUpdateFieldsContent(t *testing.T) {
+	setPostConfig(t)
+	post := renderedPost()
+
+	title := "新标题"
+	content := "## 新小节\n\nHello world"
+	updateMap := postUpdateFields(post, &DTO.UpdatePostDTO{Title: &title, Content: &content})
+
+	if updateMap["title"] != title || updateMap["content"] != content {
+		t.Errorf("updateMap = %v", updateMap)
+	}
+	if post.Content != content || updateMap["content_html"] != post.ContentHTML || post.ContentHTML == "" {
+		t.Error("更新正文后应重新渲染")
+	}
+	if updateMap["excerpt"] != post.Excerpt || post.Excerpt == "" {
+		t.Error("更新正文后应重新生成摘要")
+	}
+	if post.TOC == "" || updateMap["toc"] != post.TOC {
+		t.Error("更新正文后应重新生成目录")
+	}
+}
+
+func TestPostUpdateFieldsFormatOnly(t *testing.T) {
+	setPostConfig(t)
+	post := renderedPost()
+	before := post.ContentHTML
+
+	// 只改格式时用原正文重新渲染，但不写入正文
+	updateMap := postUpdateFields(post, &DTO.UpdatePostDTO{ContentFormat: render.FormatPlain})
+	if _, ok := updateMap["content"]; ok {
+		t.Error("只改格式不应写入正文")
+	}
+	if updateMap["content_format"] != render.FormatPlain || post.ContentHTML == before {
+		t.Errorf("改格式后应重新渲染: %v", updateMap)
+	}
+
+	// 格式与原来相同时不重新渲染
+	updateMap = postUpdateFields(post, &DTO.UpdatePostDTO{ContentFormat: render.FormatPlain})
+	if _, ok := updateMap["content_html"]; ok {
+		t.Error("格式未变化时不应重新渲染")
+	}
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"go-my-blog/config"
+	"go-my-blog/internal/repo"
+	"net/url"
+)
+
+// RedirectService 迁移前旧地址到本站文章的永久重定向
+type RedirectService struct {
+	redirectRepo *repo.RedirectRepository
+}
+
+func NewRedirectService(redirectRepo *repo.RedirectRepository) *RedirectService {
+	return &RedirectService{
+		redirectRepo: redirectRepo,
+	}
+}
+
+// Resolve 查找旧地址对应的文章地址；未配置重定向时返回 gorm.ErrRecordNotFound
+func (rs *RedirectService) Resolve(ctx context.Context, u *url.URL) (string, error) {
+	candidates := []string{RedirectPath(u.EscapedPath())}
+	if u.RawQuery != "" {
+		// 优先匹配带查询参数的地址（如 WordPress 的 /?p=12）
+		candidates = append([]string{RedirectPath(u.RequestURI())}, candidates...)
+	}
+	redirect, err := rs.redirectRepo.FindByPaths(ctx, candidates)
+	if err != nil {
+		return "", err
+	}
+	return config.Conf.Site.PostURL(redirect.PostID), nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"go-my-blog/config"
+	"go-my-blog/internal/model"
+	"go-my-blog/internal/repo"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/sitemap"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SitemapPath 站点地图入口地址
+const SitemapPath = "/sitemap.xml"
+
+type SitemapService struct {
+	postRepo *repo.PostRepository
+	client   *http.Client
+}
+
+func NewSitemapService(postRepo *repo.PostRepository) *SitemapService {
+	return &SitemapService{
+		postRepo: postRepo,
+		client:   &http.Client{Timeout: time.Duration(config.Conf.Sitemap.PingTimeoutS) * time.Second},
+	}
+}
+
+// Root 站点地图入口：URL 总数不超过上限时直接返回全部 URL；否则返回 sitemap 索引，索引项为 /sitemaps/{n}.xml
+func (ss *SitemapService) Root(ctx context.Context) ([]sitemap.URL, []sitemap.Sitemap, error) {
+	postTotal, extra, err := ss.prepare(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	total := 1 + int(postTotal) + len(extra)
+	maxURLs := config.Conf.Sitemap.MaxURLs
+	if total <= maxURLs {
+		urls, err := ss.urlsBetween(ctx, 0, total, int(postTotal), extra)
+		return urls, nil, err
+	}
+
+	pages := (total + maxURLs - 1) / maxURLs
+	sitemaps := make([]sitemap.Sitemap, 0, pages)
+	for page := 1; page <= pages; page++ {
+		sitemaps = append(sitemaps, sitemap.Sitemap{Loc: config.Conf.Site.AbsoluteURL("/sitemaps/" + strconv.Itoa(page) + ".xml")})
+	}
+	return nil, sitemaps, nil
+}
+
+// Page 拆分后的第 page 个站点地图（从 1 开始），页码超出范围时返回 nil
+func (ss *SitemapService) Page(ctx context.Context, page int) ([]sitemap.URL, error) {
+	postTotal, extra, err := ss.prepare(ctx)
+	if err != nil {
+		return nil, err
+	}
+	total := 1 + int(postTotal) + len(extra)
+	maxURLs := config.Conf.Sitemap.MaxURLs
+	start := (page - 1) * maxURLs
+	if page < 1 || start >= total {
+		return nil, nil
+	}
+	end := start + maxURLs
+	if end > total {
+		end = total
+	}
+	return ss.urlsBetween(ctx, start, end, int(postTotal), extra)
+}
+
+// RobotsTxt 根据配置生成 robots.txt，并附上站点地图地址
+func (ss *SitemapService) RobotsTxt() string {
+	robots := config.Conf.Robots
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if robots.DisallowAll {
+		b.WriteString("Disallow: /\n")
+	} else {
+		for _, path := range robots.Allow {
+			b.WriteString("Allow: " + path + "\n")
+		}
+		for _, path := range robots.Disallow {
+			b.WriteString("Disallow: " + path + "\n")
+		}
+		if len(robots.Allow) == 0 && len(robots.Disallow) == 0 {
+			b.WriteString("Disallow:\n")
+		}
+	}
+	for _, line := range robots.Extra {
+		b.WriteString(line + "\n")
+	}
+	if !robots.DisallowAll {
+		b.WriteString("\nSitemap: " + config.Conf.Site.AbsoluteURL(SitemapPath) + "\n")
+	}
+	return b.String()
+}
+
+// PingSearchEngines 文章发布后异步通知配置的地址站点地图已更新，失败只记录日志
+func (ss *SitemapService) PingSearchEngines(post *model.Post) {
+	pingURLs := config.Conf.Sitemap.PingURLs
+	if len(pingURLs) == 0 || post.Status != model.PostStatusPublished || post.NoIndex || config.Conf.Robots.DisallowAll {
+		return
+	}
+	sitemapURL := url.QueryEscape(config.Conf.Site.AbsoluteURL(SitemapPath))
+	go func() {
+		for _, pingURL := range pingURLs {
+			target := strings.ReplaceAll(pingURL, "{sitemap}", sitemapURL)
+			resp, err := ss.client.Get(target)
+			if err != nil {
+				logger.Warn("站点地图通知失败", zap.String("url", target), zap.Error(err))
+				continue
+			}
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				logger.Warn("站点地图通知返回异常状态码", zap.String("url", target), zap.Int("status", resp.StatusCode))
+				continue
+			}
+			logger.Info("站点地图通知成功", zap.String("url", target), zap.Uint("post_id", post.ID))
+		}
+	}()
+}
+
+// prepare 统计文章数，并生成标签页和作者页的 URL；这两类数量较少，直接全部加载
+// 文章页、标签页和作者页由内置前端提供，未启用前端时这些地址不存在，站点地图只保留首页
+func (ss *SitemapService) prepare(ctx context.Context) (int64, []sitemap.URL, error) {
+	if !config.Conf.Frontend.Enabled {
+		return 0, nil, nil
+	}
+	postTotal, err := ss.postRepo.CountSitemapPosts(ctx)
+	if err != nil {
+		logger.Error("站点地图文章统计失败", zap.Error(err))
+		return 0, nil, err
+	}
+	tags, err := ss.postRepo.ListTagLastMods(ctx)
+	if err != nil {
+		logger.Error("站点地图标签查询失败", zap.Error(err))
+		return 0, nil, err
+	}
+	authors, err := ss.postRepo.ListAuthorLastMods(ctx)
+	if err != nil {
+		logger.Error("站点地图作者查询失败", zap.Error(err))
+		return 0, nil, err
+	}
+
+	site := config.Conf.Site
+	extra := make([]sitemap.URL, 0, len(tags)+len(authors))
+	for _, tag := range tags {
+		extra = append(extra, sitemap.URL{Loc: site.AbsoluteURL("/tags/" + url.PathEscape(tag.Name)), LastMod: tag.LastMod})
+	}
+	for _, author := range authors {
+		extra = append(extra, sitemap.URL{Loc: site.AbsoluteURL("/authors/" + url.PathEscape(author.Name)), LastMod: author.LastMod})
+	}
+	return postTotal, extra, nil
+}
+
+// urlsBetween 取全部 URL 中 [start, end) 这一段，顺序为：首页、文章（按 ID）、标签页、作者页
+func (ss *SitemapService) urlsBetween(ctx context.Context, start int, end int, postTotal int, extra []sitemap.URL) ([]sitemap.URL, error) {
+	site := config.Conf.Site
+	urls := make([]sitemap.URL, 0, end-start)
+	index := start
+	if index == 0 && index < end {
+		home := sitemap.URL{Loc: site.AbsoluteURL("/")}
+		for _, item := range extra {
+			if item.LastMod.After(home.LastMod) {
+				home.LastMod = item.LastMod
+			}
+		}
+		urls = append(urls, home)
+		index++
+	}
+
+	postEnd := 1 + postTotal
+	if index < postEnd && index < end {
+		limit := postEnd - index
+		if end-index < limit {
+			limit = end - index
+		}
+		posts, err := ss.postRepo.ListSitemapPosts(ctx, index-1, limit)
+		if err != nil {
+			logger.Error("站点地图文章查询失败", zap.Error(err))
+			return nil, err
+		}
+		for _, post := range posts {
+			urls = append(urls, sitemap.URL{Loc: site.PostURL(post.ID), LastMod: post.UpdatedAt})
+		}
+		index += len(posts)
+		// 查询期间文章数变化时以实际结果为准，避免后续下标错位
+		if len(posts) < limit {
+			index = postEnd
+		}
+	}
+
+	for ; index < end && index-postEnd < len(extra); index++ {
+		urls = append(urls, extra[index-postEnd])
+	}
+	return urls, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"go-my-blog/config"
+	"go-my-blog/config/priority_config"
+	"go-my-blog/internal/model"
+	"go-my-blog/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// 日志输出到控制台且只记录错误，避免测试中写日志文件
+	priority_config.PriorityConf.Gin.Debug = true
+	priority_config.PriorityConf.Log.Level = "error"
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+func setSitemapConfig(t *testing.T, conf *config.AppConfig) {
+	t.Helper()
+	previous := config.Conf
+	conf.Site.BaseURL = "https://blog.example.com"
+	conf.Sitemap.MaxURLs = 50000
+	config.Conf = conf
+	t.Cleanup(func() { config.Conf = previous })
+}
+
+func TestPingOnPublish(t *testing.T) {
+	pings := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings <- r.URL.Query().Get("sitemap")
+	}))
+	defer server.Close()
+
+	conf := &config.AppConfig{}
+	conf.Sitemap.PingURLs = []string{server.URL + "/ping?sitemap={sitemap}"}
+	setSitemapConfig(t, conf)
+
+	ss := NewSitemapService(nil)
+	ps := &PostService{}
+	ps.OnPublish(ss.PingSearchEngines)
+
+	// 草稿和不允许收录的文章不通知
+	ps.firePublished(&model.Post{ID: 1, Status: model.PostStatusDraft})
+	ps.firePublished(&model.Post{ID: 2, Status: model.PostStatusPublished, NoIndex: true})
+	ps.firePublished(&model.Post{ID: 3, Status: model.PostStatusPublished})
+
+	select {
+	case got := <-pings:
+		if want := "https://blog.example.com/sitemap.xml"; got != want {
+			t.Errorf("通知中的站点地图地址 = %q, want %q", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("发布文章后未通知站点地图更新")
+	}
+	select {
+	case got := <-pings:
+		t.Errorf("只应通知一次，多收到一次通知: %q", got)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestPingSkippedWhenRobotsDisallowAll(t *testing.T) {
+	pings := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings <- struct{}{}
+	}))
+	defer server.Close()
+
+	conf := &config.AppConfig{}
+	conf.Sitemap.PingURLs = []string{server.URL + "/ping?sitemap={sitemap}"}
+	conf.Robots.DisallowAll = true
+	setSitemapConfig(t, conf)
+
+	NewSitemapService(nil).PingSearchEngines(&model.Post{ID: 1, Status: model.PostStatusPublished})
+	select {
+	case <-pings:
+		t.Error("禁止爬虫时不应通知")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestSitemapWithoutFrontend(t *testing.T) {
+	setSitemapConfig(t, &config.AppConfig{})
+
+	// 未启用前端时不查询文章、标签和作者，只保留首页
+	urls, sitemaps, err := NewSitemapService(nil).Root(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sitemaps != nil || len(urls) != 1 || urls[0].Loc != "https://blog.example.com/" {
+		t.Errorf("urls = %+v, sitemaps = %+v, want 只有首页", urls, sitemaps)
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"go-my-blog/internal/repo"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/spam"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type SpamService struct {
+	spamRepo    *repo.SpamRepository
+	commentRepo *repo.CommentRepository
+}
+
+func NewSpamService(spamRepo *repo.SpamRepository, commentRepo *repo.CommentRepository) *SpamService {
+	return &SpamService{
+		spamRepo:    spamRepo,
+		commentRepo: commentRepo,
+	}
+}
+
+// Check 对评论进行垃圾检测：先跑规则，再跑贝叶斯分类器，取最高分作为最终得分
+// 规则命中的得分：屏蔽IP/邮箱/词为 1；重复评论达到拒绝阈值；链接过多达到审核阈值
+func (ss *SpamService) Check(ctx context.Context, d *DTO.SpamCheckDTO) *DTO.SpamVerdictDTO {
+	spamConf := config.Conf.Spam
+	if !spamConf.Enabled {
+		return &DTO.SpamVerdictDTO{Reasons: []string{}}
+	}
+
+	var signals spamSignals
+	since := time.Now().Add(-time.Duration(spamConf.DuplicateWindowMinutes) * time.Minute)
+	duplicates, err := ss.commentRepo.CountDuplicates(ctx, d.UserID, d.IP, d.Content, since)
+	if err != nil {
+		// 检测失败不阻塞发表评论，交给其余规则
+		logger.Warn("SpamService.Check 重复评论检测失败", zap.Error(err))
+	} else {
+		signals.duplicates = duplicates
+	}
+	signals.bayesScore, signals.bayesOK = ss.bayesScore(ctx, d.Content)
+
+	verdict := judgeSpam(d, &spamConf, signals)
+	if verdict.Status != "" {
+		logger.Info("评论命中垃圾过滤", zap.Uint("user_id", d.UserID), zap.Float64("score", verdict.Score), zap.Strings("reasons", verdict.Reasons))
+	}
+	return verdict
+}
+
+// spamSignals 需要查询数据库的检测结果
+type spamSignals struct {
+	duplicates int64   // 时间窗口内的重复评论数
+	bayesScore float64 // 贝叶斯得分
+	bayesOK    bool    // 分类器样本充足，得分参与判定
+}
+
+// judgeSpam 按规则和分类器结果计算得分，并按阈值给出建议的评论状态
+func judgeSpam(d *DTO.SpamCheckDTO, spamConf *config.SpamConfig, signals spamSignals) *DTO.SpamVerdictDTO {
+	verdict := &DTO.SpamVerdictDTO{Reasons: []string{}}
+	hit := func(score float64, reason string) {
+		verdict.Score = math.Max(verdict.Score, score)
+		verdict.Reasons = append(verdict.Reasons, reason)
+	}
+
+	if matchBlockedIP(d.IP, spamConf.BlockedIPs) {
+		hit(1, "blocked_ip")
+	}
+	if matchBlockedEmail(d.Email, spamConf.BlockedEmails) {
+		hit(1, "blocked_email")
+	}
+	if word, ok := matchBlockedWord(d.Content, spamConf.BlockedWords); ok {
+		hit(1, "blocked_word:"+word)
+	}
+	if links := spam.CountLinks(d.Content); links > spamConf.MaxLinks {
+		hit(spamConf.ModerateThreshold, "too_many_links")
+	}
+	if signals.duplicates > 0 {
+		hit(spamConf.RejectThreshold, "duplicate")
+	}
+	if signals.bayesOK {
+		verdict.Score = math.Max(verdict.Score, signals.bayesScore)
+		if signals.bayesScore >= spamConf.ModerateThreshold {
+			verdict.Reasons = append(verdict.Reasons, "bayes")
+		}
+	}
+
+	switch {
+	case verdict.Score >= spamConf.RejectThreshold:
+		verdict.Status = model.CommentStatusSpam
+	case verdict.Score >= spamConf.ModerateThreshold:
+		verdict.Status = model.CommentStatusPending
+	}
+	return verdict
+}
+
+// Train 版主标记垃圾/正常评论时训练分类器：previous 为该评论上一次训练使用的标签，
+// 与 label 相同时不重复训练，不同时先撤销上一次训练再按新标签训练
+func (ss *SpamService) Train(ctx context.Context, content string, previous string, label string) error {
+	if previous == label {
+		return nil
+	}
+	tokens := spam.Tokenize(content)
+	if previous != "" {
+		if err := ss.spamRepo.Retrain(ctx, tokens, previous == model.SpamLabelSpam, label == model.SpamLabelSpam); err != nil {
+			logger.Error("SpamService.Train spamRepo.Retrain is error", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+	if err := ss.spamRepo.Train(ctx, tokens, label == model.SpamLabelSpam); err != nil {
+		logger.Error("SpamService.Train spamRepo.Train is error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// bayesScore 贝叶斯打分；样本不足时分类器不可靠，返回 false 表示不参与判定
+func (ss *SpamService) bayesScore(ctx context.Context, content string) (float64, bool) {
+	tokens := spam.Tokenize(content)
+	if len(tokens) == 0 {
+		return 0, false
+	}
+	counts, spamDocs, hamDocs, err := ss.spamRepo.GetTokenCounts(ctx, tokens)
+	if err != nil {
+		logger.Warn("SpamService.bayesScore 词频查询失败", zap.Error(err))
+		return 0, false
+	}
+	minDocs := config.Conf.Spam.BayesMinDocs
+	if spamDocs < minDocs || hamDocs < minDocs {
+		return 0, false
+	}
+	return spam.Classify(tokens, counts, spamDocs, hamDocs), true
+}
+
+// matchBlockedIP IP 是否在屏蔽列表中，列表项可以是单个IP或 CIDR
+func matchBlockedIP(ip string, blocked []string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, item := range blocked {
+		if strings.Contains(item, "/") {
+			if _, ipNet, err := net.ParseCIDR(item); err == nil && ipNet.Contains(parsed) {
+				return true
+			}
+			continue
+		}
+		if blockedIP := net.ParseIP(item); blockedIP != nil && blockedIP.Equal(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchBlockedEmail 邮箱是否在屏蔽列表中，@domain 形式匹配整个域名
+func matchBlockedEmail(email string, blocked []string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false
+	}
+	for _, item := range blocked {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			continue
+		}
+		if strings.HasPrefix(item, "@") && strings.HasSuffix(email, item) {
+			return true
+		}
+		if email == item {
+			return true
+		}
+	}
+	return false
+}
+
+// matchBlockedWord 内容是否包含屏蔽词（不区分大小写），返回命中的词
+func matchBlockedWord(content string, blocked []string) (string, bool) {
+	lower := strings.ToLower(content)
+	for _, word := range blocked {
+		word = strings.TrimSpace(word)
+		if word != "" && strings.Contains(lower, strings.ToLower(word)) {
+			return word, true
+		}
+	}
+	return "", false
+}
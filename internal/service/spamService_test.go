@@ -0,0 +1,93 @@
+package service
+
+import (
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestMatchBlockedIP(t *testing.T) {
+	blocked := []string{"10.0.0.0/8", "192.168.1.10", "2001:db8::/32", "not-an-ip", "300.0.0.0/8"}
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"11.0.0.1", false},
+		{"192.168.1.10", true},
+		{"192.168.1.11", false},
+		{"2001:db8::1", true},
+		{"2001:db9::1", false},
+		{"", false},
+		{"invalid", false},
+	}
+	for _, tt := range tests {
+		if got := matchBlockedIP(tt.ip, blocked); got != tt.want {
+			t.Errorf("matchBlockedIP(%q) = %t, want %t", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestMatchBlockedEmail(t *testing.T) {
+	blocked := []string{"@Spam.example", "bad@example.com", " "}
+	tests := []struct {
+		email string
+		want  bool
+	}{
+		{"anyone@spam.example", true},
+		{"BAD@example.com", true},
+		{"good@example.com", false},
+		{"user@notspam.example.org", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := matchBlockedEmail(tt.email, blocked); got != tt.want {
+			t.Errorf("matchBlockedEmail(%q) = %t, want %t", tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestMatchBlockedWord(t *testing.T) {
+	word, ok := matchBlockedWord("Buy CHEAP pills", []string{"", "casino", "cheap"})
+	if !ok || word != "cheap" {
+		t.Errorf("matchBlockedWord = %q, %t, want cheap, true", word, ok)
+	}
+	if _, ok := matchBlockedWord("正常评论", []string{"casino"}); ok {
+		t.Error("不含屏蔽词时不应命中")
+	}
+}
+
+func TestJudgeSpam(t *testing.T) {
+	spamConf := &config.SpamConfig{
+		MaxLinks:          2,
+		BlockedIPs:        []string{"10.0.0.0/8"},
+		ModerateThreshold: 0.5,
+		RejectThreshold:   0.9,
+	}
+	links := "https://a.com https://b.com https://c.com"
+	tests := []struct {
+		name    string
+		check   DTO.SpamCheckDTO
+		signals spamSignals
+		status  string
+		reasons []string
+	}{
+		{"正常评论", DTO.SpamCheckDTO{IP: "1.2.3.4", Content: "写得不错"}, spamSignals{}, "", []string{}},
+		{"链接过多", DTO.SpamCheckDTO{Content: links}, spamSignals{}, model.CommentStatusPending, []string{"too_many_links"}},
+		{"重复评论", DTO.SpamCheckDTO{Content: "顶"}, spamSignals{duplicates: 1}, model.CommentStatusSpam, []string{"duplicate"}},
+		{"屏蔽网段", DTO.SpamCheckDTO{IP: "10.9.8.7", Content: "x"}, spamSignals{}, model.CommentStatusSpam, []string{"blocked_ip"}},
+		{"分类器可疑", DTO.SpamCheckDTO{Content: "x"}, spamSignals{bayesScore: 0.7, bayesOK: true}, model.CommentStatusPending, []string{"bayes"}},
+		{"分类器正常", DTO.SpamCheckDTO{Content: "x"}, spamSignals{bayesScore: 0.3, bayesOK: true}, "", []string{}},
+		// 样本不足时分类器得分不参与判定
+		{"分类器样本不足", DTO.SpamCheckDTO{Content: "x"}, spamSignals{bayesScore: 0.99}, "", []string{}},
+		{"多条规则", DTO.SpamCheckDTO{Content: links}, spamSignals{duplicates: 2}, model.CommentStatusSpam, []string{"too_many_links", "duplicate"}},
+	}
+	for _, tt := range tests {
+		verdict := judgeSpam(&tt.check, spamConf, tt.signals)
+		if verdict.Status != tt.status || !reflect.DeepEqual(verdict.Reasons, tt.reasons) {
+			t.Errorf("%s: status = %q, reasons = %v, want %q, %v", tt.name, verdict.Status, verdict.Reasons, tt.status, tt.reasons)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/model"
+	"go-my-blog/internal/repo"
+	"go-my-blog/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// trashPurgeBatch 后台清理时每批永久删除的记录数
+const trashPurgeBatch = 100
+
+type TrashService struct {
+	trashRepo *repo.TrashRepository
+	userRepo  *repo.UserRepository
+}
+
+func NewTrashService(trashRepo *repo.TrashRepository, userRepo *repo.UserRepository) *TrashService {
+	return &TrashService{
+		trashRepo: trashRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// ListPosts 回收站中的文章：作者只能看到自己的，管理员可以看到全部
+func (ts *TrashService) ListPosts(ctx context.Context, d *DTO.ListTrashDTO) (*DTO.TrashPostListDTO, error) {
+	ownerID, err := ts.ownerFilter(ctx, d.UserID)
+	if err != nil {
+		return nil, err
+	}
+	posts, total, err := ts.trashRepo.ListPosts(ctx, ownerID, d.PageNum, d.PageSize)
+	if err != nil {
+		logger.Error("回收站文章查询失败", zap.Error(err))
+		return nil, err
+	}
+	ids := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+	counts, err := ts.trashRepo.CountCommentsDeletedWith(ctx, ids)
+	if err != nil {
+		logger.Error("回收站评论数查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	postDTOs := make([]DTO.TrashPostDTO, 0, len(posts))
+	for _, post := range posts {
+		postDTOs = append(postDTOs, DTO.TrashPostDTO{
+			ID:        post.ID,
+			Title:     post.Title,
+			UserID:    post.UserID,
+			Status:    post.Status,
+			Comments:  counts[post.ID],
+			DeletedAt: post.DeletedAt.Time.Format("2006-01-02 15:04:05"),
+			PurgeAt:   purgeTimeOf(post.DeletedAt.Time),
+		})
+	}
+	return &DTO.TrashPostListDTO{Posts: postDTOs, Total: total, PageNum: d.PageNum, PageSize: d.PageSize}, nil
+}
+
+// ListComments 回收站中单独删除的评论：评论作者只能看到自己的，管理员可以看到全部
+func (ts *TrashService) ListComments(ctx context.Context, d *DTO.ListTrashDTO) (*DTO.TrashCommentListDTO, error) {
+	ownerID, err := ts.ownerFilter(ctx, d.UserID)
+	if err != nil {
+		return nil, err
+	}
+	comments, total, err := ts.trashRepo.ListComments(ctx, ownerID, d.PageNum, d.PageSize)
+	if err != nil {
+		logger.Error("回收站评论查询失败", zap.Error(err))
+		return nil, err
+	}
+
+	commentDTOs := make([]DTO.TrashCommentDTO, 0, len(comments))
+	for _, comment := range comments {
+		commentDTO := DTO.TrashCommentDTO{
+			ID:        comment.ID,
+			PostID:    comment.PostID,
+			PostTitle: comment.Post.Title,
+			Content:   comment.Content,
+			Status:    comment.Status,
+			DeletedAt: comment.DeletedAt.Time.Format("2006-01-02 15:04:05"),
+			PurgeAt:   purgeTimeOf(comment.DeletedAt.Time),
+		}
+		if comment.UserID != nil {
+			commentDTO.UserID = *comment.UserID
+		}
+		commentDTOs = append(commentDTOs, commentDTO)
+	}
+	return &DTO.TrashCommentListDTO{Comments: commentDTOs, Total: total, PageNum: d.PageNum, PageSize: d.PageSize}, nil
+}
+
+// RestorePost 恢复文章，并恢复 DeletePost 随文章一起删除的评论
+func (ts *TrashService) RestorePost(ctx context.Context, id uint, userID uint) (*DTO.RestorePostResultDTO, error) {
+	post, err := ts.trashRepo.GetPost(ctx, id)
+	if err != nil {
+		logger.Error("回收站文章查询失败", zap.Error(err))
+		return nil, err
+	}
+	if err := ts.checkOwner(ctx, post.UserID, userID); err != nil {
+		return nil, err
+	}
+	restored, err := ts.trashRepo.RestorePost(ctx, post)
+	if err != nil {
+		logger.Error("文章恢复失败", zap.Error(err))
+		return nil, err
+	}
+	logger.Info("文章已从回收站恢复", zap.Uint("post_id", id), zap.Uint("user_id", userID), zap.Int64("comments", restored))
+	return &DTO.RestorePostResultDTO{ID: post.ID, Comments: restored}, nil
+}
+
+// RestoreComment 恢复单独删除的评论；所属文章在回收站中时需先恢复文章
+func (ts *TrashService) RestoreComment(ctx context.Context, id uint, userID uint) error {
+	comment, err := ts.trashRepo.GetComment(ctx, id)
+	if err != nil {
+		logger.Error("回收站评论查询失败", zap.Error(err))
+		return err
+	}
+	if err := ts.checkCommentOwner(ctx, comment, userID); err != nil {
+		return err
+	}
+	if comment.Post.DeletedAt.Valid {
+		return ErrTrashPostDeleted
+	}
+	if err := ts.trashRepo.RestoreComment(ctx, comment); err != nil {
+		logger.Error("评论恢复失败", zap.Error(err))
+		return err
+	}
+	logger.Info("评论已从回收站恢复", zap.Uint("comment_id", id), zap.Uint("user_id", userID))
+	return nil
+}
+
+// PurgePost 永久删除回收站中的文章（连同其全部评论），不可恢复
+func (ts *TrashService) PurgePost(ctx context.Context, id uint, userID uint) error {
+	post, err := ts.trashRepo.GetPost(ctx, id)
+	if err != nil {
+		logger.Error("回收站文章查询失败", zap.Error(err))
+		return err
+	}
+	if err := ts.checkOwner(ctx, post.UserID, userID); err != nil {
+		return err
+	}
+	if err := ts.trashRepo.PurgePost(ctx, post.ID); err != nil {
+		logger.Error("文章永久删除失败", zap.Error(err))
+		return err
+	}
+	logger.Info("文章已永久删除", zap.Uint("post_id", id), zap.Uint("user_id", userID))
+	return nil
+}
+
+// PurgeComment 永久删除回收站中的评论，不可恢复
+func (ts *TrashService) PurgeComment(ctx context.Context, id uint, userID uint) error {
+	comment, err := ts.trashRepo.GetComment(ctx, id)
+	if err != nil {
+		logger.Error("回收站评论查询失败", zap.Error(err))
+		return err
+	}
+	if err := ts.checkCommentOwner(ctx, comment, userID); err != nil {
+		return err
+	}
+	if err := ts.trashRepo.PurgeComment(ctx, comment.ID); err != nil {
+		logger.Error("评论永久删除失败", zap.Error(err))
+		return err
+	}
+	logger.Info("评论已永久删除", zap.Uint("comment_id", id), zap.Uint("user_id", userID))
+	return nil
+}
+
+// StartPurgeWorker 启动后台任务：定期永久删除超过保留期的回收站内容，ctx 取消时退出
+func (ts *TrashService) StartPurgeWorker(ctx context.Context) {
+	interval := time.Duration(config.Conf.Trash.PurgeIntervalM) * time.Minute
+	go func() {
+		for {
+			if _, err := ts.PurgeExpired(ctx); err != nil {
+				logger.Error("回收站清理任务执行失败", zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+// PurgeExpired 永久删除超过保留期的文章和评论，返回删除的记录数；先删文章（连同其评论），再删单独删除的评论
+func (ts *TrashService) PurgeExpired(ctx context.Context) (int, error) {
+	before := time.Now().Add(-config.Conf.Trash.GetRetention())
+	purged := 0
+	for {
+		ids, err := ts.trashRepo.ListExpiredPostIDs(ctx, before, trashPurgeBatch)
+		if err != nil {
+			return purged, err
+		}
+		for _, id := range ids {
+			if err := ts.trashRepo.PurgePost(ctx, id); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(ids) < trashPurgeBatch {
+			break
+		}
+	}
+	for {
+		ids, err := ts.trashRepo.ListExpiredCommentIDs(ctx, before, trashPurgeBatch)
+		if err != nil {
+			return purged, err
+		}
+		for _, id := range ids {
+			if err := ts.trashRepo.PurgeComment(ctx, id); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(ids) < trashPurgeBatch {
+			break
+		}
+	}
+	if purged > 0 {
+		logger.Info("回收站过期内容已永久删除", zap.Int("count", purged))
+	}
+	return purged, nil
+}
+
+// ownerFilter 列表的作者过滤条件：管理员返回 0（不过滤）
+func (ts *TrashService) ownerFilter(ctx context.Context, userID uint) (uint, error) {
+	user, err := ts.userRepo.FindById(ctx, userID)
+	if err != nil {
+		logger.Error("用户查询失败", zap.Error(err))
+		return 0, err
+	}
+	if user.IsAdmin() {
+		return 0, nil
+	}
+	return userID, nil
+}
+
+// checkOwner 只有作者本人和管理员可以恢复或永久删除
+func (ts *TrashService) checkOwner(ctx context.Context, ownerID uint, userID uint) error {
+	if ownerID == userID {
+		return nil
+	}
+	user, err := ts.userRepo.FindById(ctx, userID)
+	if err != nil {
+		logger.Error("用户查询失败", zap.Error(err))
+		return err
+	}
+	if !user.IsAdmin() {
+		logger.Error("非作者无权操作回收站内容", zap.Uint("user_id", userID), zap.Uint("owner_id", ownerID))
+		return ErrTrashForbidden
+	}
+	return nil
+}
+
+func (ts *TrashService) checkCommentOwner(ctx context.Context, comment *model.Comment, userID uint) error {
+	if comment.IsAuthoredBy(userID) {
+		return nil
+	}
+	// 游客评论没有作者，只有管理员可以操作
+	return ts.checkOwner(ctx, 0, userID)
+}
+
+// purgeTimeOf 回收站内容的预计永久删除时间
+func purgeTimeOf(deletedAt time.Time) string {
+	return deletedAt.Add(config.Conf.Trash.GetRetention()).Format("2006-01-02 15:04:05")
+}
@@ -1,7 +1,7 @@
 package service
 
 import (
-	"errors"
+	"context"
 	"go-my-blog/internal/DTO"
 	"go-my-blog/internal/model"
 	"go-my-blog/internal/repo"
@@ -36,7 +36,7 @@ func (us *UserSevice) GetUserRepo() *repo.UserRepository {
 // 返回值:
 //   - *DTO.UserRegisterDTO: 注册成功后的用户数据传输对象
 //   - error: 错误信息，如果注册过程中出现错误则返回
-func (us *UserSevice) UserRegister(userDTO *DTO.UserRegisterDTO) (*DTO.UserRegisterDTO, error) {
+func (us *UserSevice) UserRegister(ctx context.Context, userDTO *DTO.UserRegisterDTO) (*DTO.UserRegisterDTO, error) {
 	// 声明一个用户模型变量，用于存储数据库操作的用户数据
 	var user model.User
 	// 使用copier将DTO数据复制到用户模型中，为数据库操作做准备
@@ -49,8 +49,8 @@ func (us *UserSevice) UserRegister(userDTO *DTO.UserRegisterDTO) (*DTO.UserRegis
 	// 复制后检查密码是否为空，确保用户设置了密码
 	if user.Password == "" {
 		// 如果密码为空，记录警告日志并返回错误
-		logger.Warn("UserSevice.UserRegister password is empty", zap.Error(errors.New("密码不能为空")))
-		return nil, errors.New("密码不能为空")
+		logger.Warn("UserSevice.UserRegister password is empty", zap.Error(ErrPasswordRequired))
+		return nil, ErrPasswordRequired
 	}
 
 	// 使用bcrypt对密码进行哈希处理，确保密码安全存储
@@ -65,10 +65,13 @@ func (us *UserSevice) UserRegister(userDTO *DTO.UserRegisterDTO) (*DTO.UserRegis
 	user.Password = afterPassword
 
 	// 调用用户仓库层的注册方法，执行实际的数据库操作
-	userResult, err := us.userRepo.UserRegister(&user)
+	userResult, err := us.userRepo.UserRegister(ctx, &user)
 	// 如果注册失败，记录错误日志并返回错误
 	if err != nil {
 		logger.Error("UserSevice.UserRegister userRepo.UserRegister is error!", zap.Error(err))
+		if repo.IsDuplicateKey(err) {
+			return nil, ErrUsernameTaken.Wrap(err)
+		}
 		return nil, err
 	}
 
@@ -112,14 +115,14 @@ func (us *UserSevice) bcryptPassword(password string) (string, error) {
 	return string(afterPassword), bcrErr
 }
 
-func (us *UserSevice) UserLogin(d *DTO.LoginDTO) (*response.LoginResponse, error) {
-	user, err := us.userRepo.FindByUserName(d.Username)
+func (us *UserSevice) UserLogin(ctx context.Context, d *DTO.LoginDTO) (*response.LoginResponse, error) {
+	user, err := us.userRepo.FindByUserName(ctx, d.Username)
 	if err != nil {
-		return nil, errors.New("用户名或密码错误！")
+		return nil, ErrInvalidCredentials
 	}
 
 	if user == nil {
-		return nil, errors.New("用户名或密码错误！")
+		return nil, ErrInvalidCredentials
 	}
 
 	err = bcrypt.CompareHashAndPassword(
@@ -127,13 +130,14 @@ func (us *UserSevice) UserLogin(d *DTO.LoginDTO) (*response.LoginResponse, error
 		[]byte(d.Password),
 	)
 
+	// 密码错误时不区分账号状态，避免泄露账号是否为导入用户
 	if err != nil {
-		return nil, errors.New("用户名或密码错误！")
+		return nil, ErrInvalidCredentials
 	}
 
 	token, expiresAt, err := jwt.GenerateToken(user.ID, user.Username)
 	if err != nil {
-		return nil, errors.New("生成令牌失败！")
+		return nil, ErrTokenIssueFailed.Wrap(err)
 	}
 
 	return &response.LoginResponse{AccessToken: token, Username: user.Username, ExpiresAt: expiresAt}, nil
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"go-my-blog/bootstrap"
 	"go-my-blog/config"
 	priorityConfig "go-my-blog/config/priority_config"
+	"go-my-blog/internal/command"
 	"go-my-blog/pkg/db"
 	"go-my-blog/pkg/logger"
 	"go-my-blog/router"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -36,10 +41,28 @@ func main() {
 	// 3. 初始化数据库（依赖配置中的 MySQL 参数）
 	logger.Info("开始初始化数据库")
 	db.Init()
+	// 迁移表结构（新增字段/表，如文章状态、标签）
+	bootstrap.AutoMigrate(db.DB)
 
 	// 4. 初始化所有modules
 	container := bootstrap.InitAllModules(db.DB)
 
+	// 带子命令时执行命令后退出（如 export-static），不启动 HTTP 服务；
+	// 收到中断信号时取消命令的上下文，正在执行的查询随之中止
+	if len(os.Args) > 1 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err := command.Run(ctx, container, os.Args[1:])
+		stop()
+		if err != nil {
+			logger.Fatal("命令执行失败", zap.Strings("args", os.Args[1:]), zap.Error(err))
+		}
+		return
+	}
+
+	// 后台定期删除冷静期已过的账号和过期的个人数据归档，以及超过保留期的回收站内容
+	container.AccountService.StartPurgeWorker(context.Background())
+	container.TrashService.StartPurgeWorker(context.Background())
+
 	// 5. 初始化 Gin 引擎和路由
 	logger.Info("开始初始化路由")
 	ginRun := gin.Default()
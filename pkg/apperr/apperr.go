@@ -0,0 +1,148 @@
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind 错误类型
+type Kind int
+
+const (
+	KindInternal     Kind = iota // 服务器内部错误
+	KindBadRequest               // 请求格式错误（无法解析的参数、请求体）
+	KindValidation               // 参数格式正确但不满足业务规则
+	KindUnauthorized             // 未登录或登录已失效
+	KindForbidden                // 已登录但无权操作
+	KindNotFound                 // 资源不存在
+	KindConflict                 // 与当前状态冲突（重复提交、状态不允许）
+	KindTooLarge                 // 请求体超过大小限制
+	KindTimeout                  // 请求处理超时
+	KindCanceled                 // 客户端在响应前断开连接
+)
+
+// StatusClientClosedRequest 客户端在响应前断开连接（沿用 nginx 的 499 状态码）
+const StatusClientClosedRequest = 499
+
+// Status 错误类型对应的 HTTP 状态码
+func (k Kind) Status() int {
+	switch k {
+	case KindBadRequest:
+		return http.StatusBadRequest
+	case KindValidation:
+		return http.StatusUnprocessableEntity
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	case KindCanceled:
+		return StatusClientClosedRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// Error 业务错误。Code 为稳定的机器可读错误码，Message 为面向用户的说明，
+// Details 为可选的补充信息（如字段错误列表），cause 为底层错误，只记录日志不返回给客户端
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Details interface{}
+	cause   error
+}
+
+func New(kind Kind, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func Internal(code string, message string) *Error {
+	return New(KindInternal, code, message)
+}
+
+func BadRequest(code string, message string) *Error {
+	return New(KindBadRequest, code, message)
+}
+
+func Validation(code string, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+func Unauthorized(code string, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func Forbidden(code string, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func NotFound(code string, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code string, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func TooLarge(code string, message string) *Error {
+	return New(KindTooLarge, code, message)
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + "：" + e.cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is 错误码相同即视为同一错误，便于用 errors.Is 与预定义的错误比较
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Status 错误对应的 HTTP 状态码
+func (e *Error) Status() int {
+	return e.Kind.Status()
+}
+
+// Wrap 返回附带底层错误的副本，预定义的错误本身不会被修改
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.cause = err
+	return &c
+}
+
+// WithMessage 返回替换了说明的副本（如需带上具体数值的说明）
+func (e *Error) WithMessage(message string) *Error {
+	c := *e
+	c.Message = message
+	return &c
+}
+
+// WithDetails 返回附带补充信息的副本
+func (e *Error) WithDetails(details interface{}) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// As 取出错误链中的业务错误
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
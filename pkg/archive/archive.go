@@ -0,0 +1,165 @@
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// MaxLineSize 单条记录的最大长度（文章正文可能较长）
+const MaxLineSize = 64 << 20
+
+// Writer 以 tar.gz 写出归档：JSON 文件、JSON Lines 实体文件和原始文件
+type Writer struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	gz := gzip.NewWriter(w)
+	return &Writer{gz: gz, tw: tar.NewWriter(gz)}
+}
+
+// WriteJSON 写入单个 JSON 文件（如 manifest.json）
+func (w *Writer) WriteJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return w.WriteFile(name, data)
+}
+
+// WriteFile 写入原始文件
+func (w *Writer) WriteFile(name string, data []byte) error {
+	if err := w.tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: time.Now()}); err != nil {
+		return err
+	}
+	_, err := w.tw.Write(data)
+	return err
+}
+
+// Lines 开始写一个 JSON Lines 文件；tar 需要预先知道文件大小，记录先写到临时文件，Close 时再整体写入归档
+func (w *Writer) Lines(name string) (*LineWriter, error) {
+	tmp, err := os.CreateTemp("", "archive-*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	return &LineWriter{archive: w, name: name, tmp: tmp, buf: bufio.NewWriter(tmp)}, nil
+}
+
+// Close 结束归档
+func (w *Writer) Close() error {
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+// LineWriter 一个 JSON Lines 文件，每行一条记录
+type LineWriter struct {
+	archive *Writer
+	name    string
+	tmp     *os.File
+	buf     *bufio.Writer
+	Count   int
+}
+
+// Write 写入一条记录
+func (lw *LineWriter) Write(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := lw.buf.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	lw.Count++
+	return nil
+}
+
+// Close 把临时文件写入归档并删除
+func (lw *LineWriter) Close() error {
+	defer os.Remove(lw.tmp.Name())
+	defer lw.tmp.Close()
+	if err := lw.buf.Flush(); err != nil {
+		return err
+	}
+	size, err := lw.tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := lw.tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := lw.archive.tw.WriteHeader(&tar.Header{Name: lw.name, Mode: 0o644, Size: size, ModTime: time.Now()}); err != nil {
+		return err
+	}
+	_, err = io.Copy(lw.archive.tw, lw.tmp)
+	return err
+}
+
+// Reader 顺序读取 tar.gz 归档
+type Reader struct {
+	gz *gzip.Reader
+	tr *tar.Reader
+}
+
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{gz: gz, tr: tar.NewReader(gz)}, nil
+}
+
+// Next 移动到下一个文件，返回文件名；读完时返回 io.EOF
+func (r *Reader) Next() (string, error) {
+	for {
+		header, err := r.tr.Next()
+		if err != nil {
+			return "", err
+		}
+		if header.Typeflag == tar.TypeReg {
+			return header.Name, nil
+		}
+	}
+}
+
+// DecodeJSON 将当前文件解析为 JSON
+func (r *Reader) DecodeJSON(v interface{}) error {
+	return json.NewDecoder(r.tr).Decode(v)
+}
+
+// EachLine 逐行读取当前 JSON Lines 文件，空行跳过
+func (r *Reader) EachLine(fn func(line []byte) error) error {
+	scanner := bufio.NewScanner(r.tr)
+	scanner.Buffer(make([]byte, 64*1024), MaxLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	if errors.Is(scanner.Err(), bufio.ErrTooLong) {
+		return errors.New("单条记录超过长度上限")
+	}
+	return scanner.Err()
+}
+
+// Body 当前文件内容
+func (r *Reader) Body() io.Reader {
+	return r.tr
+}
+
+// Close 关闭读取器（不关闭底层 io.Reader）
+func (r *Reader) Close() error {
+	return r.gz.Close()
+}
@@ -0,0 +1,9 @@
+// Package cjk 中日韩文字判断：这些文字之间没有空格分词，字数统计、摘要截断和分词都需要单独处理
+package cjk
+
+import "unicode"
+
+// Is 是否为中日韩文字（汉字、平假名、片假名、谚文）
+func Is(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,16 @@
+package cjk
+
+import "testing"
+
+func TestIs(t *testing.T) {
+	for _, r := range "汉字ひらがなカタカナ한글" {
+		if !Is(r) {
+			t.Errorf("Is(%q) = false, want true", r)
+		}
+	}
+	for _, r := range "Latin 123，。!Кириллица" {
+		if Is(r) {
+			t.Errorf("Is(%q) = true, want false", r)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	updated := published.Add(2 * time.Hour)
+	return &Feed{
+		Title:       "My Blog",
+		Link:        "https://blog.example.com/",
+		FeedURL:     "https://blog.example.com/feed.xml",
+		Description: "A blog",
+		Language:    "zh-CN",
+		Updated:     updated,
+		Items: []Item{
+			{
+				ID:          "https://blog.example.com/posts/2",
+				Title:       "Full <post> & more",
+				Link:        "https://blog.example.com/posts/2",
+				Summary:     "summary two",
+				ContentHTML: "<p>Hello <b>world</b> ]]> end</p>",
+				AuthorName:  "alice",
+				Published:   published,
+				Updated:     updated,
+				Tags:        []string{"go", "web"},
+			},
+			{
+				ID:         "https://blog.example.com/posts/1",
+				Title:      "Excerpt only",
+				Link:       "https://blog.example.com/posts/1",
+				Summary:    "summary one",
+				AuthorName: "",
+				Published:  published.Add(-24 * time.Hour),
+				Updated:    published.Add(-24 * time.Hour),
+			},
+		},
+	}
+}
+
+func TestRSS(t *testing.T) {
+	body, err := RSS(testFeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		XMLName xml.Name `xml:"rss"`
+		Version string   `xml:"version,attr"`
+		Channel struct {
+			Title       string `xml:"title"`
+			Description string `xml:"description"`
+			// 无命名空间的 link 与 atom:link 同名，需按命名空间区分
+			Links []struct {
+				XMLName xml.Name
+				Href    string `xml:"href,attr"`
+				Rel     string `xml:"rel,attr"`
+				Value   string `xml:",chardata"`
+			} `xml:"link"`
+			Items []struct {
+				Title      string   `xml:"title"`
+				Link       string   `xml:"link"`
+				GUID       string   `xml:"guid"`
+				PubDate    string   `xml:"pubDate"`
+				Categories []string `xml:"category"`
+				Content    string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("RSS 不是合法的 XML: %v\n%s", err, body)
+	}
+
+	ch := doc.Channel
+	var link, self string
+	for _, l := range ch.Links {
+		switch l.XMLName.Space {
+		case "":
+			link = l.Value
+		case "http://www.w3.org/2005/Atom":
+			if l.Rel == "self" {
+				self = l.Href
+			}
+		}
+	}
+	if doc.Version != "2.0" || ch.Title == "" || link == "" || ch.Description == "" {
+		t.Errorf("channel 缺少必需元素: %+v", ch)
+	}
+	if self != "https://blog.example.com/feed.xml" {
+		t.Errorf("atom:link 自引用 = %q", self)
+	}
+	if len(ch.Items) != 2 {
+		t.Fatalf("item 数量 = %d, want 2", len(ch.Items))
+	}
+	for _, item := range ch.Items {
+		if item.Title == "" || item.Link == "" || item.GUID == "" {
+			t.Errorf("item 缺少必需元素: %+v", item)
+		}
+		if _, err := time.Parse(time.RFC1123Z, item.PubDate); err != nil {
+			t.Errorf("pubDate 不是 RFC 822 格式: %q", item.PubDate)
+		}
+	}
+	first := ch.Items[0]
+	if first.Title != "Full <post> & more" {
+		t.Errorf("标题转义后未还原: %q", first.Title)
+	}
+	if first.Content != "<p>Hello <b>world</b> ]]> end</p>" {
+		t.Errorf("content:encoded = %q", first.Content)
+	}
+	if strings.Join(first.Categories, ",") != "go,web" {
+		t.Errorf("category = %v", first.Categories)
+	}
+	if ch.Items[1].Content != "" {
+		t.Error("摘要模式不应输出 content:encoded")
+	}
+}
+
+func TestAtom(t *testing.T) {
+	body, err := Atom(testFeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	type link struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	}
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Title   string   `xml:"title"`
+		Updated string   `xml:"updated"`
+		Links   []link   `xml:"link"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Title   string `xml:"title"`
+			Updated string `xml:"updated"`
+			Links   []link `xml:"link"`
+			Author  struct {
+				Name string `xml:"name"`
+			} `xml:"author"`
+			Content *struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Atom 不是合法的 XML: %v\n%s", err, body)
+	}
+
+	if doc.ID == "" || doc.Title == "" {
+		t.Errorf("feed 缺少 id 或 title")
+	}
+	if _, err := time.Parse(time.RFC3339, doc.Updated); err != nil {
+		t.Errorf("updated 不是 RFC 3339 格式: %q", doc.Updated)
+	}
+	hasSelf := false
+	for _, l := range doc.Links {
+		hasSelf = hasSelf || (l.Rel == "self" && l.Href == "https://blog.example.com/feed.xml")
+	}
+	if !hasSelf {
+		t.Error("缺少 rel=self 链接")
+	}
+	if len(doc.Entries) != 2 {
+		t.Fatalf("entry 数量 = %d, want 2", len(doc.Entries))
+	}
+	for _, entry := range doc.Entries {
+		if entry.ID == "" || entry.Title == "" || entry.Updated == "" || len(entry.Links) == 0 {
+			t.Errorf("entry 缺少必需元素: %+v", entry)
+		}
+		// 没有 feed 级作者时每个 entry 必须有作者
+		if entry.Author.Name == "" {
+			t.Errorf("entry %s 缺少作者", entry.ID)
+		}
+	}
+	if c := doc.Entries[0].Content; c == nil || c.Type != "html" || c.Value != "<p>Hello <b>world</b> ]]> end</p>" {
+		t.Errorf("content = %+v", c)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	body, err := JSON(testFeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("JSON Feed 不是合法的 JSON: %v", err)
+	}
+	if doc["version"] != "https://jsonfeed.org/version/1.1" || doc["title"] != "My Blog" {
+		t.Errorf("version/title = %v/%v", doc["version"], doc["title"])
+	}
+	items, _ := doc["items"].([]interface{})
+	if len(items) != 2 {
+		t.Fatalf("items 数量 = %d, want 2", len(items))
+	}
+	for _, raw := range items {
+		item := raw.(map[string]interface{})
+		if item["id"] == "" || item["id"] == nil {
+			t.Error("item 缺少 id")
+		}
+		// 每个 item 必须有 content_html 或 content_text
+		if item["content_html"] == nil && item["content_text"] == nil {
+			t.Errorf("item %v 缺少 content_html/content_text", item["id"])
+		}
+	}
+	if items[1].(map[string]interface{})["content_text"] != "summary one" {
+		t.Error("摘要模式应以摘要作为 content_text")
+	}
+}
+
+func TestJSONEmptyItems(t *testing.T) {
+	body, err := JSON(&Feed{Title: "Empty"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `"items": []`) {
+		t.Errorf("无文章时 items 应为空数组: %s", body)
+	}
+}
+
+func TestETag(t *testing.T) {
+	f := testFeed()
+	etag := f.ETag("rss:full")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Errorf("ETag 应为弱校验值: %s", etag)
+	}
+	if f.ETag("rss:full") != etag {
+		t.Error("内容不变时 ETag 应保持不变")
+	}
+	if f.ETag("atom:full") == etag {
+		t.Error("不同格式的 ETag 应不同")
+	}
+	f.Items[0].Updated = f.Items[0].Updated.Add(time.Second)
+	if f.ETag("rss:full") == etag {
+		t.Error("文章更新后 ETag 应变化")
+	}
+}
@@ -0,0 +1,158 @@
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Document 拆分后的 Markdown 文件：Meta 为前置元数据，Body 为正文
+type Document struct {
+	Meta map[string]interface{}
+	Body string
+}
+
+// Parse 拆分前置元数据：--- 包裹的为 YAML（Jekyll/Hugo），+++ 包裹的为 TOML（Hugo）；没有元数据时 Meta 为空
+func Parse(data []byte) (*Document, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	doc := &Document{Meta: map[string]interface{}{}}
+
+	var delimiter string
+	switch {
+	case strings.HasPrefix(text, "---\n"):
+		delimiter = "---"
+	case strings.HasPrefix(text, "+++\n"):
+		delimiter = "+++"
+	default:
+		doc.Body = text
+		return doc, nil
+	}
+
+	rest := text[len(delimiter)+1:]
+	var head string
+	if strings.HasPrefix(rest, delimiter+"\n") || rest == delimiter {
+		// 空的元数据块
+		head, rest = "", strings.TrimPrefix(strings.TrimPrefix(rest, delimiter), "\n")
+	} else {
+		end := strings.Index(rest, "\n"+delimiter+"\n")
+		if end < 0 {
+			if !strings.HasSuffix(rest, "\n"+delimiter) {
+				return nil, errors.New("前置元数据没有结束标记 " + delimiter)
+			}
+			end = len(rest) - len(delimiter) - 1
+		}
+		head = rest[:end]
+		rest = rest[min(end+len(delimiter)+2, len(rest)):]
+	}
+
+	var err error
+	if delimiter == "---" {
+		err = yaml.Unmarshal([]byte(head), &doc.Meta)
+	} else {
+		err = toml.Unmarshal([]byte(head), &doc.Meta)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("前置元数据解析失败：%w", err)
+	}
+	if doc.Meta == nil {
+		doc.Meta = map[string]interface{}{}
+	}
+	doc.Body = strings.TrimLeft(rest, "\n")
+	return doc, nil
+}
+
+// String 读取字符串字段，不存在或类型不符时返回空串
+func (d *Document) String(key string) string {
+	switch v := d.Meta[key].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case fmt.Stringer:
+		return v.String()
+	case int, int64, float64:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// Bool 读取布尔字段，兼容 "true"/"false" 字符串
+func (d *Document) Bool(key string) bool {
+	switch v := d.Meta[key].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(strings.TrimSpace(v), "true")
+	}
+	return false
+}
+
+// Strings 读取字符串列表，兼容单个字符串和逗号分隔的写法
+func (d *Document) Strings(key string) []string {
+	var result []string
+	switch v := d.Meta[key].(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s := strings.TrimSpace(fmt.Sprint(item)); s != "" {
+				result = append(result, s)
+			}
+		}
+	case []string:
+		result = append(result, v...)
+	case string:
+		for _, item := range strings.Split(v, ",") {
+			if s := strings.TrimSpace(item); s != "" {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+// dateLayouts 常见的日期写法；不带时区的按本地时间解析
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Time 读取时间字段，YAML/TOML 原生时间和常见字符串格式均可；不存在时返回 nil
+func (d *Document) Time(key string) (*time.Time, error) {
+	switch v := d.Meta[key].(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		return &v, nil
+	case toml.LocalDate:
+		t := v.AsTime(time.Local)
+		return &t, nil
+	case toml.LocalDateTime:
+		t := v.AsTime(time.Local)
+		return &t, nil
+	case string:
+		return ParseTime(v)
+	}
+	return nil, fmt.Errorf("%s 不是有效的时间", key)
+}
+
+// ParseTime 按常见格式解析时间字符串，空串返回 nil
+func ParseTime(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("无法识别的时间格式：%s", value)
+}
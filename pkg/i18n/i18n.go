@@ -0,0 +1,142 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 支持的语言
+const (
+	ZhCN = "zh-CN"
+	EnUS = "en-US"
+)
+
+// DefaultLocale 请求的语言没有对应文案时回退到的语言
+const DefaultLocale = ZhCN
+
+// Supported 支持的语言，按优先级排列
+var Supported = []string{ZhCN, EnUS}
+
+//go:embed locales/*.json
+var embedded embed.FS
+
+// catalogs 各语言的文案表：键为错误码或提示信息码，值为可包含 {name} 占位符的模板
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	result := make(map[string]map[string]string, len(Supported))
+	for _, locale := range Supported {
+		data, err := embedded.ReadFile(path.Join("locales", locale+".json"))
+		if err != nil {
+			panic("i18n: 缺少文案文件 " + locale + ".json")
+		}
+		catalog := make(map[string]string)
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic("i18n: 文案文件格式错误 " + locale + ".json：" + err.Error())
+		}
+		result[locale] = catalog
+	}
+	return result
+}
+
+// Match 把语言标签（如 en、en-GB、zh_Hans_CN）匹配到支持的语言：先完整匹配，再按主语言匹配
+func Match(tag string) (string, bool) {
+	tag = strings.TrimSpace(strings.ReplaceAll(tag, "_", "-"))
+	if tag == "" {
+		return "", false
+	}
+	for _, locale := range Supported {
+		if strings.EqualFold(tag, locale) {
+			return locale, true
+		}
+	}
+	primary := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+	for _, locale := range Supported {
+		if strings.ToLower(strings.SplitN(locale, "-", 2)[0]) == primary {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate 按 Accept-Language 的权重选择支持的语言，都不支持时返回 fallback
+func Negotiate(acceptLanguage string, fallback string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	for _, c := range candidates {
+		if locale, ok := Match(c.tag); ok {
+			return locale
+		}
+	}
+	return fallback
+}
+
+// Text 翻译 key 对应的文案，依次查找：请求的语言 → DefaultLocale → fallback → key 本身。
+// args 用于替换模板中的 {name} 占位符
+func Text(locale string, key string, args map[string]interface{}, fallback string) string {
+	template, ok := lookup(locale, key)
+	if !ok {
+		template, ok = lookup(DefaultLocale, key)
+	}
+	if !ok {
+		if fallback != "" {
+			return fallback
+		}
+		return key
+	}
+	return format(template, args)
+}
+
+// T 翻译 key 对应的文案，没有文案时返回 key
+func T(locale string, key string) string {
+	return Text(locale, key, nil, "")
+}
+
+// Has 语言的文案表中是否有 key（不含回退）
+func Has(locale string, key string) bool {
+	_, ok := lookup(locale, key)
+	return ok
+}
+
+func lookup(locale string, key string) (string, bool) {
+	template, ok := catalogs[locale][key]
+	return template, ok
+}
+
+func format(template string, args map[string]interface{}) string {
+	if len(args) == 0 || !strings.Contains(template, "{") {
+		return template
+	}
+	pairs := make([]string, 0, len(args)*2)
+	for name, value := range args {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
@@ -0,0 +1,86 @@
+package i18n
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+		ok   bool
+	}{
+		{"zh-CN", ZhCN, true},
+		{"EN-us", EnUS, true},
+		{"en", EnUS, true},
+		{"en-GB", EnUS, true},
+		{"zh_Hans_CN", ZhCN, true},
+		{"zh-TW", ZhCN, true},
+		{" en-US ", EnUS, true},
+		{"fr-FR", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Match(tt.tag)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Match(%q) = %q, %t, want %q, %t", tt.tag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ZhCN},
+		{"en-US", EnUS},
+		{"zh-CN,zh;q=0.9,en;q=0.8", ZhCN},
+		// 按权重而不是出现顺序选择
+		{"zh-CN;q=0.5, en-US;q=0.8", EnUS},
+		{"fr-FR, en;q=0.7, zh;q=0.3", EnUS},
+		// q=0 表示不接受
+		{"en;q=0, zh-CN;q=0.1", ZhCN},
+		{"en;q=0", ZhCN},
+		// 权重相同时保留原顺序
+		{"en-GB;q=0.8, zh-CN;q=0.8", EnUS},
+		// 通配符和无法解析的权重
+		{"*", ZhCN},
+		{"fr;q=0.9, en;q=abc", EnUS},
+		{"fr-FR, de", ZhCN},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header, ZhCN); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	if got := Text(EnUS, "validation.required", map[string]interface{}{"field": "title"}, ""); got != "title is required" {
+		t.Errorf("Text = %q", got)
+	}
+	// 不支持的语言回退到默认语言，没有文案时依次使用 fallback 和 key
+	if got := Text("fr-FR", "validation.required", map[string]interface{}{"field": "title"}, ""); got != Text(DefaultLocale, "validation.required", map[string]interface{}{"field": "title"}, "") {
+		t.Errorf("不支持的语言应回退到默认语言: %q", got)
+	}
+	if got := Text(EnUS, "no_such_key", nil, "备用说明"); got != "备用说明" {
+		t.Errorf("Text = %q, want fallback", got)
+	}
+	if got := T(EnUS, "no_such_key"); got != "no_such_key" {
+		t.Errorf("T = %q, want key", got)
+	}
+}
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for _, locale := range Supported {
+		for key := range catalogs[DefaultLocale] {
+			if !Has(locale, key) {
+				t.Errorf("%s 缺少文案 %s", locale, key)
+			}
+		}
+		for key := range catalogs[locale] {
+			if !Has(DefaultLocale, key) {
+				t.Errorf("%s 中的文案 %s 在 %s 中不存在", locale, key, DefaultLocale)
+			}
+		}
+	}
+}
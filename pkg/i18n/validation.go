@@ -0,0 +1,29 @@
+package i18n
+
+import (
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError 翻译 validator 的字段校验错误，文案键为 validation.<tag>；
+// min/max/len 对字符串和列表分别使用 validation.<tag>_len、validation.<tag>_items，
+// 没有对应文案的规则使用 validation.default
+func FieldError(locale string, fe validator.FieldError, field string) string {
+	args := map[string]interface{}{"field": field, "param": fe.Param()}
+	key := "validation." + fe.Tag()
+	switch fe.Tag() {
+	case "min", "max", "len":
+		switch fe.Kind() {
+		case reflect.String:
+			key += "_len"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			key += "_items"
+		}
+	}
+	if !Has(locale, key) && !Has(DefaultLocale, key) {
+		key = "validation.default"
+		args["tag"] = fe.Tag()
+	}
+	return Text(locale, key, args, "")
+}
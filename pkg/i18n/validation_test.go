@@ -0,0 +1,67 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type fieldErrorSample struct {
+	Title string            `validate:"min=3"`
+	Tags  []string          `validate:"max=1"`
+	Meta  map[string]string `validate:"len=1"`
+	Count int               `validate:"min=10"`
+	Code  string            `validate:"uuid"`
+}
+
+// sampleFieldErrors 用真实的 validator 生成字段错误，字段名 -> 错误
+func sampleFieldErrors(t *testing.T) map[string]validator.FieldError {
+	t.Helper()
+	err := validator.New().Struct(fieldErrorSample{Title: "a", Tags: []string{"a", "b"}, Meta: map[string]string{}, Count: 1, Code: "x"})
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Fatalf("err = %v, want ValidationErrors", err)
+	}
+	result := make(map[string]validator.FieldError, len(validationErrors))
+	for _, fe := range validationErrors {
+		result[fe.Field()] = fe
+	}
+	return result
+}
+
+func TestFieldErrorKeys(t *testing.T) {
+	fields := sampleFieldErrors(t)
+	tests := []struct {
+		field string
+		name  string // 响应中的字段名
+		want  string
+	}{
+		// 字符串按长度
+		{"Title", "title", "title must be at least 3 characters long"},
+		// 列表和映射按元素个数
+		{"Tags", "tags", "tags must contain at most 1 items"},
+		{"Meta", "meta", "meta must contain exactly 1 items"},
+		// 数字按数值
+		{"Count", "count", "count must be at least 10"},
+		// 没有文案的规则使用 validation.default
+		{"Code", "code", "code failed the uuid check"},
+	}
+	for _, tt := range tests {
+		fe, ok := fields[tt.field]
+		if !ok {
+			t.Errorf("缺少字段 %s 的错误", tt.field)
+			continue
+		}
+		if got := FieldError(EnUS, fe, tt.name); got != tt.want {
+			t.Errorf("FieldError(%s) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestFieldErrorFallsBackToDefaultLocale(t *testing.T) {
+	fe := sampleFieldErrors(t)["Title"]
+	if got, want := FieldError("fr-FR", fe, "title"), FieldError(DefaultLocale, fe, "title"); got != want {
+		t.Errorf("FieldError(fr-FR) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,130 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+)
+
+// 支持的图片类型
+const (
+	MimeJPEG = "image/jpeg"
+	MimePNG  = "image/png"
+	MimeGIF  = "image/gif"
+)
+
+// Extensions 图片类型对应的文件扩展名
+var Extensions = map[string]string{
+	MimeJPEG: ".jpg",
+	MimePNG:  ".png",
+	MimeGIF:  ".gif",
+}
+
+// Variant 处理后的一份图片（原图或缩略图）
+type Variant struct {
+	Data     []byte
+	MimeType string
+	Width    int
+	Height   int
+}
+
+// Result 图片处理结果
+type Result struct {
+	Original   Variant
+	Thumbnails []Variant
+}
+
+// Sniff 根据文件头判断真实 MIME 类型，不信任客户端声明的 Content-Type
+func Sniff(data []byte) string {
+	return http.DetectContentType(data)
+}
+
+// Process 校验并处理图片：
+//   - 先只解码图片头检查像素数，防止解压炸弹
+//   - JPEG/PNG 按 EXIF 方向摆正后重新编码，丢弃 EXIF（含 GPS 等隐私信息）及其他元数据块
+//   - GIF 原样保留以免丢失动画（GIF 不含 EXIF）
+//   - 按给定宽度生成等比缩略图，原图不够宽的尺寸跳过；GIF 缩略图取首帧编码为 PNG
+func Process(data []byte, mimeType string, maxPixels int, thumbnailWidths []int, quality int) (*Result, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("无法识别的图片：" + err.Error())
+	}
+	if maxPixels > 0 && cfg.Width*cfg.Height > maxPixels {
+		return nil, errors.New("图片像素过大")
+	}
+
+	var img image.Image
+	switch mimeType {
+	case MimeJPEG:
+		img, err = jpeg.Decode(bytes.NewReader(data))
+		if err == nil {
+			img = applyOrientation(img, jpegOrientation(data))
+		}
+	case MimePNG:
+		img, err = png.Decode(bytes.NewReader(data))
+	case MimeGIF:
+		img, err = gif.Decode(bytes.NewReader(data))
+	default:
+		return nil, errors.New("不支持的图片类型：" + mimeType)
+	}
+	if err != nil {
+		return nil, errors.New("图片解码失败：" + err.Error())
+	}
+
+	bounds := img.Bounds()
+	result := &Result{}
+	if mimeType == MimeGIF {
+		result.Original = Variant{Data: data, MimeType: mimeType, Width: bounds.Dx(), Height: bounds.Dy()}
+	} else {
+		original, err := encode(img, mimeType, quality)
+		if err != nil {
+			return nil, err
+		}
+		result.Original = Variant{Data: original, MimeType: mimeType, Width: bounds.Dx(), Height: bounds.Dy()}
+	}
+
+	thumbnailMime := mimeType
+	if thumbnailMime == MimeGIF {
+		thumbnailMime = MimePNG
+	}
+	for _, width := range thumbnailWidths {
+		if width <= 0 || width >= bounds.Dx() {
+			continue
+		}
+		height := bounds.Dy() * width / bounds.Dx()
+		if height < 1 {
+			height = 1
+		}
+		thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, bounds, draw.Src, nil)
+		encoded, err := encode(thumbnail, thumbnailMime, quality)
+		if err != nil {
+			return nil, err
+		}
+		result.Thumbnails = append(result.Thumbnails, Variant{Data: encoded, MimeType: thumbnailMime, Width: width, Height: height})
+	}
+	return result, nil
+}
+
+func encode(img image.Image, mimeType string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch mimeType {
+	case MimeJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	case MimePNG:
+		err = png.Encode(&buf, img)
+	default:
+		return nil, errors.New("不支持的编码类型：" + mimeType)
+	}
+	if err != nil {
+		return nil, errors.New("图片编码失败：" + err.Error())
+	}
+	return buf.Bytes(), nil
+}
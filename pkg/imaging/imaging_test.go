@@ -0,0 +1,163 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testImage 宽 40、高 20 的图片，左半红色、右半蓝色，用于判断旋转方向
+func testImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 20 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withExif 在 JPEG 的 SOI 之后插入 APP1（EXIF）段：IFD0 只含方向标签，后面附带模拟的 GPS 隐私信息
+func withExif(data []byte, orientation uint16, extra string) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("II")
+	binary.Write(&tiff, binary.LittleEndian, uint16(42))
+	binary.Write(&tiff, binary.LittleEndian, uint32(8))
+	binary.Write(&tiff, binary.LittleEndian, uint16(1))      // 条目数
+	binary.Write(&tiff, binary.LittleEndian, uint16(0x0112)) // 方向标签
+	binary.Write(&tiff, binary.LittleEndian, uint16(3))      // SHORT
+	binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	binary.Write(&tiff, binary.LittleEndian, orientation)
+	binary.Write(&tiff, binary.LittleEndian, uint16(0))
+	binary.Write(&tiff, binary.LittleEndian, uint32(0)) // 没有下一个 IFD
+	tiff.WriteString(extra)
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	return append(out, data[2:]...)
+}
+
+func TestSniff(t *testing.T) {
+	var pngBuf, gifBuf bytes.Buffer
+	png.Encode(&pngBuf, testImage())
+	gif.Encode(&gifBuf, testImage(), nil)
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"jpeg", encodeJPEG(t, testImage()), MimeJPEG},
+		{"png", pngBuf.Bytes(), MimePNG},
+		{"gif", gifBuf.Bytes(), MimeGIF},
+		{"伪装成图片的 HTML", []byte("<html><script>alert(1)</script></html>"), "text/html; charset=utf-8"},
+		{"纯文本", []byte("not an image"), "text/plain; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		if got := Sniff(tt.data); got != tt.want {
+			t.Errorf("%s: Sniff = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProcessStripsExifAndAppliesOrientation(t *testing.T) {
+	const secret = "GPS-LATITUDE-SECRET"
+	// 方向 6：拍摄时顺时针旋转了 90 度，摆正后宽高互换
+	data := withExif(encodeJPEG(t, testImage()), 6, secret)
+	if jpegOrientation(data) != 6 {
+		t.Fatal("测试图片的 EXIF 方向未被识别")
+	}
+
+	result, err := Process(data, MimeJPEG, 0, nil, 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := result.Original
+	if bytes.Contains(out.Data, []byte("Exif")) || bytes.Contains(out.Data, []byte(secret)) {
+		t.Error("处理后的图片仍包含 EXIF 数据")
+	}
+	if out.Width != 20 || out.Height != 40 {
+		t.Errorf("尺寸 = %dx%d, want 20x40", out.Width, out.Height)
+	}
+
+	// 顺时针旋转后，原来左侧的红色在上方
+	img, err := jpeg.Decode(bytes.NewReader(out.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, b, _ := img.At(10, 5).RGBA(); r < b {
+		t.Error("图片未按 EXIF 方向旋转：上方应为红色")
+	}
+}
+
+func TestProcessThumbnails(t *testing.T) {
+	var pngBuf bytes.Buffer
+	png.Encode(&pngBuf, testImage())
+
+	result, err := Process(pngBuf.Bytes(), MimePNG, 0, []int{10, 20, 40, 80}, 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 不小于原图宽度的尺寸跳过
+	if len(result.Thumbnails) != 2 {
+		t.Fatalf("缩略图数量 = %d, want 2", len(result.Thumbnails))
+	}
+	for i, want := range [][2]int{{10, 5}, {20, 10}} {
+		thumb := result.Thumbnails[i]
+		if thumb.Width != want[0] || thumb.Height != want[1] || thumb.MimeType != MimePNG {
+			t.Errorf("缩略图 %d = %dx%d %s, want %dx%d image/png", i, thumb.Width, thumb.Height, thumb.MimeType, want[0], want[1])
+		}
+	}
+}
+
+func TestProcessGIFKeepsOriginal(t *testing.T) {
+	var gifBuf bytes.Buffer
+	gif.Encode(&gifBuf, testImage(), nil)
+
+	result, err := Process(gifBuf.Bytes(), MimeGIF, 0, []int{10}, 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(result.Original.Data, gifBuf.Bytes()) {
+		t.Error("GIF 原图应原样保留")
+	}
+	if len(result.Thumbnails) != 1 || result.Thumbnails[0].MimeType != MimePNG {
+		t.Error("GIF 缩略图应编码为 PNG")
+	}
+}
+
+func TestProcessRejects(t *testing.T) {
+	data := encodeJPEG(t, testImage())
+	if _, err := Process(data, MimeJPEG, 100, nil, 90); err == nil {
+		t.Error("超过像素上限时应返回错误")
+	}
+	if _, err := Process([]byte("not an image"), MimeJPEG, 0, nil, 90); err == nil {
+		t.Error("无法识别的图片应返回错误")
+	}
+	if _, err := Process(data, "image/webp", 0, nil, 90); err == nil {
+		t.Error("不支持的类型应返回错误")
+	}
+}
@@ -0,0 +1,297 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema JSON Schema（OpenAPI 3.1 使用 2020-12 草案），只包含本项目用到的关键字
+type Schema struct {
+	Ref                  string        `json:"$ref,omitempty"`
+	Type                 string        `json:"type,omitempty"`
+	Format               string        `json:"format,omitempty"`
+	Description          string        `json:"description,omitempty"`
+	Properties           Properties    `json:"properties,omitempty"`
+	Required             []string      `json:"required,omitempty"`
+	Items                *Schema       `json:"items,omitempty"`
+	AdditionalProperties *Schema       `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema     `json:"allOf,omitempty"`
+	Enum                 []interface{} `json:"enum,omitempty"`
+	Pattern              string        `json:"pattern,omitempty"`
+	MinLength            *int          `json:"minLength,omitempty"`
+	MaxLength            *int          `json:"maxLength,omitempty"`
+	Minimum              *float64      `json:"minimum,omitempty"`
+	Maximum              *float64      `json:"maximum,omitempty"`
+	MinItems             *int          `json:"minItems,omitempty"`
+	MaxItems             *int          `json:"maxItems,omitempty"`
+}
+
+// Property 对象的一个属性
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties 保持结构体字段顺序的属性列表，序列化为 JSON 对象
+type Properties []Property
+
+func (p Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, prop := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(prop.Name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(prop.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(schema)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Ref 引用 components.schemas 中的 Schema
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// Object 由给定属性组成的对象 Schema
+func Object(props ...Property) *Schema {
+	return &Schema{Type: "object", Properties: props}
+}
+
+// RuleFunc 把自定义 validate 规则转换为 Schema 约束
+type RuleFunc func(s *Schema, param string)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Generator 通过反射根据结构体的 json/form/validate 标签生成 Schema，
+// 具名结构体登记到 components.schemas 并以 $ref 引用
+type Generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+	rules   map[string]RuleFunc
+}
+
+// NewGenerator 生成的具名 Schema 写入 schemas（通常为 Document.Components.Schemas）
+func NewGenerator(schemas map[string]*Schema) *Generator {
+	return &Generator{
+		schemas: schemas,
+		names:   map[reflect.Type]string{},
+		rules:   map[string]RuleFunc{},
+	}
+}
+
+// Rule 登记自定义校验规则对应的 Schema 约束，如 username、password
+func (g *Generator) Rule(tag string, fn RuleFunc) {
+	g.rules[tag] = fn
+}
+
+// Schema 返回 v 的类型对应的 Schema
+func (g *Generator) Schema(v interface{}) *Schema {
+	return g.schemaOf(reflect.TypeOf(v))
+}
+
+// QueryParameters 把结构体的 form 字段转换为查询参数
+func (g *Generator) QueryParameters(v interface{}) []*Parameter {
+	t := indirect(reflect.TypeOf(v))
+	var params []*Parameter
+	for _, f := range fields(t, "form") {
+		schema := g.schemaOf(f.field.Type)
+		required := g.applyRules(schema, f.field.Tag.Get("validate"))
+		params = append(params, &Parameter{Name: f.name, In: "query", Required: required, Schema: schema})
+	}
+	return params
+}
+
+func (g *Generator) schemaOf(t reflect.Type) *Schema {
+	t = indirect(t)
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"} // encoding/json 把 []byte 编码为 base64 字符串
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return Ref(g.register(t))
+	default:
+		return &Schema{} // interface{} 等任意值
+	}
+}
+
+// register 登记具名结构体，返回组件名；先占位再生成属性，以支持自引用的结构体（如评论回复树）
+func (g *Generator) register(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+	g.names[t] = name
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.structSchema(t)
+	return name
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: Properties{}}
+	for _, f := range fields(t, "json") {
+		prop := g.schemaOf(f.field.Type)
+		if g.applyRules(prop, f.field.Tag.Get("validate")) {
+			s.Required = append(s.Required, f.name)
+		}
+		s.Properties = append(s.Properties, Property{Name: f.name, Schema: prop})
+	}
+	return s
+}
+
+// applyRules 把 validate 标签转换为 Schema 约束，dive 之后的规则作用于数组元素；返回字段是否必填
+func (g *Generator) applyRules(s *Schema, tag string) bool {
+	if tag == "" || tag == "-" {
+		return false
+	}
+	required := false
+	target := s
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			if target.Items == nil {
+				return required
+			}
+			target = target.Items
+		case "required":
+			if target == s {
+				required = true
+			}
+		case "min", "gte":
+			setBound(target, param, true)
+		case "max", "lte":
+			setBound(target, param, false)
+		case "len":
+			setBound(target, param, true)
+			setBound(target, param, false)
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				if target.Type == "integer" {
+					if n, err := strconv.Atoi(v); err == nil {
+						target.Enum = append(target.Enum, n)
+						continue
+					}
+				}
+				target.Enum = append(target.Enum, v)
+			}
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "datetime":
+			if param == "2006-01-02" {
+				target.Format = "date"
+			}
+		default:
+			if fn, ok := g.rules[name]; ok {
+				fn(target, param)
+			}
+		}
+	}
+	return required
+}
+
+// setBound 按类型把 min/max 转换为长度、数值或元素个数的上下界
+func setBound(s *Schema, param string, lower bool) {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+	switch s.Type {
+	case "string":
+		if lower {
+			s.MinLength = &n
+		} else {
+			s.MaxLength = &n
+		}
+	case "array":
+		if lower {
+			s.MinItems = &n
+		} else {
+			s.MaxItems = &n
+		}
+	case "integer", "number":
+		f := float64(n)
+		if lower {
+			s.Minimum = &f
+		} else {
+			s.Maximum = &f
+		}
+	}
+}
+
+type structField struct {
+	name  string
+	field reflect.StructField
+}
+
+// fields 按 encoding/json 的规则列出结构体的可导出字段：标签为 - 时跳过，没有标签时使用字段名，匿名嵌入的结构体展开
+func fields(t reflect.Type, tagKey string) []structField {
+	var list []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get(tagKey), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && indirect(f.Type).Kind() == reflect.Struct {
+			list = append(list, fields(indirect(f.Type), tagKey)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		list = append(list, structField{name: name, field: f})
+	}
+	return list
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
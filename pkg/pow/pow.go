@@ -0,0 +1,134 @@
+package pow
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Challenge 工作量证明题目：客户端需找到 solution，使 sha256(Token + ":" + solution) 的前 Difficulty 位为 0
+type Challenge struct {
+	Token      string `json:"token"`      // 签名后的题目，提交答案时原样带回
+	Difficulty int    `json:"difficulty"` // 要求的前导零比特数
+	ExpiresAt  int64  `json:"expiresAt"`  // 过期时间戳（秒）
+}
+
+// Issue 签发题目：题目内容（随机数、难度、过期时间）用 HMAC 签名，服务端无需保存任何状态
+func Issue(secret []byte, difficulty int, ttl time.Duration) (*Challenge, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(ttl).Unix()
+	payload := fmt.Sprintf("%s.%d.%d", hex.EncodeToString(nonce), difficulty, expiresAt)
+	token := payload + "." + sign(secret, payload)
+	return &Challenge{Token: token, Difficulty: difficulty, ExpiresAt: expiresAt}, nil
+}
+
+// ErrReplayed 题目已被使用过：每道题只能换取一次提交
+var ErrReplayed = errors.New("题目已使用，请重新获取")
+
+// Verify 校验答案：签名正确、未过期且满足难度要求。
+// 校验本身不保存状态，同一组题目和答案在过期前可以重复通过，需要防重放时使用 Guard.Verify
+func Verify(secret []byte, token string, solution string) error {
+	_, err := verify(secret, token, solution)
+	return err
+}
+
+// verify 校验答案，返回题目的过期时间戳
+func verify(secret []byte, token string, solution string) (int64, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return 0, errors.New("题目格式错误")
+	}
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(sign(secret, payload)), []byte(parts[3])) {
+		return 0, errors.New("题目签名无效")
+	}
+
+	difficulty, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, errors.New("题目格式错误")
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, errors.New("题目格式错误")
+	}
+	if time.Now().Unix() > expiresAt {
+		return 0, errors.New("题目已过期，请重新获取")
+	}
+
+	if solution == "" || leadingZeroBits(sha256.Sum256([]byte(token+":"+solution))) < difficulty {
+		return 0, errors.New("工作量证明校验失败")
+	}
+	return expiresAt, nil
+}
+
+// 清理过期记录的最小间隔（秒）
+const purgeInterval = 60
+
+// Guard 记录已通过校验的题目，过期前再次提交同一道题会被拒绝；
+// 题目过期后本身就无法通过校验，记录随之清理，占用的内存与有效期内的提交数成正比
+// 记录只保存在当前进程内：多实例部署时同一道题可以在每个实例各通过一次，进程重启后记录也会丢失
+type Guard struct {
+	mu        sync.Mutex
+	used      map[[sha256.Size]byte]int64 // 题目摘要 → 过期时间戳
+	lastPurge int64
+}
+
+func NewGuard() *Guard {
+	return &Guard{used: map[[sha256.Size]byte]int64{}}
+}
+
+// Verify 校验答案并把题目标记为已使用，同一道题只能通过一次
+func (g *Guard) Verify(secret []byte, token string, solution string) error {
+	expiresAt, err := verify(secret, token, solution)
+	if err != nil {
+		return err
+	}
+
+	key := sha256.Sum256([]byte(token))
+	now := time.Now().Unix()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.used[key]; ok {
+		return ErrReplayed
+	}
+	if now-g.lastPurge >= purgeInterval {
+		for k, exp := range g.used {
+			if now > exp {
+				delete(g.used, k)
+			}
+		}
+		g.lastPurge = now
+	}
+	g.used[key] = expiresAt
+	return nil
+}
+
+func sign(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func leadingZeroBits(sum [sha256.Size]byte) int {
+	count := 0
+	for _, b := range sum {
+		if b == 0 {
+			count += 8
+			continue
+		}
+		return count + bits.LeadingZeros8(b)
+	}
+	return count
+}
@@ -0,0 +1,100 @@
+package pow
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("test-secret")
+
+// solve 暴力求解题目，测试使用较低难度
+func solve(t *testing.T, c *Challenge) string {
+	t.Helper()
+	for i := 0; i < 1<<20; i++ {
+		solution := strconv.Itoa(i)
+		if leadingZeroBits(sha256.Sum256([]byte(c.Token+":"+solution))) >= c.Difficulty {
+			return solution
+		}
+	}
+	t.Fatal("未找到答案")
+	return ""
+}
+
+func TestVerify(t *testing.T) {
+	c, err := Issue(testSecret, 8, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution := solve(t, c)
+
+	if err := Verify(testSecret, c.Token, solution); err != nil {
+		t.Fatalf("正确答案校验失败: %v", err)
+	}
+	if err := Verify([]byte("other-secret"), c.Token, solution); err == nil {
+		t.Error("签名密钥不同时应校验失败")
+	}
+	if err := Verify(testSecret, c.Token+"x", solution); err == nil {
+		t.Error("篡改题目后应校验失败")
+	}
+	if err := Verify(testSecret, c.Token, ""); err == nil {
+		t.Error("空答案应校验失败")
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	c, err := Issue(testSecret, 0, -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(testSecret, c.Token, "0"); err == nil {
+		t.Error("过期题目应校验失败")
+	}
+}
+
+func TestGuardRejectsReplay(t *testing.T) {
+	c, err := Issue(testSecret, 8, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution := solve(t, c)
+	guard := NewGuard()
+
+	if err := guard.Verify(testSecret, c.Token, solution); err != nil {
+		t.Fatalf("首次提交应通过: %v", err)
+	}
+	if err := guard.Verify(testSecret, c.Token, solution); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("重复提交同一答案应返回 ErrReplayed，实际为 %v", err)
+	}
+
+	// 其他题目不受影响
+	other, err := Issue(testSecret, 8, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := guard.Verify(testSecret, other.Token, solve(t, other)); err != nil {
+		t.Fatalf("新题目应通过: %v", err)
+	}
+}
+
+func TestGuardDoesNotConsumeFailedAttempts(t *testing.T) {
+	c, err := Issue(testSecret, 8, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guard := NewGuard()
+	solution := solve(t, c)
+	wrong := solution + "x"
+	for leadingZeroBits(sha256.Sum256([]byte(c.Token+":"+wrong))) >= c.Difficulty {
+		wrong += "x"
+	}
+
+	if err := guard.Verify(testSecret, c.Token, wrong); err == nil || errors.Is(err, ErrReplayed) {
+		t.Fatalf("错误答案应校验失败，实际为 %v", err)
+	}
+	if err := guard.Verify(testSecret, c.Token, solution); err != nil {
+		t.Fatalf("错误答案不应占用题目: %v", err)
+	}
+}
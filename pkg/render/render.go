@@ -0,0 +1,151 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkHTML "github.com/yuin/goldmark/renderer/html"
+)
+
+// 内容格式
+const (
+	FormatMarkdown = "markdown"
+	FormatPlain    = "plain"
+	FormatHTML     = "html"
+)
+
+// Version 渲染器版本：调整 Markdown 扩展、白名单策略或摘要/目录生成规则后递增，
+// 库中 render_version 落后的内容会在读取时重新渲染
+const Version = 3
+
+// HeadingIDPrefix 锚点前缀：与页面中主题使用的 id（如 comment-1）区分开，
+// 内容中不带前缀的 id 会被清洗掉，不能覆盖或伪造页面元素
+const HeadingIDPrefix = "h-"
+
+// markdown 渲染器：GFM 扩展（表格、删除线、任务列表、自动链接），标题自动生成 id 供目录锚点使用；
+// 允许原始 HTML 透传，统一交给白名单策略清洗
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	goldmark.WithRendererOptions(goldmarkHTML.WithUnsafe()),
+)
+
+// policy HTML 白名单：在 UGC 策略基础上保留代码块的语言 class（供前端语法高亮）和带前缀的标题 id（目录锚点）
+var policy = newPolicy()
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^`+HeadingIDPrefix+`[\p{L}\p{N}_-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// idAttrPattern 清洗后 HTML 中的 id 属性：bluemonday 输出的属性值统一使用双引号且已转义，正文中的文本不会误匹配
+var idAttrPattern = regexp.MustCompile(` id="[^"]*"`)
+
+// sanitize 按白名单清洗，并去掉不带 HeadingIDPrefix 的 id：UGC 策略全局允许 id，且无法在其基础上收紧
+func sanitize(content string) string {
+	return idAttrPattern.ReplaceAllStringFunc(policy.Sanitize(content), func(attr string) string {
+		if strings.HasPrefix(attr, ` id="`+HeadingIDPrefix) {
+			return attr
+		}
+		return ""
+	})
+}
+
+// IsValidFormat 是否为支持的内容格式
+func IsValidFormat(format string) bool {
+	return format == FormatMarkdown || format == FormatPlain || format == FormatHTML
+}
+
+// Render 将内容按格式渲染为清洗后的安全 HTML
+func Render(content string, format string) (string, error) {
+	switch format {
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+		if err := markdown.Convert([]byte(content), &buf, parser.WithContext(ctx)); err != nil {
+			return "", err
+		}
+		return sanitize(buf.String()), nil
+	case FormatHTML:
+		return sanitize(content), nil
+	case FormatPlain:
+		return renderPlain(content), nil
+	default:
+		return "", errors.New("不支持的内容格式：" + format)
+	}
+}
+
+// headingIDs 标题锚点生成器：保留中日韩等 Unicode 字母数字（goldmark 默认实现会丢弃非 ASCII 字符），
+// 空白和标点折叠为 -，加上 HeadingIDPrefix 前缀，重复的锚点追加序号
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: make(map[string]bool)}
+}
+
+// Slugify 将标题文本转为锚点
+func Slugify(text string) string {
+	var buf strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' {
+			buf.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && buf.Len() > 0 {
+			buf.WriteRune('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(buf.String(), "-")
+	if slug == "" {
+		slug = "section"
+	}
+	return slug
+}
+
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	slug := HeadingIDPrefix + Slugify(string(value))
+	id := slug
+	for n := 1; h.used[id]; n++ {
+		id = slug + "-" + strconv.Itoa(n)
+	}
+	h.used[id] = true
+	return []byte(id)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
+
+// renderPlain 纯文本：转义后按空行分段，段内换行转为 <br>
+func renderPlain(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	var buf strings.Builder
+	for _, paragraph := range strings.Split(content, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		buf.WriteString("<p>")
+		buf.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		buf.WriteString("</p>\n")
+	}
+	return buf.String()
+}
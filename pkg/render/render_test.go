@@ -0,0 +1,90 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSanitizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  string
+		banned  []string // 清洗后不能出现的片段
+	}{
+		{"script 标签", "<script>alert(1)</script>正文", FormatHTML, []string{"<script", "alert(1)"}},
+		{"Markdown 中的 script", "正文\n\n<script>alert(1)</script>", FormatMarkdown, []string{"<script", "alert(1)"}},
+		{"javascript: 链接", `<a href="javascript:alert(1)">x</a>`, FormatHTML, []string{"javascript:"}},
+		{"Markdown javascript: 链接", "[x](javascript:alert(1))", FormatMarkdown, []string{"javascript:"}},
+		{"大小写混写的 javascript: 链接", `<a href="JaVaScRiPt:alert(1)">x</a>`, FormatHTML, []string{"alert(1)"}},
+		{"data: 链接", `<a href="data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==">x</a>`, FormatHTML, []string{"data:"}},
+		{"data: 图片", `<img src="data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=">`, FormatHTML, []string{"data:"}},
+		{"事件处理属性", `<img src="https://example.com/a.png" onerror="alert(1)"><p onclick="alert(1)">x</p>`, FormatHTML, []string{"onerror", "onclick"}},
+		{"style 属性", `<p style="position:fixed;top:0">x</p>`, FormatHTML, []string{"style", "position:fixed"}},
+		{"style 标签", "<style>body{display:none}</style>x", FormatHTML, []string{"<style", "display:none"}},
+		{"iframe", `<iframe src="https://evil.example.com"></iframe>`, FormatHTML, []string{"<iframe"}},
+		{"非标题元素的 id", `<p id="comments">x</p>`, FormatHTML, []string{`id="comments"`}},
+		{"标题中不带前缀的 id", `<h2 id="comment-1">x</h2>`, FormatHTML, []string{`id="comment-1"`}},
+		{"代码块以外的 class", `<p class="admin-only">x</p>`, FormatHTML, []string{"admin-only"}},
+		{"纯文本中的标签", "<script>alert(1)</script>", FormatPlain, []string{"<script"}},
+	}
+	for _, tt := range tests {
+		got, err := Render(tt.content, tt.format)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		for _, banned := range tt.banned {
+			if strings.Contains(got, banned) {
+				t.Errorf("%s: 输出中包含 %q: %s", tt.name, banned, got)
+			}
+		}
+	}
+}
+
+func TestRenderKeeps(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  string
+		want    []string // 清洗后必须保留的片段
+	}{
+		{"https 链接", "[x](https://example.com/a)", FormatMarkdown, []string{`href="https://example.com/a"`, `target="_blank"`}},
+		{"站内链接", "[x](/posts/1)", FormatMarkdown, []string{`href="/posts/1"`}},
+		{"代码块语言", "```go\nfmt.Println()\n```", FormatMarkdown, []string{`class="language-go"`}},
+		{"带前缀的标题 id", `<h2 id="h-intro">x</h2>`, FormatHTML, []string{`id="h-intro"`}},
+		{"表格", "| a | b |\n|---|---|\n| 1 | 2 |", FormatMarkdown, []string{"<table>", "<td>1</td>"}},
+		{"纯文本换行", "第一行\n第二行\n\n第二段", FormatPlain, []string{"<p>第一行<br>第二行</p>", "<p>第二段</p>"}},
+	}
+	for _, tt := range tests {
+		got, err := Render(tt.content, tt.format)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: 输出中缺少 %q: %s", tt.name, want, got)
+			}
+		}
+	}
+}
+
+func TestRenderHeadingIDs(t *testing.T) {
+	got, err := Render("# 简介\n\n## Hello, World!\n\n## Hello, World!\n\n## ？？", FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 保留中文，标点折叠为 -，重复的锚点追加序号，只有标点的标题使用 section
+	for _, want := range []string{`id="h-简介"`, `id="h-hello-world"`, `id="h-hello-world-1"`, `id="h-section"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("输出中缺少 %s: %s", want, got)
+		}
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	if _, err := Render("x", "rst"); err == nil {
+		t.Error("不支持的格式应返回错误")
+	}
+}
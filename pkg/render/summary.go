@@ -0,0 +1,119 @@
+package render
+
+import (
+	"go-my-blog/pkg/cjk"
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// MoreMarker 手动摘要分隔标记：标记之前的内容作为摘要
+const MoreMarker = "<!--more-->"
+
+// TOCItem 目录条目，Children 为下一级标题
+type TOCItem struct {
+	Level    int       `json:"level"`
+	Text     string    `json:"text"`
+	Anchor   string    `json:"anchor"`
+	Children []TOCItem `json:"children,omitempty"`
+}
+
+// textPolicy 去掉全部标签，只保留文本
+var textPolicy = bluemonday.StrictPolicy()
+
+// headingPattern 匹配清洗后 HTML 中带锚点的标题（bluemonday 输出的属性统一使用双引号）
+var headingPattern = regexp.MustCompile(`(?s)<h([1-6]) id="([^"]+)">(.*?)</h[1-6]>`)
+
+// SplitMore 按 <!--more--> 切分内容，返回标记之前的部分；没有标记时 ok 为 false
+func SplitMore(content string) (string, bool) {
+	idx := strings.Index(content, MoreMarker)
+	if idx < 0 {
+		return "", false
+	}
+	return strings.TrimSpace(content[:idx]), true
+}
+
+// PlainText 将渲染后的 HTML 转为纯文本，连续空白折叠为一个空格
+func PlainText(contentHTML string) string {
+	text := html.UnescapeString(textPolicy.Sanitize(contentHTML))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// Truncate 按字符（而非字节）截断文本，不会截断中文；
+// 截断点落在英文单词中间时回退到最近的空格，超出长度时追加省略号
+func Truncate(text string, maxRunes int) string {
+	runes := []rune(text)
+	if maxRunes <= 0 || len(runes) <= maxRunes {
+		return text
+	}
+	cut := maxRunes
+	if !cjk.Is(runes[cut-1]) && !unicode.IsSpace(runes[cut]) {
+		// 最多回退 20 个字符，避免超长单词把摘要截得过短
+		for i := cut - 1; i > 0 && i >= maxRunes-20; i-- {
+			if unicode.IsSpace(runes[i]) {
+				cut = i
+				break
+			}
+		}
+	}
+	return strings.TrimSpace(string(runes[:cut])) + "…"
+}
+
+// CountWords 统计字数：中日韩字符每个字算一个，其余按连续的字母数字算一个单词
+func CountWords(text string) (cjkChars int, words int) {
+	inWord := false
+	for _, r := range text {
+		switch {
+		case cjk.Is(r):
+			cjkChars++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+	return cjkChars, words
+}
+
+// ReadingMinutes 估算阅读时长（分钟，向上取整），有内容时至少 1 分钟
+func ReadingMinutes(cjk int, words int, cjkPerMinute int, wordsPerMinute int) int {
+	if cjk+words == 0 {
+		return 0
+	}
+	minutes := float64(cjk)/float64(cjkPerMinute) + float64(words)/float64(wordsPerMinute)
+	return int(math.Max(1, math.Ceil(minutes)))
+}
+
+// TOC 从渲染后的 HTML 中提取带锚点的标题，按层级组织为目录树
+func TOC(contentHTML string) []TOCItem {
+	var headings []TOCItem
+	for _, match := range headingPattern.FindAllStringSubmatch(contentHTML, -1) {
+		headings = append(headings, TOCItem{
+			Level:  int(match[1][0] - '0'),
+			Anchor: match[2],
+			Text:   PlainText(match[3]),
+		})
+	}
+	pos := 0
+	return nestTOC(headings, &pos, 0)
+}
+
+// nestTOC 依次消费比 parentLevel 更深的标题，作为当前层级的条目；标题跳级（如 h2 后直接 h4）时也挂在最近的上级下
+func nestTOC(headings []TOCItem, pos *int, parentLevel int) []TOCItem {
+	var items []TOCItem
+	for *pos < len(headings) && headings[*pos].Level > parentLevel {
+		item := headings[*pos]
+		*pos++
+		item.Children = nestTOC(headings, pos, item.Level)
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,109 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitMore(t *testing.T) {
+	summary, ok := SplitMore("摘要第一段\n\n" + MoreMarker + "\n\n正文")
+	if !ok || summary != "摘要第一段" {
+		t.Errorf("SplitMore = %q, %t", summary, ok)
+	}
+	if summary, ok := SplitMore("没有分隔标记的正文"); ok || summary != "" {
+		t.Errorf("没有标记时 SplitMore = %q, %t", summary, ok)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxRunes int
+		want     string
+	}{
+		{"你好世界", 2, "你好…"},
+		{"你好世界", 4, "你好世界"},
+		{"你好世界", 0, "你好世界"},
+		// 截断点在单词中间时回退到空格
+		{"hello world foo", 8, "hello…"},
+		// 截断点正好在空格前，不需要回退
+		{"中文abc def", 5, "中文abc…"},
+		// 截断点前是中文时直接截断
+		{"博客系统 blog", 2, "博客…"},
+		// 超长单词最多回退 20 个字符，找不到空格时直接截断
+		{strings.Repeat("a", 30), 25, strings.Repeat("a", 25) + "…"},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.text, tt.maxRunes); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.text, tt.maxRunes, got, tt.want)
+		}
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		text  string
+		cjk   int
+		words int
+	}{
+		{"你好，世界", 4, 0},
+		{"Hello, world! 123", 0, 3},
+		{"用Go写博客 v2", 4, 2},
+		{"こんにちは カタカナ", 9, 0},
+		{"안녕 world", 2, 1},
+		{"", 0, 0},
+	}
+	for _, tt := range tests {
+		cjk, words := CountWords(tt.text)
+		if cjk != tt.cjk || words != tt.words {
+			t.Errorf("CountWords(%q) = %d, %d, want %d, %d", tt.text, cjk, words, tt.cjk, tt.words)
+		}
+	}
+}
+
+func TestReadingMinutes(t *testing.T) {
+	tests := []struct {
+		cjk, words int
+		want       int
+	}{
+		{0, 0, 0},
+		{1, 0, 1},   // 有内容时至少 1 分钟
+		{401, 0, 2}, // 向上取整
+		{800, 200, 3},
+	}
+	for _, tt := range tests {
+		if got := ReadingMinutes(tt.cjk, tt.words, 400, 200); got != tt.want {
+			t.Errorf("ReadingMinutes(%d, %d) = %d, want %d", tt.cjk, tt.words, got, tt.want)
+		}
+	}
+}
+
+func TestTOC(t *testing.T) {
+	contentHTML := `<h1>没有锚点的标题</h1>` +
+		`<h2 id="h-a">A</h2><h3 id="h-b">B <code>x</code></h3>` +
+		`<h2 id="h-c">C</h2><h4 id="h-d">D</h4>`
+	want := []TOCItem{
+		{Level: 2, Text: "A", Anchor: "h-a", Children: []TOCItem{{Level: 3, Text: "B x", Anchor: "h-b"}}},
+		// 跳级的 h4 挂在最近的 h2 下
+		{Level: 2, Text: "C", Anchor: "h-c", Children: []TOCItem{{Level: 4, Text: "D", Anchor: "h-d"}}},
+	}
+	if got := TOC(contentHTML); !reflect.DeepEqual(got, want) {
+		t.Errorf("TOC = %+v, want %+v", got, want)
+	}
+}
+
+func TestTOCMixedScript(t *testing.T) {
+	contentHTML, err := Render("## 第一节 Intro\n\n正文\n\n### 小结：Summary", FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TOCItem{
+		{Level: 2, Text: "第一节 Intro", Anchor: "h-第一节-intro", Children: []TOCItem{
+			{Level: 3, Text: "小结：Summary", Anchor: "h-小结-summary"},
+		}},
+	}
+	if got := TOC(contentHTML); !reflect.DeepEqual(got, want) {
+		t.Errorf("TOC = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,164 @@
+package seo
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Image 分享图
+type Image struct {
+	URL    string
+	Width  int
+	Height int
+	Alt    string
+}
+
+// Article 生成文章元数据所需的信息，URL 均为绝对地址
+type Article struct {
+	Title        string
+	Description  string
+	URL          string // 规范地址
+	Image        *Image
+	AuthorName   string
+	PublishedAt  time.Time
+	ModifiedAt   time.Time
+	Tags         []string
+	WordCount    int
+	NoIndex      bool
+	SiteName     string
+	Locale       string // 如 zh-CN，og:locale 使用 zh_CN 形式
+	TwitterSite  string // 站点 Twitter 账号，如 @myblog
+	PublisherURL string // 站点首页地址
+}
+
+// MetaTag 一个 <meta> 标签：Attr 为 property（Open Graph）或 name（Twitter 等）
+type MetaTag struct {
+	Attr    string `json:"attr"`
+	Key     string `json:"key"`
+	Content string `json:"content"`
+}
+
+// Meta 可直接输出到页面 <head> 的元数据
+type Meta struct {
+	Title        string                 `json:"title"`
+	Description  string                 `json:"description"`
+	CanonicalURL string                 `json:"canonicalUrl"`
+	Robots       string                 `json:"robots"`
+	Tags         []MetaTag              `json:"tags"`
+	JSONLD       map[string]interface{} `json:"jsonLd"` // schema.org BlogPosting，序列化后放入 <script type="application/ld+json">
+}
+
+// BuildArticle 生成文章页的 Open Graph、Twitter 卡片和 JSON-LD
+func BuildArticle(a Article) Meta {
+	meta := Meta{
+		Title:        a.Title,
+		Description:  a.Description,
+		CanonicalURL: a.URL,
+		Robots:       "index,follow",
+	}
+	if a.NoIndex {
+		meta.Robots = "noindex,nofollow"
+	}
+
+	property := func(key string, content string) {
+		if content != "" {
+			meta.Tags = append(meta.Tags, MetaTag{Attr: "property", Key: key, Content: content})
+		}
+	}
+	name := func(key string, content string) {
+		if content != "" {
+			meta.Tags = append(meta.Tags, MetaTag{Attr: "name", Key: key, Content: content})
+		}
+	}
+
+	// Open Graph
+	property("og:type", "article")
+	property("og:title", a.Title)
+	property("og:description", a.Description)
+	property("og:url", a.URL)
+	property("og:site_name", a.SiteName)
+	property("og:locale", strings.ReplaceAll(a.Locale, "-", "_"))
+	if a.Image != nil {
+		property("og:image", a.Image.URL)
+		if a.Image.Width > 0 && a.Image.Height > 0 {
+			property("og:image:width", strconv.Itoa(a.Image.Width))
+			property("og:image:height", strconv.Itoa(a.Image.Height))
+		}
+		property("og:image:alt", a.Image.Alt)
+	}
+	property("article:published_time", formatTime(a.PublishedAt))
+	property("article:modified_time", formatTime(a.ModifiedAt))
+	property("article:author", a.AuthorName)
+	for _, tag := range a.Tags {
+		property("article:tag", tag)
+	}
+
+	// Twitter 卡片：有图时使用大图卡片
+	card := "summary"
+	if a.Image != nil {
+		card = "summary_large_image"
+	}
+	name("twitter:card", card)
+	name("twitter:site", a.TwitterSite)
+	name("twitter:title", a.Title)
+	name("twitter:description", a.Description)
+	if a.Image != nil {
+		name("twitter:image", a.Image.URL)
+		name("twitter:image:alt", a.Image.Alt)
+	}
+
+	// JSON-LD
+	ld := map[string]interface{}{
+		"@context":         "https://schema.org",
+		"@type":            "BlogPosting",
+		"headline":         a.Title,
+		"mainEntityOfPage": map[string]interface{}{"@type": "WebPage", "@id": a.URL},
+		"url":              a.URL,
+	}
+	if a.Description != "" {
+		ld["description"] = a.Description
+	}
+	if !a.PublishedAt.IsZero() {
+		ld["datePublished"] = formatTime(a.PublishedAt)
+	}
+	if !a.ModifiedAt.IsZero() {
+		ld["dateModified"] = formatTime(a.ModifiedAt)
+	}
+	if a.AuthorName != "" {
+		ld["author"] = map[string]interface{}{"@type": "Person", "name": a.AuthorName}
+	}
+	if a.SiteName != "" {
+		publisher := map[string]interface{}{"@type": "Organization", "name": a.SiteName}
+		if a.PublisherURL != "" {
+			publisher["url"] = a.PublisherURL
+		}
+		ld["publisher"] = publisher
+	}
+	if a.Image != nil {
+		image := map[string]interface{}{"@type": "ImageObject", "url": a.Image.URL}
+		if a.Image.Width > 0 && a.Image.Height > 0 {
+			image["width"] = a.Image.Width
+			image["height"] = a.Image.Height
+		}
+		ld["image"] = image
+	}
+	if len(a.Tags) > 0 {
+		ld["keywords"] = strings.Join(a.Tags, ",")
+	}
+	if a.WordCount > 0 {
+		ld["wordCount"] = a.WordCount
+	}
+	if a.Locale != "" {
+		ld["inLanguage"] = a.Locale
+	}
+	meta.JSONLD = ld
+	return meta
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
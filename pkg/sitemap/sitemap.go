@@ -0,0 +1,77 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs 协议规定单个 sitemap 文件最多 50000 个 URL，超出需拆分并使用 sitemap 索引
+const MaxURLs = 50000
+
+// ContentType sitemap 的 Content-Type
+const ContentType = "application/xml; charset=utf-8"
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL 一个页面
+type URL struct {
+	Loc     string
+	LastMod time.Time // 零值时不输出 lastmod
+}
+
+// Sitemap 索引中的一个子 sitemap
+type Sitemap struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name  `xml:"urlset"`
+	NS      string    `xml:"xmlns,attr"`
+	URLs    []xmlItem `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name  `xml:"sitemapindex"`
+	NS       string    `xml:"xmlns,attr"`
+	Sitemaps []xmlItem `xml:"sitemap"`
+}
+
+type xmlItem struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// URLSet 生成 <urlset>
+func URLSet(urls []URL) ([]byte, error) {
+	doc := urlSet{NS: namespace, URLs: make([]xmlItem, 0, len(urls))}
+	for _, u := range urls {
+		doc.URLs = append(doc.URLs, xmlItem{Loc: u.Loc, LastMod: formatLastMod(u.LastMod)})
+	}
+	return marshal(doc)
+}
+
+// Index 生成 <sitemapindex>
+func Index(sitemaps []Sitemap) ([]byte, error) {
+	doc := sitemapIndex{NS: namespace, Sitemaps: make([]xmlItem, 0, len(sitemaps))}
+	for _, s := range sitemaps {
+		doc.Sitemaps = append(doc.Sitemaps, xmlItem{Loc: s.Loc, LastMod: formatLastMod(s.LastMod)})
+	}
+	return marshal(doc)
+}
+
+// formatLastMod lastmod 使用 W3C Datetime 格式
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,124 @@
+package spam
+
+import (
+	"go-my-blog/pkg/cjk"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// TokenCount 单个词在训练语料中出现的文档数
+type TokenCount struct {
+	Spam int64 // 出现过该词的垃圾评论数
+	Ham  int64 // 出现过该词的正常评论数
+}
+
+// 未训练过的词的先验概率及其权重（Robinson 平滑）
+const (
+	unknownProbability = 0.5
+	unknownStrength    = 1.0
+	// 单词概率的上下限，避免个别词把结果直接推到 0 或 1
+	minProbability = 0.01
+	maxProbability = 0.99
+)
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)[^\s<>"']+`)
+
+// CountLinks 统计文本中的链接数量
+func CountLinks(text string) int {
+	return len(linkPattern.FindAllStringIndex(text, -1))
+}
+
+// Tokenize 将文本切分为去重后的词：英文/数字按单词切分并转小写，
+// 中日韩文字没有空格分词，按相邻两个字组成二元词
+func Tokenize(text string) []string {
+	seen := make(map[string]struct{})
+	var tokens []string
+	add := func(token string) {
+		// 词表字段为 varchar(100)，过长的词（通常是随机链接）没有统计意义
+		if len(token) > 100 {
+			return
+		}
+		if _, ok := seen[token]; ok {
+			return
+		}
+		seen[token] = struct{}{}
+		tokens = append(tokens, token)
+	}
+
+	// 链接整体作为一个词，并额外记录域名
+	for _, link := range linkPattern.FindAllString(text, -1) {
+		link = strings.ToLower(link)
+		add("link:" + linkHost(link))
+	}
+	text = linkPattern.ReplaceAllString(text, " ")
+
+	var word []rune
+	var prevCJK rune
+	flushWord := func() {
+		if len(word) >= 2 && len(word) <= 30 {
+			add(strings.ToLower(string(word)))
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		switch {
+		case cjk.Is(r):
+			flushWord()
+			if prevCJK != 0 {
+				add(string([]rune{prevCJK, r}))
+			}
+			prevCJK = r
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			prevCJK = 0
+			word = append(word, r)
+		default:
+			prevCJK = 0
+			flushWord()
+		}
+	}
+	flushWord()
+	return tokens
+}
+
+// Classify 朴素贝叶斯打分：返回 0~1 之间的垃圾概率
+// 参数:
+//   - tokens: Tokenize 得到的词列表
+//   - counts: 词在训练语料中的统计，没有出现的词按未知词处理
+//   - spamDocs/hamDocs: 训练过的垃圾/正常评论总数
+func Classify(tokens []string, counts map[string]TokenCount, spamDocs int64, hamDocs int64) float64 {
+	if spamDocs == 0 || hamDocs == 0 || len(tokens) == 0 {
+		return unknownProbability
+	}
+
+	// Robinson 组合：在对数空间累加，避免大量小概率相乘下溢
+	var logSpam, logHam float64
+	for _, token := range tokens {
+		p := tokenProbability(counts[token], spamDocs, hamDocs)
+		logSpam += math.Log(p)
+		logHam += math.Log(1 - p)
+	}
+	return 1 / (1 + math.Exp(logHam-logSpam))
+}
+
+// tokenProbability 计算单个词的垃圾概率，并按出现次数向先验概率平滑
+func tokenProbability(count TokenCount, spamDocs int64, hamDocs int64) float64 {
+	n := float64(count.Spam + count.Ham)
+	if n == 0 {
+		return unknownProbability
+	}
+	spamFreq := float64(count.Spam) / float64(spamDocs)
+	hamFreq := float64(count.Ham) / float64(hamDocs)
+	p := spamFreq / (spamFreq + hamFreq)
+	p = (unknownStrength*unknownProbability + n*p) / (unknownStrength + n)
+	return math.Min(maxProbability, math.Max(minProbability, p))
+}
+
+func linkHost(link string) string {
+	link = strings.TrimPrefix(strings.TrimPrefix(link, "http://"), "https://")
+	if i := strings.IndexAny(link, "/?#"); i >= 0 {
+		link = link[:i]
+	}
+	return strings.TrimPrefix(link, "www.")
+}
@@ -0,0 +1,69 @@
+package spam
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCountLinks(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"没有链接", 0},
+		{"see https://a.com and www.b.org/x, HTTP://c.net", 3},
+		{"邮箱 user@example.com 不算链接", 0},
+	}
+	for _, tt := range tests {
+		if got := CountLinks(tt.text); got != tt.want {
+			t.Errorf("CountLinks(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Hello hello WORLD a 你好世界 https://www.Example.com/path?x=1")
+	// 链接记为域名，英文转小写去重，单字母丢弃，中文按相邻两字切分
+	want := []string{"link:example.com", "hello", "world", "你好", "好世", "世界"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %q, want %q", got, want)
+	}
+}
+
+func TestTokenizeMixedScript(t *testing.T) {
+	got := Tokenize("用Go写博客，" + strings.Repeat("x", 31))
+	// 中英文相邻时分别切分，中文二元词不跨越英文；超过 30 个字符的单词丢弃
+	want := []string{"go", "写博", "博客"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %q, want %q", got, want)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	counts := map[string]TokenCount{
+		"viagra": {Spam: 10},
+		"博客":     {Ham: 10},
+		"hello":  {Spam: 5, Ham: 5},
+	}
+	tests := []struct {
+		name     string
+		tokens   []string
+		spamDocs int64
+		hamDocs  int64
+		check    func(float64) bool
+	}{
+		{"未训练", []string{"viagra"}, 0, 10, func(p float64) bool { return p == 0.5 }},
+		{"没有词", nil, 10, 10, func(p float64) bool { return p == 0.5 }},
+		{"未知词", []string{"unknown"}, 10, 10, func(p float64) bool { return p == 0.5 }},
+		{"中性词", []string{"hello"}, 10, 10, func(p float64) bool { return p == 0.5 }},
+		{"垃圾词", []string{"viagra"}, 10, 10, func(p float64) bool { return p > 0.9 && p < 1 }},
+		{"正常词", []string{"博客"}, 10, 10, func(p float64) bool { return p < 0.1 && p > 0 }},
+		{"垃圾词多于正常词", []string{"viagra", "viagra2", "hello"}, 10, 10, func(p float64) bool { return p > 0.5 }},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.tokens, counts, tt.spamDocs, tt.hamDocs); !tt.check(got) {
+			t.Errorf("%s: Classify = %f", tt.name, got)
+		}
+	}
+}
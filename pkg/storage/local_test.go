@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalPutOpenDelete(t *testing.T) {
+	root := t.TempDir()
+	s := NewLocalStorage(root, "/uploads/")
+	key := "media/2026/10/photo.jpg"
+	content := []byte("image-bytes")
+
+	if err := s.Put(key, bytes.NewReader(content), int64(len(content)), "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := s.Open(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if !bytes.Equal(got, content) {
+		t.Errorf("Open = %q, want %q", got, content)
+	}
+
+	// 写入完成后目录中不应残留临时文件
+	entries, _ := os.ReadDir(filepath.Join(root, "media", "2026", "10"))
+	if len(entries) != 1 {
+		t.Errorf("目录中有 %d 个文件，want 1", len(entries))
+	}
+
+	if err := s.Delete(key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Open(key); !os.IsNotExist(err) {
+		t.Errorf("删除后 Open 应返回不存在，实际为 %v", err)
+	}
+	if err := s.Delete(key); err != nil {
+		t.Errorf("删除不存在的文件不应报错: %v", err)
+	}
+
+	if got, want := s.URL(key), "/uploads/media/2026/10/photo.jpg"; got != want {
+		t.Errorf("URL = %q, want %q", got, want)
+	}
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	s := NewLocalStorage(t.TempDir(), "/uploads")
+	for _, key := range []string{"../secret.txt", "media/../../secret.txt", "/etc/passwd", ""} {
+		if err := s.Put(key, bytes.NewReader(nil), 0, "text/plain"); err == nil {
+			t.Errorf("Put(%q) 应被拒绝", key)
+		}
+		if _, err := s.Open(key); err == nil {
+			t.Errorf("Open(%q) 应被拒绝", key)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testAccessKey = "minioadmin"
+	testSecretKey = "minio-secret"
+	testBucket    = "media"
+	testRegion    = "us-east-1"
+)
+
+// fakeS3 本地 S3 替身：按 path-style 保存对象，并独立重算 Signature V4 校验每个请求
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	fake := &fakeS3{t: t, objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !f.verifySignature(r) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>")
+		return
+	}
+	prefix := "/" + testBucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, "<Error><Code>NoSuchBucket</Code></Error>")
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		if r.ContentLength != int64(len(body)) {
+			f.t.Errorf("Content-Length = %d, 实际请求体 %d 字节", r.ContentLength, len(body))
+		}
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySignature 按 AWS Signature V4 规范在服务端重新计算签名并与 Authorization 头比对
+func (f *fakeS3) verifySignature(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	amzDate := r.Header.Get("X-Amz-Date")
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 ") || len(amzDate) != 16 || payloadHash == "" {
+		return false
+	}
+	fields := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 "), ", ") {
+		name, value, _ := strings.Cut(part, "=")
+		fields[name] = value
+	}
+	date := amzDate[:8]
+	scope := date + "/" + testRegion + "/s3/aws4_request"
+	if fields["Credential"] != testAccessKey+"/"+scope {
+		return false
+	}
+
+	signedHeaders := strings.Split(fields["SignedHeaders"], ";")
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		fields["SignedHeaders"],
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	mac := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+	key := mac([]byte("AWS4"+testSecretKey), date)
+	key = mac(key, testRegion)
+	key = mac(key, "s3")
+	key = mac(key, "aws4_request")
+	expected := hex.EncodeToString(mac(key, stringToSign))
+	return hmac.Equal([]byte(expected), []byte(fields["Signature"]))
+}
+
+func newTestS3Storage(t *testing.T, endpoint string, secretKey string) *S3Storage {
+	t.Helper()
+	s, err := NewS3Storage(S3Options{
+		Endpoint:  endpoint,
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: secretKey,
+		PathStyle: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestS3PutOpenDelete(t *testing.T) {
+	fake, server := newFakeS3(t)
+	s := newTestS3Storage(t, server.URL, testSecretKey)
+
+	// key 中的空格、加号和中文需要按 S3 规则编码，签名才能与服务端一致
+	for _, key := range []string{"media/2026/10/photo.jpg", "media/2026/10/my photo+1 图片.png"} {
+		content := []byte("image-bytes:" + key)
+		if err := s.Put(key, bytes.NewReader(content), int64(len(content)), "image/jpeg"); err != nil {
+			t.Fatalf("Put(%q): %v", key, err)
+		}
+		if got := fake.types[key]; got != "image/jpeg" {
+			t.Errorf("Content-Type = %q, want image/jpeg", got)
+		}
+
+		rc, err := s.Open(key)
+		if err != nil {
+			t.Fatalf("Open(%q): %v", key, err)
+		}
+		got, _ := io.ReadAll(rc)
+		rc.Close()
+		if !bytes.Equal(got, content) {
+			t.Errorf("Open(%q) = %q, want %q", key, got, content)
+		}
+
+		if err := s.Delete(key); err != nil {
+			t.Fatalf("Delete(%q): %v", key, err)
+		}
+		if _, ok := fake.objects[key]; ok {
+			t.Errorf("Delete(%q) 后对象仍存在", key)
+		}
+		// 删除不存在的对象不报错
+		if err := s.Delete(key); err != nil {
+			t.Errorf("重复 Delete(%q): %v", key, err)
+		}
+	}
+}
+
+func TestS3OpenMissing(t *testing.T) {
+	_, server := newFakeS3(t)
+	s := newTestS3Storage(t, server.URL, testSecretKey)
+
+	if _, err := s.Open("media/missing.jpg"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("Open 不存在的对象应返回 404 错误，实际为 %v", err)
+	}
+}
+
+func TestS3RejectsWrongSecret(t *testing.T) {
+	_, server := newFakeS3(t)
+	s := newTestS3Storage(t, server.URL, "wrong-secret")
+
+	content := []byte("data")
+	err := s.Put("media/a.jpg", bytes.NewReader(content), int64(len(content)), "image/jpeg")
+	if err == nil || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Fatalf("密钥错误时应返回签名错误，实际为 %v", err)
+	}
+}
+
+func TestS3URL(t *testing.T) {
+	pathStyle, err := NewS3Storage(S3Options{Endpoint: "http://127.0.0.1:9000/", Bucket: "media", PathStyle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := pathStyle.URL("a/b c.jpg"), "http://127.0.0.1:9000/media/a/b%20c.jpg"; got != want {
+		t.Errorf("path-style URL = %q, want %q", got, want)
+	}
+
+	virtualHost, err := NewS3Storage(S3Options{Endpoint: "https://s3.us-east-1.amazonaws.com", Bucket: "media"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := virtualHost.URL("a/b.jpg"), "https://media.s3.us-east-1.amazonaws.com/a/b.jpg"; got != want {
+		t.Errorf("virtual-hosted URL = %q, want %q", got, want)
+	}
+
+	cdn, err := NewS3Storage(S3Options{Endpoint: "https://s3.example.com", Bucket: "media", PublicBaseURL: "https://cdn.example.com/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cdn.URL("a/b.jpg"), "https://cdn.example.com/a/b.jpg"; got != want {
+		t.Errorf("CDN URL = %q, want %q", got, want)
+	}
+}
+
+func TestNewS3StorageValidatesOptions(t *testing.T) {
+	if _, err := NewS3Storage(S3Options{Bucket: "media"}); err == nil {
+		t.Error("缺少 endpoint 时应返回错误")
+	}
+	if _, err := NewS3Storage(S3Options{Endpoint: "http://127.0.0.1:9000"}); err == nil {
+		t.Error("缺少 bucket 时应返回错误")
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"errors"
+	"go-my-blog/config"
+	"io"
+)
+
+// 存储类型
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Storage 媒体文件存储抽象：key 为形如 media/2026/10/xxx.jpg 的相对路径
+type Storage interface {
+	// Put 写入文件，size 为内容长度
+	Put(key string, body io.Reader, size int64, contentType string) error
+	// Open 读取文件，调用方负责关闭
+	Open(key string) (io.ReadCloser, error)
+	// Delete 删除文件，文件不存在时不报错
+	Delete(key string) error
+	// URL 文件的公开访问地址
+	URL(key string) string
+}
+
+// New 根据媒体配置创建存储
+func New(conf config.MediaConfig) (Storage, error) {
+	switch conf.Storage {
+	case DriverLocal:
+		return NewLocalStorage(conf.Local.Root, conf.Local.BaseURL), nil
+	case DriverS3:
+		return NewS3Storage(S3Options{
+			Endpoint:      conf.S3.Endpoint,
+			Region:        conf.S3.Region,
+			Bucket:        conf.S3.Bucket,
+			AccessKey:     conf.S3.AccessKey,
+			SecretKey:     conf.S3.SecretKey,
+			PathStyle:     conf.S3.PathStyle,
+			PublicBaseURL: conf.S3.PublicBaseURL,
+		})
+	default:
+		return nil, errors.New("不支持的存储类型：" + conf.Storage)
+	}
+}
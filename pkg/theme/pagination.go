@@ -0,0 +1,90 @@
+package theme
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Pagination 分页信息，模板中通过 .Pagination 使用
+type Pagination struct {
+	Page       int
+	PageSize   int
+	Total      int64
+	TotalPages int
+	BasePath   string // 列表页地址，如 /tags/go；第 N 页为 /tags/go/page/N，便于导出为静态文件
+}
+
+// NewPagination 根据当前页、每页条数和总数计算分页
+func NewPagination(page int, pageSize int, total int64, basePath string) Pagination {
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	if page < 1 {
+		page = 1
+	}
+	return Pagination{Page: page, PageSize: pageSize, Total: total, TotalPages: totalPages, BasePath: basePath}
+}
+
+// ParsePage 解析页码（路径中的 /page/N 或兼容的 ?page=N），取第一个非空值，非法值按第一页处理
+func ParsePage(values ...string) int {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return 1
+		}
+		return page
+	}
+	return 1
+}
+
+func (p Pagination) HasPrev() bool {
+	return p.Page > 1
+}
+
+func (p Pagination) HasNext() bool {
+	return p.Page < p.TotalPages
+}
+
+func (p Pagination) PrevURL() string {
+	return p.URL(p.Page - 1)
+}
+
+func (p Pagination) NextURL() string {
+	return p.URL(p.Page + 1)
+}
+
+// URL 第 page 页的地址，第一页即列表页本身，以免产生重复页面
+func (p Pagination) URL(page int) string {
+	if page <= 1 {
+		return p.BasePath
+	}
+	return strings.TrimSuffix(p.BasePath, "/") + "/page/" + strconv.Itoa(page)
+}
+
+// Pages 当前页附近的页码（最多 5 个），用于输出页码导航
+func (p Pagination) Pages() []int {
+	start := p.Page - 2
+	if start < 1 {
+		start = 1
+	}
+	end := start + 4
+	if end > p.TotalPages {
+		end = p.TotalPages
+		start = end - 4
+		if start < 1 {
+			start = 1
+		}
+	}
+	pages := make([]int, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		pages = append(pages, i)
+	}
+	return pages
+}
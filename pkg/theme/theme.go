@@ -0,0 +1,168 @@
+package theme
+
+import (
+	"embed"
+	"errors"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// 主题必须提供的页面模板；自定义主题缺少的文件会回退到内置默认主题
+var Pages = []string{"home", "post", "tag", "author", "archive", "404"}
+
+// 所有页面共用的模板文件：layout.html 定义 "layout"，partials.html 定义分页等片段
+var shared = []string{"layout.html", "partials.html"}
+
+//go:embed default
+var embedded embed.FS
+
+// Theme 一套 html/template 主题：每个页面模板与共用模板一起解析，页面模板定义 "content"、"title" 等块
+type Theme struct {
+	fsys   fs.FS
+	reload bool
+
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+}
+
+// New 加载主题；dir 为空时使用内置默认主题。reload 为 true（调试模式）时每次渲染都重新解析模板，修改后刷新即可生效
+func New(dir string, reload bool) (*Theme, error) {
+	defaultFS, err := fs.Sub(embedded, "default")
+	if err != nil {
+		return nil, err
+	}
+	t := &Theme{fsys: defaultFS, reload: reload}
+	if dir != "" {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, errors.New("主题路径不是目录：" + dir)
+		}
+		t.fsys = overlayFS{primary: os.DirFS(dir), fallback: defaultFS}
+	}
+
+	templates, err := t.parse()
+	if err != nil {
+		return nil, err
+	}
+	t.templates = templates
+	return t, nil
+}
+
+// Render 使用页面模板渲染，输出入口为 "layout"
+func (t *Theme) Render(w io.Writer, page string, data interface{}) error {
+	templates := t.current()
+	if t.reload {
+		reloaded, err := t.parse()
+		if err != nil {
+			return err
+		}
+		t.mu.Lock()
+		t.templates = reloaded
+		t.mu.Unlock()
+		templates = reloaded
+	}
+	tmpl, ok := templates[page]
+	if !ok {
+		return errors.New("主题缺少页面模板：" + page)
+	}
+	return tmpl.ExecuteTemplate(w, "layout", data)
+}
+
+// Static 主题的静态资源目录（static/）
+func (t *Theme) Static() fs.FS {
+	static, err := fs.Sub(t.fsys, "static")
+	if err != nil {
+		return t.fsys
+	}
+	return static
+}
+
+func (t *Theme) current() map[string]*template.Template {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.templates
+}
+
+// parse 解析全部页面模板，任一模板有语法错误时整体失败
+func (t *Theme) parse() (map[string]*template.Template, error) {
+	base := template.New("").Funcs(funcs)
+	for _, name := range shared {
+		if err := parseFile(base, t.fsys, name); err != nil {
+			return nil, err
+		}
+	}
+
+	templates := make(map[string]*template.Template, len(Pages))
+	for _, page := range Pages {
+		tmpl, err := base.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if err := parseFile(tmpl, t.fsys, page+".html"); err != nil {
+			return nil, err
+		}
+		templates[page] = tmpl
+	}
+	return templates, nil
+}
+
+func parseFile(tmpl *template.Template, fsys fs.FS, name string) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	_, err = tmpl.New(name).Parse(string(data))
+	return err
+}
+
+// overlayFS 优先读取自定义主题目录，不存在的文件回退到内置主题
+type overlayFS struct {
+	primary  fs.FS
+	fallback fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.primary.Open(name)
+	if err == nil {
+		return f, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return o.fallback.Open(name)
+	}
+	return nil, err
+}
+
+// funcs 模板中可用的辅助函数
+var funcs = template.FuncMap{
+	// safeHTML 输出服务端已清洗过的 HTML（文章、评论正文），不要用于用户原始输入
+	"safeHTML": func(s string) template.HTML {
+		return template.HTML(s)
+	},
+	"formatDate": func(v interface{}, layout string) string {
+		switch t := v.(type) {
+		case time.Time:
+			return t.Format(layout)
+		case string:
+			if parsed, err := time.ParseInLocation("2006-01-02 15:04:05", t, time.Local); err == nil {
+				return parsed.Format(layout)
+			}
+			return t
+		}
+		return ""
+	},
+	"pathEscape": url.PathEscape,
+	"add": func(a, b int) int {
+		return a + b
+	},
+	"sub": func(a, b int) int {
+		return a - b
+	},
+}
@@ -0,0 +1,144 @@
+package wxr
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Author 站点作者（<wp:author>）
+type Author struct {
+	ID          int    `xml:"author_id"`
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+// Category 分类或标签：Domain 为 category 或 post_tag
+type Category struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+// Comment 评论（<wp:comment>）
+type Comment struct {
+	ID          int    `xml:"comment_id"`
+	Author      string `xml:"comment_author"`
+	AuthorEmail string `xml:"comment_author_email"`
+	AuthorURL   string `xml:"comment_author_url"`
+	AuthorIP    string `xml:"comment_author_IP"`
+	Date        string `xml:"comment_date"`
+	DateGMT     string `xml:"comment_date_gmt"`
+	Content     string `xml:"comment_content"`
+	Approved    string `xml:"comment_approved"` // 1 已通过 / 0 待审核 / spam / trash
+	Type        string `xml:"comment_type"`     // 空或 comment 为普通评论，pingback/trackback 为引用通告
+	Parent      int    `xml:"comment_parent"`
+	UserID      int    `xml:"user_id"`
+}
+
+// Item 文章、页面、附件等内容（<item>），通过 PostType 区分
+type Item struct {
+	Title           string     `xml:"title"`
+	Link            string     `xml:"link"`
+	GUID            string     `xml:"guid"`
+	Creator         string     `xml:"creator"`
+	Content         string     `xml:"http://purl.org/rss/1.0/modules/content/ encoded"` // 只匹配 content:encoded，不匹配 excerpt:encoded
+	PostID          int        `xml:"post_id"`
+	PostDate        string     `xml:"post_date"`
+	PostDateGMT     string     `xml:"post_date_gmt"`
+	PostModified    string     `xml:"post_modified"`
+	PostModifiedGMT string     `xml:"post_modified_gmt"`
+	PostName        string     `xml:"post_name"`
+	Status          string     `xml:"status"`
+	PostType        string     `xml:"post_type"`
+	Categories      []Category `xml:"category"`
+	Comments        []Comment  `xml:"comment"`
+}
+
+// Parse 流式解析 WXR：逐个解码 <wp:author> 和 <item> 并回调，内存占用与单篇文章大小相关，与文件总大小无关
+func Parse(r io.Reader, onAuthor func(*Author) error, onItem func(*Item) error) error {
+	decoder := xml.NewDecoder(r)
+	// WordPress 导出偶尔包含非法字符实体，宽松模式下尽量继续解析
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch {
+		case start.Name.Local == "author" && isWordPressNS(start.Name.Space):
+			var author Author
+			if err := decoder.DecodeElement(&author, &start); err != nil {
+				return err
+			}
+			if err := onAuthor(&author); err != nil {
+				return err
+			}
+		case start.Name.Local == "item":
+			var item Item
+			if err := decoder.DecodeElement(&item, &start); err != nil {
+				return err
+			}
+			if err := onItem(&item); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// isWordPressNS WXR 1.0/1.1/1.2 的 wp 命名空间，如 http://wordpress.org/export/1.2/
+func isWordPressNS(space string) bool {
+	return strings.Contains(space, "wordpress.org/export/") && !strings.HasSuffix(space, "/excerpt/")
+}
+
+// ParseDate 解析 WordPress 日期：优先使用 GMT 时间，未设置（0000-00-00，常见于草稿）时使用站点本地时间
+func ParseDate(gmt string, local string) *time.Time {
+	const layout = "2006-01-02 15:04:05"
+	if t, err := time.ParseInLocation(layout, strings.TrimSpace(gmt), time.UTC); err == nil && t.Year() > 1 {
+		return &t
+	}
+	if t, err := time.ParseInLocation(layout, strings.TrimSpace(local), time.Local); err == nil && t.Year() > 1 {
+		return &t
+	}
+	return nil
+}
+
+var (
+	// blockTag 已经是块级 HTML 的段落不再包裹 <p>
+	blockTag = regexp.MustCompile(`^<(?:p|div|h[1-6]|ul|ol|li|pre|blockquote|table|figure|hr|!--)[\s>/]`)
+	// paragraphBreak 空行分隔段落
+	paragraphBreak = regexp.MustCompile(`\n\s*\n`)
+)
+
+// AutoP 近似 WordPress 的 wpautop：经典编辑器保存的正文没有 <p>，依靠空行分段、换行转 <br>。
+// 块编辑器（Gutenberg）保存的正文已是完整 HTML，原样返回
+func AutoP(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if strings.Contains(content, "<!-- wp:") || strings.Contains(content, "<p>") || strings.Contains(content, "<p ") {
+		return content
+	}
+	var b strings.Builder
+	for _, paragraph := range paragraphBreak.Split(strings.TrimSpace(content), -1) {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		if blockTag.MatchString(paragraph) {
+			b.WriteString(paragraph + "\n")
+			continue
+		}
+		b.WriteString("<p>" + strings.ReplaceAll(paragraph, "\n", "<br>\n") + "</p>\n")
+	}
+	return b.String()
+}
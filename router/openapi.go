@@ -0,0 +1,354 @@
+package router
+
+import (
+	"go-my-blog/config"
+	"go-my-blog/internal/DTO"
+	"go-my-blog/internal/handler"
+	"go-my-blog/internal/middleware"
+	"go-my-blog/internal/request"
+	"go-my-blog/internal/response"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/openapi"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// 文档只描述 JSON 接口；订阅源、站点地图和 HTML 前台不在其中
+const (
+	apiPrefix    = "/api/"
+	securePrefix = "/api/v2/" // 需要携带 Bearer 令牌的接口
+	bearerAuth   = "bearerAuth"
+)
+
+// 接口分组
+const (
+	tagUser       = "用户"
+	tagPost       = "文章"
+	tagComment    = "评论"
+	tagModeration = "评论审核"
+	tagMedia      = "媒体库"
+	tagTrash      = "回收站"
+	tagAccount    = "个人数据与账号"
+	tagAdmin      = "管理"
+)
+
+var apiTags = []openapi.Tag{
+	{Name: tagUser, Description: "注册与登录"},
+	{Name: tagPost, Description: "文章的创建、编辑与查询"},
+	{Name: tagComment, Description: "登录用户与游客的评论"},
+	{Name: tagModeration, Description: "需版主权限"},
+	{Name: tagMedia, Description: "图片上传与管理"},
+	{Name: tagTrash, Description: "已删除内容的恢复与永久删除"},
+	{Name: tagAccount, Description: "个人数据导出、账号注销与语言偏好"},
+	{Name: tagAdmin, Description: "需管理员权限"},
+}
+
+// apiDoc 单个接口的文档描述，请求和响应直接引用 request、response 包中的结构体，字段与校验规则由反射生成
+type apiDoc struct {
+	tag     string
+	summary string
+	query   interface{} // 查询参数（form 标签）
+	body    interface{} // JSON 请求体
+	upload  string      // multipart/form-data 上传的文件字段名
+	status  int         // 成功时的状态码，默认 200
+	data    interface{} // 成功响应 data 字段的内容，nil 表示只返回 msg
+	file    string      // 响应为文件下载时的 Content-Type
+	errors  []int       // 除参数错误、未登录和服务器错误外可能返回的状态码
+}
+
+// apiDocs 以 "方法 路径" 为键描述 InitRouter 注册的每个接口，新增接口时需同步补充，否则启动时会记录警告
+var apiDocs = map[string]apiDoc{
+	// 公开接口
+	"POST /api/v1/register": {tag: tagUser, summary: "用户注册",
+		body: request.RegisterRequest{}, data: response.UserResponse{}, errors: []int{http.StatusConflict}},
+	"POST /api/v1/login": {tag: tagUser, summary: "用户登录",
+		body: request.LoginRequest{}, data: response.LoginResponse{}, errors: []int{http.StatusUnauthorized, http.StatusForbidden}},
+	"GET /api/v1/comment-challenge": {tag: tagComment, summary: "获取游客评论的工作量证明题目",
+		data: response.GuestChallengeResponse{}, errors: []int{http.StatusForbidden}},
+	"POST /api/v1/posts/:postID/comments": {tag: tagComment, summary: "游客发表评论（进入审核队列）",
+		body: request.CreateGuestCommentRequest{}, data: response.CreateCommentResponse{},
+		errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict}},
+
+	// 文章
+	"POST /api/v2/posts": {tag: tagPost, summary: "创建文章",
+		body: request.CreatePostRequest{}, data: response.CreatePostResponse{}, errors: []int{http.StatusForbidden}},
+	"PUT /api/v2/posts/:id": {tag: tagPost, summary: "更新文章",
+		body: request.UpdatePostRequest{}, data: response.UpdatePostResponse{},
+		errors: []int{http.StatusForbidden, http.StatusNotFound}},
+	"DELETE /api/v2/posts/:id": {tag: tagPost, summary: "删除文章（移入回收站）",
+		errors: []int{http.StatusForbidden, http.StatusNotFound}},
+	"GET /api/v2/posts": {tag: tagPost, summary: "文章列表（分页、筛选、排序）",
+		query: request.PostListRequest{}, data: response.PostListResponse{},
+		errors: []int{http.StatusForbidden, http.StatusNotFound}},
+	"GET /api/v2/posts/:id": {tag: tagPost, summary: "文章详情",
+		data: response.PostDetailResponse{}, errors: []int{http.StatusForbidden, http.StatusNotFound}},
+
+	// 评论
+	"POST /api/v2/posts/:postID/comments": {tag: tagComment, summary: "发布评论",
+		body: request.CreateCommentRequest{}, data: response.CreateCommentResponse{},
+		errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict}},
+	"GET /api/v2/comments/:postID": {tag: tagComment, summary: "文章的评论列表",
+		data: []DTO.CommentDetailDTO{}, errors: []int{http.StatusNotFound}},
+	"PUT /api/v2/comments/:id": {tag: tagComment, summary: "编辑评论",
+		body: request.UpdateCommentRequest{}, data: response.UpdateCommentResponse{},
+		errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict}},
+	"DELETE /api/v2/comments/:id": {tag: tagComment, summary: "删除自己的评论",
+		errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict}},
+	"GET /api/v2/comment-threads/:id": {tag: tagComment, summary: "评论所在的整棵回复树",
+		data: response.CommentDetailResponse{}, errors: []int{http.StatusNotFound}},
+
+	// 评论审核
+	"GET /api/v2/moderation/comments": {tag: tagModeration, summary: "审核队列",
+		query: request.ModerationQueueRequest{}, data: response.ModerationCommentListResponse{},
+		errors: []int{http.StatusForbidden}},
+	"POST /api/v2/moderation/comments": {tag: tagModeration, summary: "批量通过/拒绝评论",
+		body: request.ModerateCommentsRequest{}, data: response.ModerateResultResponse{},
+		errors: []int{http.StatusForbidden}},
+
+	// 媒体库
+	"POST /api/v2/media": {tag: tagMedia, summary: "上传图片",
+		upload: "file", data: response.MediaResponse{}, errors: []int{http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity}},
+	"GET /api/v2/media": {tag: tagMedia, summary: "我的媒体列表",
+		query: request.ListMediaRequest{}, data: response.MediaListResponse{}},
+	"GET /api/v2/media/:id": {tag: tagMedia, summary: "媒体详情（含引用文章）",
+		data: response.MediaResponse{}, errors: []int{http.StatusForbidden, http.StatusNotFound}},
+	"DELETE /api/v2/media/:id": {tag: tagMedia, summary: "删除媒体",
+		query: request.DeleteMediaRequest{}, errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict}},
+
+	// 回收站
+	"GET /api/v2/trash/posts": {tag: tagTrash, summary: "已删除的文章",
+		query: request.ListTrashRequest{}, data: response.TrashPostListResponse{}},
+	"POST /api/v2/trash/posts/:id/restore": {tag: tagTrash, summary: "恢复文章（连同随文章删除的评论）",
+		data: response.RestorePostResponse{}, errors: []int{http.StatusForbidden, http.StatusNotFound}},
+	"DELETE /api/v2/trash/posts/:id": {tag: tagTrash, summary: "永久删除文章",
+		errors: []int{http.StatusForbidden, http.StatusNotFound}},
+	"GET /api/v2/trash/comments": {tag: tagTrash, summary: "单独删除的评论",
+		query: request.ListTrashRequest{}, data: response.TrashCommentListResponse{}},
+	"POST /api/v2/trash/comments/:id/restore": {tag: tagTrash, summary: "恢复评论",
+		errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict}},
+	"DELETE /api/v2/trash/comments/:id": {tag: tagTrash, summary: "永久删除评论",
+		errors: []int{http.StatusForbidden, http.StatusNotFound}},
+
+	// 个人数据与账号
+	"POST /api/v2/me/export": {tag: tagAccount, summary: "申请导出个人数据（后台生成）",
+		status: http.StatusAccepted, data: response.DataExportResponse{}, errors: []int{http.StatusConflict}},
+	"GET /api/v2/me/exports/:id": {tag: tagAccount, summary: "导出进度",
+		data: response.DataExportResponse{}, errors: []int{http.StatusNotFound}},
+	"GET /api/v2/me/exports/:id/download": {tag: tagAccount, summary: "下载个人数据归档",
+		file: "application/gzip", errors: []int{http.StatusNotFound, http.StatusConflict}},
+	"DELETE /api/v2/me": {tag: tagAccount, summary: "申请注销账号（冷静期后删除）",
+		body: request.DeleteAccountRequest{}, status: http.StatusAccepted, data: response.AccountDeletionResponse{},
+		errors: []int{http.StatusForbidden, http.StatusConflict}},
+	"DELETE /api/v2/me/deletion": {tag: tagAccount, summary: "撤销注销申请",
+		errors: []int{http.StatusConflict}},
+	"PUT /api/v2/me/locale": {tag: tagAccount, summary: "设置提示信息的语言偏好",
+		body: request.UpdateLocaleRequest{}, data: response.LocaleResponse{}},
+
+	// 管理
+	"GET /api/v2/admin/export": {tag: tagAdmin, summary: "下载整站数据归档",
+		file: "application/gzip", errors: []int{http.StatusForbidden}},
+}
+
+// 错误响应，键为状态码
+var errorResponses = map[int]struct {
+	name        string
+	description string
+}{
+	http.StatusBadRequest:            {"BadRequest", "请求参数格式错误"},
+	http.StatusUnauthorized:          {"Unauthorized", "未登录或令牌无效"},
+	http.StatusForbidden:             {"Forbidden", "无权执行该操作"},
+	http.StatusNotFound:              {"NotFound", "资源不存在"},
+	http.StatusConflict:              {"Conflict", "与资源的当前状态冲突"},
+	http.StatusRequestEntityTooLarge: {"TooLarge", "上传内容过大"},
+	http.StatusUnprocessableEntity:   {"ValidationFailed", "请求参数校验失败，details.fields 列出未通过校验的字段"},
+	http.StatusInternalServerError:   {"InternalError", "服务器内部错误"},
+	http.StatusGatewayTimeout:        {"Timeout", "请求处理超时"},
+}
+
+// registerDocs 根据已注册的路由生成 OpenAPI 文档，提供 /openapi.json 和交互式文档页面 /docs；需在其他路由注册完成后调用
+func registerDocs(r *gin.Engine) {
+	doc, undocumented := buildOpenAPI(r.Routes())
+	for _, route := range undocumented {
+		logger.Warn("接口缺少 OpenAPI 描述，请在 router/openapi.go 中补充", zap.String("route", route))
+	}
+
+	docsHandler := handler.NewDocsHandler(doc)
+	r.GET("/openapi.json", docsHandler.Spec)
+	r.GET("/docs", docsHandler.Page)
+}
+
+// buildOpenAPI 为路由表中的每个接口生成操作，同时返回 apiDocs 中没有描述的接口（仍以最简形式写入文档）
+func buildOpenAPI(routes gin.RoutesInfo) (*openapi.Document, []string) {
+	info := openapi.Info{Title: "go-my-blog API", Version: "1.0.0"}
+	if name := config.Conf.Site.Name; name != "" {
+		info.Title = name + " API"
+	}
+	info.Description = "成功时返回 {msg, data}；失败时返回 {code, message, details, request_id}，" +
+		"请求头 Accept: application/problem+json 时返回 RFC 7807 格式。提示信息的语言按 Accept-Language 协商。"
+
+	doc := openapi.New(info)
+	doc.Tags = apiTags
+	if baseURL := config.Conf.Site.BaseURL; baseURL != "" {
+		doc.Servers = []openapi.Server{{URL: strings.TrimRight(baseURL, "/")}}
+	}
+	doc.Components.SecuritySchemes[bearerAuth] = &openapi.SecurityScheme{
+		Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "登录接口返回的 access_token",
+	}
+
+	gen := openapi.NewGenerator(doc.Components.Schemas)
+	gen.Rule("username", func(s *openapi.Schema, _ string) {
+		s.Pattern = request.UsernamePattern
+	})
+	gen.Rule("password", func(s *openapi.Schema, _ string) {
+		minLength, maxLength := request.PasswordMinLength, request.PasswordMaxLength
+		s.MinLength, s.MaxLength = &minLength, &maxLength
+		s.Description = "需同时包含字母和数字"
+	})
+	gen.Rule("clearable_url", func(s *openapi.Schema, _ string) {
+		s.Description = "有效网址，传空字符串清空"
+	})
+	addErrorResponses(doc, gen)
+
+	var undocumented []string
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, apiPrefix) {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		spec, ok := apiDocs[key]
+		if !ok {
+			undocumented = append(undocumented, key)
+			spec = apiDoc{summary: route.Handler}
+		}
+		doc.AddOperation(route.Method, openapi.PathTemplate(route.Path), buildOperation(gen, route, spec))
+	}
+	sort.Strings(undocumented)
+	return doc, undocumented
+}
+
+func buildOperation(gen *openapi.Generator, route gin.RouteInfo, spec apiDoc) *openapi.Operation {
+	op := &openapi.Operation{
+		Summary:     spec.summary,
+		OperationID: operationID(route.Handler),
+		Responses:   map[string]*openapi.Response{},
+	}
+	if spec.tag != "" {
+		op.Tags = []string{spec.tag}
+	}
+
+	errorStatuses := map[int]bool{http.StatusInternalServerError: true, http.StatusGatewayTimeout: true}
+	for _, name := range openapi.PathParams(route.Path) {
+		schema := &openapi.Schema{Type: "string"}
+		if strings.HasSuffix(strings.ToLower(name), "id") {
+			minimum := 1.0
+			schema = &openapi.Schema{Type: "integer", Minimum: &minimum}
+		}
+		op.Parameters = append(op.Parameters, &openapi.Parameter{Name: name, In: "path", Required: true, Schema: schema})
+		errorStatuses[http.StatusBadRequest] = true
+	}
+	if spec.query != nil {
+		op.Parameters = append(op.Parameters, gen.QueryParameters(spec.query)...)
+		errorStatuses[http.StatusBadRequest] = true
+		errorStatuses[http.StatusUnprocessableEntity] = true
+	}
+	if spec.body != nil {
+		op.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content:  map[string]openapi.MediaType{"application/json": {Schema: gen.Schema(spec.body)}},
+		}
+		errorStatuses[http.StatusBadRequest] = true
+		errorStatuses[http.StatusUnprocessableEntity] = true
+	}
+	if spec.upload != "" {
+		upload := openapi.Object(openapi.Property{Name: spec.upload, Schema: &openapi.Schema{Type: "string", Format: "binary"}})
+		upload.Required = []string{spec.upload}
+		op.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content:  map[string]openapi.MediaType{"multipart/form-data": {Schema: upload}},
+		}
+		errorStatuses[http.StatusBadRequest] = true
+	}
+	if strings.HasPrefix(route.Path, securePrefix) {
+		op.Security = []openapi.SecurityRequirement{{bearerAuth: {}}}
+		errorStatuses[http.StatusUnauthorized] = true
+	}
+	for _, status := range spec.errors {
+		errorStatuses[status] = true
+	}
+
+	status := spec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(status)] = successResponse(gen, spec)
+	for status := range errorStatuses {
+		op.Responses[strconv.Itoa(status)] = &openapi.Response{Ref: "#/components/responses/" + errorResponses[status].name}
+	}
+	return op
+}
+
+// successResponse 成功响应：文件下载直接返回文件内容，其余为 {msg, data} 结构
+func successResponse(gen *openapi.Generator, spec apiDoc) *openapi.Response {
+	if spec.file != "" {
+		return &openapi.Response{
+			Description: "文件内容",
+			Headers: map[string]*openapi.Header{
+				"Content-Disposition": {Description: "附件文件名", Schema: &openapi.Schema{Type: "string"}},
+			},
+			Content: map[string]openapi.MediaType{spec.file: {Schema: &openapi.Schema{Type: "string", Format: "binary"}}},
+		}
+	}
+	envelope := openapi.Object(openapi.Property{Name: "msg", Schema: &openapi.Schema{Type: "string", Description: "按请求语言翻译的提示信息"}})
+	envelope.Required = []string{"msg"}
+	if spec.data != nil {
+		envelope.Properties = append(envelope.Properties, openapi.Property{Name: "data", Schema: gen.Schema(spec.data)})
+		envelope.Required = append(envelope.Required, "data")
+	}
+	return &openapi.Response{
+		Description: "成功",
+		Content:     map[string]openapi.MediaType{"application/json": {Schema: envelope}},
+	}
+}
+
+// addErrorResponses 登记统一的错误响应，422 的 details.fields 为字段错误列表
+func addErrorResponses(doc *openapi.Document, gen *openapi.Generator) {
+	errorSchema := gen.Schema(middleware.ErrorResponse{})
+	problemSchema := gen.Schema(middleware.ProblemResponse{})
+	fieldErrors := openapi.Object(openapi.Property{Name: "fields", Schema: gen.Schema([]middleware.FieldError{})})
+	validationDetails := openapi.Object(openapi.Property{Name: "details", Schema: fieldErrors})
+
+	for status, resp := range errorResponses {
+		errSchema, problem := errorSchema, problemSchema
+		if status == http.StatusUnprocessableEntity {
+			errSchema = &openapi.Schema{AllOf: []*openapi.Schema{errorSchema, validationDetails}}
+			problem = &openapi.Schema{AllOf: []*openapi.Schema{problemSchema, validationDetails}}
+		}
+		doc.Components.Responses[resp.name] = &openapi.Response{
+			Description: resp.description,
+			Headers: map[string]*openapi.Header{
+				middleware.RequestIDHeader: {Description: "请求ID，与响应中的 request_id 一致", Schema: &openapi.Schema{Type: "string"}},
+			},
+			Content: map[string]openapi.MediaType{
+				"application/json":         {Schema: errSchema},
+				"application/problem+json": {Schema: problem},
+			},
+		}
+	}
+}
+
+// operationID 取处理函数名作为操作ID，如 go-my-blog/internal/handler.(*PostHandler).CreatePost-fm → PostHandler.CreatePost
+func operationID(handlerName string) string {
+	name := strings.TrimSuffix(handlerName, "-fm")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if _, after, ok := strings.Cut(name, "."); ok {
+		name = after
+	}
+	return strings.NewReplacer("(", "", ")", "", "*", "").Replace(name)
+}
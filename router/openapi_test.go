@@ -0,0 +1,142 @@
+package router
+
+import (
+	"encoding/json"
+	"go-my-blog/bootstrap"
+	"go-my-blog/config"
+	"go-my-blog/config/priority_config"
+	"go-my-blog/internal/handler"
+	"go-my-blog/internal/service"
+	"go-my-blog/pkg/logger"
+	"go-my-blog/pkg/openapi"
+	"go-my-blog/pkg/storage"
+	"go-my-blog/pkg/theme"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	// 日志输出到控制台且只记录错误，避免测试中写日志文件
+	priority_config.PriorityConf.Gin.Debug = true
+	priority_config.PriorityConf.Log.Level = "error"
+	logger.Init()
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// testContainer 只用于注册路由的容器：每个组件都是零值，路由表与真实容器一致，但不能处理业务请求
+func testContainer(t *testing.T) *bootstrap.Container {
+	t.Helper()
+	container := &bootstrap.Container{}
+	v := reflect.ValueOf(container).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Ptr && field.IsNil() && field.CanSet() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+	}
+	// 注册路由时会读取用户仓库，需要完整的调用链
+	container.UserHandler = handler.NewUserHandler(service.NewUserService(nil))
+	// 前台的主题静态文件路由需要加载主题，使用内置默认主题
+	siteTheme, err := theme.New("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	container.FrontendHandler = handler.NewFrontendHandler(nil, siteTheme)
+	return container
+}
+
+// 不在 OpenAPI 文档中的路由：返回的不是 JSON 接口
+var (
+	// 订阅源，包括作者、标签和分类订阅源
+	feedSuffixes = []string{"/feed.xml", "/atom.xml", "/feed.json"}
+	// 站点地图、robots.txt、文档本身和本地存储的媒体文件
+	nonAPIPrefixes = []string{"/sitemap.xml", "/sitemaps/", "/robots.txt", "/openapi.json", "/docs", testMediaPrefix + "/"}
+	// 开启 HTML 前台时注册的页面和主题静态文件
+	frontendPrefixes = []string{"/page/", "/posts/", "/tags/", "/authors/", "/archive", "/theme/"}
+)
+
+const testMediaPrefix = "/uploads"
+
+func isNonAPIRoute(path string, frontend bool) bool {
+	for _, suffix := range feedSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	prefixes := nonAPIPrefixes
+	if frontend {
+		if path == "/" {
+			return true
+		}
+		prefixes = append(prefixes, frontendPrefixes...)
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestOpenAPICoversAllRoutes(t *testing.T) {
+	for _, frontend := range []bool{false, true} {
+		previous := config.Conf
+		config.Conf = &config.AppConfig{}
+		config.Conf.Frontend.Enabled = frontend
+		config.Conf.Media.Storage = storage.DriverLocal
+		config.Conf.Media.Local.URLPrefix = testMediaPrefix
+		config.Conf.Media.Local.Root = t.TempDir()
+		r := gin.New()
+		InitRouter(r, testContainer(t))
+		config.Conf = previous
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET /openapi.json 状态码 = %d", w.Code)
+		}
+		var doc struct {
+			Paths map[string]map[string]json.RawMessage `json:"paths"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("OpenAPI 文档不是合法的 JSON: %v", err)
+		}
+
+		apiRoutes := 0
+		for _, route := range r.Routes() {
+			if !strings.HasPrefix(route.Path, apiPrefix) {
+				if !isNonAPIRoute(route.Path, frontend) {
+					t.Errorf("frontend=%t: 路由 %s %s 既不是 /api/ 接口，也不在排除列表中", frontend, route.Method, route.Path)
+				}
+				continue
+			}
+			apiRoutes++
+			path := openapi.PathTemplate(route.Path)
+			if _, ok := doc.Paths[path][strings.ToLower(route.Method)]; !ok {
+				t.Errorf("frontend=%t: 文档缺少接口 %s %s", frontend, route.Method, path)
+			}
+		}
+		if apiRoutes == 0 {
+			t.Fatal("没有找到 /api/ 接口")
+		}
+
+		// 文档中的每个接口都对应实际注册的路由，且都有 apiDocs 描述
+		documented := 0
+		for _, item := range doc.Paths {
+			documented += len(item)
+		}
+		if documented != apiRoutes {
+			t.Errorf("frontend=%t: 文档中有 %d 个接口，实际注册了 %d 个", frontend, documented, apiRoutes)
+		}
+		if _, undocumented := buildOpenAPI(r.Routes()); len(undocumented) != 0 {
+			t.Errorf("frontend=%t: 以下接口缺少 apiDocs 描述: %v", frontend, undocumented)
+		}
+	}
+}
@@ -2,18 +2,73 @@ package router
 
 import (
 	"go-my-blog/bootstrap"
+	"go-my-blog/config"
 	"go-my-blog/config/priority_config"
 	"go-my-blog/internal/middleware" // 引入中间件（如认证、日志）
+	"go-my-blog/internal/request"
+	"go-my-blog/pkg/storage"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // InitRouter 初始化路由：将所有 API 注册到 Gin 引擎
 func InitRouter(r *gin.Engine, container *bootstrap.Container) {
+	// 0. 请求参数校验：ShouldBind 系列方法绑定后按 validate 标签校验，失败时返回字段错误列表（422）
+	binding.Validator = &request.Validator{}
+
 	// 1. 全局中间件：所有路由都会经过的中间件（如日志、跨域）
+	r.Use(middleware.RequestID())                                         // 请求ID中间件（日志与错误响应据此关联到同一个请求）
+	r.Use(middleware.Locale())                                            // 语言协商中间件（按 Accept-Language 选择提示信息的语言）
 	r.Use(middleware.GinLogger())                                         // 自定义日志中间件（记录请求日志）
-	r.Use(middleware.GinRecovery(priority_config.PriorityConf.Gin.Debug)) // 异常恢复中间件（避免服务因 panic 崩溃）
 	r.Use(middleware.Cors())                                              // 跨域处理中间件（前端调用 API 时需要）
+	r.Use(middleware.Timeout())                                           // 请求超时中间件（超时或客户端断开时取消查询，返回 504/499）
+	r.Use(middleware.GinRecovery(priority_config.PriorityConf.Gin.Debug)) // 异常恢复中间件（避免服务因 panic 崩溃；在超时中间件之内，panic 不会被当作请求取消）
+	r.Use(middleware.ErrorHandler())                                      // 错误处理中间件（把 c.Error 记录的错误转换为统一的错误响应）
+
+	// 本地存储的媒体文件由 Gin 直接提供；S3 存储的文件由对象存储/CDN 提供
+	if config.Conf.Media.Storage == storage.DriverLocal {
+		r.Static(config.Conf.Media.Local.URLPrefix, config.Conf.Media.Local.Root)
+	}
+
+	// 订阅源（RSS 2.0 / Atom 1.0 / JSON Feed 1.1），支持 ?mode=full|excerpt
+	r.GET("/feed.xml", container.FeedHandler.RSS)
+	r.GET("/atom.xml", container.FeedHandler.Atom)
+	r.GET("/feed.json", container.FeedHandler.JSON)
+	r.GET("/authors/:username/feed.xml", container.FeedHandler.RSS) // 作者订阅源
+	r.GET("/authors/:username/atom.xml", container.FeedHandler.Atom)
+	r.GET("/authors/:username/feed.json", container.FeedHandler.JSON)
+	r.GET("/tags/:tag/feed.xml", container.FeedHandler.RSS) // 标签订阅源
+	r.GET("/tags/:tag/atom.xml", container.FeedHandler.Atom)
+	r.GET("/tags/:tag/feed.json", container.FeedHandler.JSON)
+	r.GET("/categories/:tag/feed.xml", container.FeedHandler.RSS) // 分类订阅源：分类以标签表示，与标签订阅源相同
+	r.GET("/categories/:tag/atom.xml", container.FeedHandler.Atom)
+	r.GET("/categories/:tag/feed.json", container.FeedHandler.JSON)
+
+	// 站点地图与 robots.txt
+	r.GET("/sitemap.xml", container.SitemapHandler.Sitemap)
+	r.GET("/sitemaps/:page", container.SitemapHandler.SitemapPage) // 拆分后的站点地图，如 /sitemaps/1.xml
+	r.GET("/robots.txt", container.SitemapHandler.Robots)
+
+	// HTML 前台（可选）：与接口共用同一个 Gin 引擎
+	if config.Conf.Frontend.Enabled {
+		r.StaticFS("/theme", http.FS(container.FrontendHandler.Static()))
+		r.GET("/", container.RedirectHandler.LegacyQuery, container.FrontendHandler.Home)
+		r.GET("/page/:page", container.FrontendHandler.Home)
+		r.GET("/posts/:id", container.FrontendHandler.Post)
+		r.GET("/tags/:tag", container.FrontendHandler.Tag)
+		r.GET("/tags/:tag/page/:page", container.FrontendHandler.Tag)
+		r.GET("/authors/:username", container.FrontendHandler.Author)
+		r.GET("/authors/:username/page/:page", container.FrontendHandler.Author)
+		r.GET("/archive", container.FrontendHandler.Archive)
+	}
+	// 未匹配的地址先查找迁移前旧地址的重定向，再返回 404
+	if config.Conf.Frontend.Enabled {
+		r.NoRoute(container.RedirectHandler.Redirect, container.FrontendHandler.NotFound)
+	} else {
+		r.NoRoute(container.RedirectHandler.Redirect)
+	}
 
 	// 2. 无需认证的路由组（公开接口）
 	public := r.Group("/api/v1")
@@ -21,11 +76,16 @@ func InitRouter(r *gin.Engine, container *bootstrap.Container) {
 		// 用户相关公开接口
 		public.POST("/register", container.UserHandler.UserRegister) // 用户注册
 		public.POST("/login", container.UserHandler.UserLogin)       // 用户登录
+
+		// 游客评论（需在配置中开启，提交前先获取工作量证明题目）
+		public.GET("/comment-challenge", container.CommentHandler.GuestChallenge)           // 获取题目
+		public.POST("/posts/:postID/comments", container.CommentHandler.CreateGuestComment) // 游客发表评论
 	}
 
 	// 3. 需要认证的路由组（需登录才能访问）
 	auth := r.Group("/api/v2")
-	auth.Use(middleware.JWTAuth()) // JWT 认证中间件：验证 token 有效性
+	auth.Use(middleware.JWTAuth())                                       // JWT 认证中间件：验证 token 有效性
+	auth.Use(middleware.UserLocale(container.UserHandler.GetUserRepo())) // 用户设置了语言偏好时，提示信息使用该语言
 	{
 		// 文章相关私有接口（需登录）
 		auth.POST("/posts", container.PostHandler.CreatePost)       // 创建文章
@@ -37,6 +97,40 @@ func InitRouter(r *gin.Engine, container *bootstrap.Container) {
 		// 评论相关私有接口（需登录）
 		auth.POST("/posts/:postID/comments", container.CommentHandler.CreateComment) // 发布评论
 		auth.GET("/comments/:postID", container.CommentHandler.CommentList)          // 文章的评论列表
+		auth.PUT("/comments/:id", container.CommentHandler.UpdateComment)            // 编辑评论
 		auth.DELETE("/comments/:id", container.CommentHandler.DeleteComment)         // 删除自己的评论
+		auth.GET("/comment-threads/:id", container.CommentHandler.CommentThread)     // 评论所在的整棵回复树
+
+		// 评论审核接口（需版主权限，权限在服务层校验）
+		auth.GET("/moderation/comments", container.CommentHandler.ModerationQueue)   // 审核队列
+		auth.POST("/moderation/comments", container.CommentHandler.ModerateComments) // 批量通过/拒绝
+
+		// 媒体库接口（需登录，只能管理自己上传的文件，管理员除外）
+		auth.POST("/media", container.MediaHandler.UploadMedia)       // 上传图片
+		auth.GET("/media", container.MediaHandler.ListMedia)          // 我的媒体列表
+		auth.GET("/media/:id", container.MediaHandler.MediaDetail)    // 媒体详情（含引用文章）
+		auth.DELETE("/media/:id", container.MediaHandler.DeleteMedia) // 删除媒体
+
+		// 回收站（作者管理自己删除的内容，管理员可管理全部，权限在服务层校验）
+		auth.GET("/trash/posts", container.TrashHandler.ListPosts)                      // 已删除的文章
+		auth.POST("/trash/posts/:id/restore", container.TrashHandler.RestorePost)       // 恢复文章（连同随文章删除的评论）
+		auth.DELETE("/trash/posts/:id", container.TrashHandler.PurgePost)               // 永久删除文章
+		auth.GET("/trash/comments", container.TrashHandler.ListComments)                // 单独删除的评论
+		auth.POST("/trash/comments/:id/restore", container.TrashHandler.RestoreComment) // 恢复评论
+		auth.DELETE("/trash/comments/:id", container.TrashHandler.PurgeComment)         // 永久删除评论
+
+		// 个人数据与账号注销
+		auth.POST("/me/export", container.AccountHandler.RequestExport)               // 申请导出个人数据（后台生成）
+		auth.GET("/me/exports/:id", container.AccountHandler.ExportStatus)            // 导出进度
+		auth.GET("/me/exports/:id/download", container.AccountHandler.DownloadExport) // 下载归档
+		auth.DELETE("/me", container.AccountHandler.DeleteAccount)                    // 申请注销账号（冷静期后删除）
+		auth.DELETE("/me/deletion", container.AccountHandler.CancelDeletion)          // 撤销注销申请
+		auth.PUT("/me/locale", container.AccountHandler.UpdateLocale)                 // 设置提示信息的语言偏好
+
+		// 管理接口（需管理员权限，权限在服务层校验）
+		auth.GET("/admin/export", container.BackupHandler.Export) // 下载整站数据归档
 	}
+
+	// 4. 接口文档：根据以上路由生成 OpenAPI 文档（/openapi.json）及交互式文档页面（/docs）
+	registerDocs(r)
 }